@@ -6,21 +6,21 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
-	"syscall"
+	"strings"
 	"time"
 
+	"teckbook-compass-backend/internal/domain/entity"
+	"teckbook-compass-backend/internal/domain/repository"
 	"teckbook-compass-backend/internal/infrastructure/config"
 	"teckbook-compass-backend/internal/infrastructure/database/postgres"
+	"teckbook-compass-backend/internal/infrastructure/database/recording"
 	"teckbook-compass-backend/internal/infrastructure/external"
 	"teckbook-compass-backend/internal/infrastructure/secrets"
 	"teckbook-compass-backend/internal/usecase"
 )
 
-const (
-	lockFilePath = "/tmp/teckbook-compass-batch.lock"
-)
-
 // ============================================================================
 // 型定義
 // ============================================================================
@@ -31,11 +31,16 @@ type BatchType string
 const (
 	BatchTypeArticle BatchType = "article" // 記事取得バッチ
 	BatchTypeAmazon  BatchType = "amazon"  // Amazon URL取得バッチ
+	BatchTypeHatena  BatchType = "hatena"  // はてなブックマーク数更新バッチ
+
+	BatchTypeRefreshBooks    BatchType = "refresh-books"    // 書籍情報再取得バッチ
+	BatchTypeRefreshArticles BatchType = "refresh-articles" // 記事情報再取得バッチ
 )
 
 // IsValid バッチタイプが有効かどうかを判定
 func (b BatchType) IsValid() bool {
-	return b == BatchTypeArticle || b == BatchTypeAmazon
+	return b == BatchTypeArticle || b == BatchTypeAmazon || b == BatchTypeHatena ||
+		b == BatchTypeRefreshBooks || b == BatchTypeRefreshArticles
 }
 
 // String バッチタイプの日本語名を返す
@@ -45,6 +50,12 @@ func (b BatchType) String() string {
 		return "記事取得バッチ"
 	case BatchTypeAmazon:
 		return "Amazon URL取得バッチ"
+	case BatchTypeHatena:
+		return "はてなブックマーク数更新バッチ"
+	case BatchTypeRefreshBooks:
+		return "書籍情報再取得バッチ"
+	case BatchTypeRefreshArticles:
+		return "記事情報再取得バッチ"
 	default:
 		return string(b)
 	}
@@ -52,24 +63,42 @@ func (b BatchType) String() string {
 
 // BatchParams バッチ実行パラメータ
 type BatchParams struct {
-	Type  BatchType // バッチの種類
-	Mode  string    // 取得モード ("new", "historical", "auto"/空)
-	Limit int       // 処理上限（amazonバッチ用）
+	Type   BatchType // バッチの種類
+	Mode   string    // 取得モード ("new", "historical", "auto"/空)
+	Limit  int       // 処理上限（amazon, hatena, refresh-books, refresh-articlesバッチ用）
+	DryRun bool      // データベースに書き込まず、書き込む内容を差分として返す
 }
 
 // NewBatchParamsFromEnv 環境変数からBatchParamsを生成
 func NewBatchParamsFromEnv() BatchParams {
+	batchType := BatchType(os.Getenv("BATCH_TYPE"))
+
+	limit := getAmazonLimitFromEnv()
+	switch batchType {
+	case BatchTypeHatena:
+		limit = getHatenaLimitFromEnv()
+	case BatchTypeRefreshBooks:
+		limit = getRefreshBooksLimitFromEnv()
+	case BatchTypeRefreshArticles:
+		limit = getRefreshArticlesLimitFromEnv()
+	}
+
 	return BatchParams{
-		Type:  BatchType(os.Getenv("BATCH_TYPE")),
-		Mode:  os.Getenv("FETCH_MODE"),
-		Limit: getAmazonLimitFromEnv(),
+		Type:   batchType,
+		Mode:   os.Getenv("FETCH_MODE"),
+		Limit:  limit,
+		DryRun: os.Getenv("DRY_RUN") == "true",
 	}
 }
 
 // BatchResult バッチ実行結果
 type BatchResult struct {
-	Success bool
-	Message string
+	Success   bool
+	Partial   bool            // 期限に近づいたため途中で終了した（残りは次回の実行で処理する）
+	Remaining int             // 期限に近づいたため処理しなかった件数
+	RunID     int64           // 実行履歴（batch_runs）のID（記録できなかった場合・ドライランの場合は0）
+	Diff      *recording.Diff // ドライランで記録した書き込みの差分（ドライランの場合のみ）
+	Message   string
 }
 
 // ============================================================================
@@ -80,7 +109,7 @@ type BatchResult struct {
 type App struct {
 	Config *config.Config
 	DB     *postgres.DB
-	lock   *BatchLock
+	lock   *postgres.BatchLock
 }
 
 // NewApp 新しいAppを作成（設定読み込み・DB接続）
@@ -107,25 +136,32 @@ func NewApp() (*App, error) {
 // Close リソースを解放
 func (a *App) Close() {
 	if a.lock != nil {
-		a.lock.Release()
+		if err := a.lock.Release(); err != nil {
+			log.Printf("Warning: 排他ロックの解放エラー: %v", err)
+		}
 	}
 	if a.DB != nil {
 		a.DB.Close()
 	}
 }
 
-// AcquireLock ロックを取得（エラー時はSlack通知）
-func (a *App) AcquireLock(skip bool) error {
-	lock, err := acquireLockInternal(skip)
+// AcquireLock バッチの種類ごとの排他ロックを取得（エラー時はSlack通知）
+// 同じ種類のバッチが実行中の場合はエラーを返す（保持者のリース期限が切れている場合は引き継ぐ）
+// ドライランはデータベースに書き込まないため、ロックを取得せず実行中のバッチと並行して実行できる
+func (a *App) AcquireLock(ctx context.Context, params BatchParams) error {
+	if params.DryRun {
+		log.Printf("ドライランのため排他ロックは取得しません (%s)", params.Type)
+		return nil
+	}
+
+	lock, err := postgres.AcquireBatchLock(ctx, a.DB.DB, string(params.Type), a.Config.Lock.Lease)
 	if err != nil {
 		a.notifyError("バッチ起動失敗", err.Error())
 		return err
 	}
 	a.lock = lock
 
-	if !skip {
-		log.Println("排他ロックを取得しました")
-	}
+	log.Printf("排他ロックを取得しました (%s)", params.Type)
 	return nil
 }
 
@@ -138,103 +174,211 @@ func (a *App) notifyError(title, message string) {
 }
 
 // ExecuteBatch パラメータに基づいてバッチを実行
-func (a *App) ExecuteBatch(params BatchParams) BatchResult {
+// ctx に期限（Lambdaの実行時間の上限等）がある場合、各バッチは期限から BATCH_DEADLINE_MARGIN_SECONDS 前に
+// 新しい処理を始めるのをやめ、途中までの結果を保存して終了する
+// ドライランの場合は取得・抽出・照合・スコア計算まで実行し、書き込みは記録のみ行って差分を返す
+// （Slack通知・実行履歴の記録も行わない）
+func (a *App) ExecuteBatch(ctx context.Context, params BatchParams) BatchResult {
 	// バッチタイプのバリデーション
 	if !params.Type.IsValid() {
-		errMsg := fmt.Sprintf("不明なバッチタイプ: %s (使用可能: article, amazon)", params.Type)
+		errMsg := fmt.Sprintf("不明なバッチタイプ: %s (使用可能: article, amazon, hatena, refresh-books, refresh-articles)", params.Type)
 		log.Println(errMsg)
 		return BatchResult{Success: false, Message: errMsg}
 	}
 
 	log.Printf("Starting %s...", params.Type.String())
 
+	// 排他ロックを失った場合（接続が切れた・リース期限切れで引き継がれた等）はバッチを中断する
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	if a.lock != nil {
+		go func() {
+			select {
+			case <-a.lock.Lost():
+				log.Println("Warning: 排他ロックを失ったためバッチを中断します")
+				cancel()
+			case <-ctx.Done():
+			}
+		}()
+	}
+
+	// 処理上限が指定されていない場合は環境変数（未設定の場合はデフォルト値）を使う
+	limit := params.Limit
+	if limit <= 0 {
+		switch params.Type {
+		case BatchTypeAmazon:
+			limit = getAmazonLimitFromEnv()
+		case BatchTypeHatena:
+			limit = getHatenaLimitFromEnv()
+		case BatchTypeRefreshBooks:
+			limit = getRefreshBooksLimitFromEnv()
+		case BatchTypeRefreshArticles:
+			limit = getRefreshArticlesLimitFromEnv()
+		}
+	}
+
+	// ドライランの場合は書き込みを記録するリポジトリを使い、Slack通知を無効にする
+	cfg := a.Config
+	var batchRepo repository.BatchRepository = postgres.NewBatchRepository(a.DB.DB)
+	var recorder *recording.BatchRepository
+	if params.DryRun {
+		log.Println("ドライラン: データベースへの書き込み・Slack通知・実行履歴の記録は行いません")
+		recorder = recording.NewBatchRepository(batchRepo)
+		batchRepo = recorder
+		dryRunConfig := *a.Config
+		dryRunConfig.Slack.Enabled = false
+		cfg = &dryRunConfig
+	}
+
+	record := startBatchRun(ctx, batchRepo, params, limit)
+
+	var remaining int
 	var err error
 	switch params.Type {
 	case BatchTypeArticle:
 		fetchMode := parseFetchMode(params.Mode)
-		err = runBatchProcess(a.Config, a.DB, fetchMode)
+		remaining, err = runBatchProcess(ctx, cfg, batchRepo, fetchMode, record)
 	case BatchTypeAmazon:
-		limit := params.Limit
-		if limit <= 0 {
-			limit = getAmazonLimitFromEnv()
-		}
-		err = runAmazonBatchProcess(a.Config, a.DB, limit)
+		remaining, err = runAmazonBatchProcess(ctx, cfg, batchRepo, limit, record)
+	case BatchTypeHatena:
+		remaining, err = runHatenaBatchProcess(ctx, cfg, batchRepo, limit, record)
+	case BatchTypeRefreshBooks:
+		remaining, err = runRefreshBooksBatchProcess(ctx, cfg, batchRepo, limit, record)
+	case BatchTypeRefreshArticles:
+		remaining, err = runRefreshArticlesBatchProcess(ctx, cfg, batchRepo, limit, record)
+	}
+
+	var diff *recording.Diff
+	if recorder != nil {
+		diff = recorder.Diff()
+		logDryRunDiff(diff)
 	}
 
 	if err != nil {
+		finishBatchRun(ctx, batchRepo, record, entity.BatchRunFailed, err)
 		return BatchResult{
 			Success: false,
+			RunID:   record.ID,
+			Diff:    diff,
 			Message: fmt.Sprintf("%s失敗: %v", params.Type.String(), err),
 		}
 	}
 
+	if remaining > 0 {
+		finishBatchRun(ctx, batchRepo, record, entity.BatchRunPartial, nil)
+		return BatchResult{
+			Success:   true,
+			Partial:   true,
+			Remaining: remaining,
+			RunID:     record.ID,
+			Diff:      diff,
+			Message:   fmt.Sprintf("%sは期限に近づいたため途中で終了しました（残り%d件は次回の実行で処理します）", params.Type.String(), remaining),
+		}
+	}
+
+	finishBatchRun(ctx, batchRepo, record, entity.BatchRunSucceeded, nil)
+	message := fmt.Sprintf("%sが完了しました", params.Type.String())
+	if params.DryRun {
+		message = fmt.Sprintf("%sのドライランが完了しました", params.Type.String())
+	}
 	return BatchResult{
 		Success: true,
-		Message: fmt.Sprintf("%sが完了しました", params.Type.String()),
+		RunID:   record.ID,
+		Diff:    diff,
+		Message: message,
 	}
 }
 
 // ============================================================================
-// ロック処理
+// 実行履歴
 // ============================================================================
 
-// BatchLock バッチの排他制御用ロック
-type BatchLock struct {
-	file *os.File
-}
-
-// acquireLockInternal ロックを取得（内部用）
-func acquireLockInternal(skip bool) (*BatchLock, error) {
-	if skip {
-		log.Println("ファイルロックをスキップします")
-		return &BatchLock{file: nil}, nil
+// startBatchRun 実行の開始を batch_runs に記録（記録できなくてもバッチは実行する）
+func startBatchRun(ctx context.Context, runs repository.BatchRunRepository, params BatchParams, limit int) *entity.BatchRun {
+	record := &entity.BatchRun{
+		BatchType: string(params.Type),
+		Mode:      params.Mode,
+		Params:    map[string]any{},
+		Counters:  map[string]int{},
+		Outcome:   entity.BatchRunRunning,
+		StartedAt: time.Now(),
 	}
-
-	// ロックファイルを開く（なければ作成）
-	file, err := os.OpenFile(lockFilePath, os.O_CREATE|os.O_RDWR, 0644)
-	if err != nil {
-		return nil, fmt.Errorf("failed to open lock file: %w", err)
+	if params.Type == BatchTypeArticle {
+		record.Params["mode"] = params.Mode
+	} else {
+		record.Params["limit"] = limit
 	}
 
-	// 排他ロックを取得（ノンブロッキング）
-	err = syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
-	if err != nil {
-		file.Close()
-		if err == syscall.EWOULDBLOCK {
-			return nil, fmt.Errorf("別のバッチプロセスが実行中です")
-		}
-		return nil, fmt.Errorf("failed to acquire lock: %w", err)
+	if err := runs.CreateBatchRun(ctx, record); err != nil {
+		log.Printf("Warning: 実行履歴の記録エラー: %v", err)
 	}
-
-	// PIDをファイルに書き込み（デバッグ用）
-	file.Truncate(0)
-	file.Seek(0, 0)
-	fmt.Fprintf(file, "%d\n", os.Getpid())
-
-	return &BatchLock{file: file}, nil
+	return record
 }
 
-// Release ロックを解放
-func (l *BatchLock) Release() error {
-	if l.file == nil {
-		return nil
+// finishBatchRun 実行結果を batch_runs に記録（中断された場合も記録する）
+func finishBatchRun(ctx context.Context, runs repository.BatchRunRepository, record *entity.BatchRun, outcome entity.BatchRunOutcome, err error) {
+	record.Finish(outcome, err, time.Now())
+	if record.ID == 0 {
+		return
 	}
+	if err := runs.FinishBatchRun(context.WithoutCancel(ctx), record); err != nil {
+		log.Printf("Warning: 実行履歴の記録エラー (ID: %d): %v", record.ID, err)
+		return
+	}
+	log.Printf("実行履歴を記録しました (ID: %d, %s)", record.ID, record.Outcome)
+}
+
+// ============================================================================
+// ドライラン
+// ============================================================================
 
-	// ロックを解放
-	if err := syscall.Flock(int(l.file.Fd()), syscall.LOCK_UN); err != nil {
-		return fmt.Errorf("failed to release lock: %w", err)
+// logDryRunDiff ドライランで記録した書き込みの差分を出力
+func logDryRunDiff(diff *recording.Diff) {
+	log.Println("===========================================")
+	log.Println("  ドライランの差分（データベースには書き込んでいません）")
+	log.Println("===========================================")
+	log.Printf("  新しい書籍:         %d\n", len(diff.NewBooks))
+	for _, book := range diff.NewBooks {
+		log.Printf("    %s %s（%s / %s）\n", book.ID, book.Title, book.Author, book.Publisher)
+	}
+	log.Printf("  新しい紐付け:       %d\n", len(diff.NewLinks))
+	for _, link := range diff.NewLinks {
+		log.Printf("    %s → %s（%s/%s, 確からしさ: %.2f, 極性: %s, %q）\n",
+			link.ArticleID, link.BookID, link.Origin, link.SourceType, link.Confidence, link.Sentiment, link.MatchedText)
+	}
+	if len(diff.RemovedLinks) > 0 {
+		log.Printf("  作り直す紐付け:     %d 記事\n", len(diff.RemovedLinks))
+		for _, removed := range diff.RemovedLinks {
+			log.Printf("    %s（削除前: %s）\n", removed.ArticleID, strings.Join(removed.BookIDs, ", "))
+		}
+	}
+	log.Printf("  スコアの変化:       %d 冊\n", len(diff.ScoreDeltas))
+	for _, delta := range diff.ScoreDeltas {
+		log.Printf("    %s %+.2f（%.2f → %.2f）%s\n", delta.BookID, delta.Delta, delta.Before, delta.After, delta.Title)
+	}
+	log.Printf("  カテゴリの振り分け: %d 冊\n", len(diff.CategoryAssignments))
+	for _, assignment := range diff.CategoryAssignments {
+		log.Printf("    %s: %s\n", assignment.BookID, strings.Join(assignment.CategoryIDs, ", "))
 	}
 
-	// ファイルを閉じる
-	if err := l.file.Close(); err != nil {
-		return fmt.Errorf("failed to close lock file: %w", err)
+	methods := make([]string, 0, len(diff.Writes))
+	for method := range diff.Writes {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+	log.Println("  書き込み回数:")
+	for _, method := range methods {
+		log.Printf("    %-36s %d\n", method, diff.Writes[method])
 	}
+	log.Println("===========================================")
+}
 
-	// ロックファイルを削除
-	os.Remove(lockFilePath)
+// ============================================================================
+// ロック処理
+// ============================================================================
 
-	return nil
-}
+// 排他制御はバッチの種類ごとのPostgreSQLのアドバイザリロック（postgres.BatchLock）で行う
+// CLI・Lambdaのどちらから起動しても、同じ種類のバッチは同時に1つしか実行されない
 
 // ============================================================================
 // Secrets Manager関連
@@ -272,6 +416,36 @@ func getAmazonLimitFromEnv() int {
 	return 50 // デフォルト値
 }
 
+// getHatenaLimitFromEnv 環境変数からはてなブックマーク数更新の処理上限を取得
+func getHatenaLimitFromEnv() int {
+	if limitStr := os.Getenv("HATENA_LIMIT"); limitStr != "" {
+		if limit, err := strconv.Atoi(limitStr); err == nil && limit > 0 {
+			return limit
+		}
+	}
+	return 500 // デフォルト値
+}
+
+// getRefreshBooksLimitFromEnv 環境変数から書籍情報再取得の処理上限を取得
+func getRefreshBooksLimitFromEnv() int {
+	if limitStr := os.Getenv("BOOK_REFRESH_LIMIT"); limitStr != "" {
+		if limit, err := strconv.Atoi(limitStr); err == nil && limit > 0 {
+			return limit
+		}
+	}
+	return 200 // デフォルト値
+}
+
+// getRefreshArticlesLimitFromEnv 環境変数から記事情報再取得の処理上限を取得
+func getRefreshArticlesLimitFromEnv() int {
+	if limitStr := os.Getenv("ARTICLE_REFRESH_LIMIT"); limitStr != "" {
+		if limit, err := strconv.Atoi(limitStr); err == nil && limit > 0 {
+			return limit
+		}
+	}
+	return 500 // デフォルト値
+}
+
 // getMigrationsPath マイグレーションファイルのパスを取得
 func getMigrationsPath() (string, error) {
 	if path := os.Getenv("MIGRATIONS_PATH"); path != "" {
@@ -290,23 +464,80 @@ func getMigrationsPath() (string, error) {
 // バッチ処理実行（既存ロジック維持）
 // ============================================================================
 
-// runBatchProcess 記事取得バッチ処理を実行
-func runBatchProcess(cfg *config.Config, db *postgres.DB, fetchMode *usecase.FetchModeOption) error {
+// newUsageLedger 外部APIの使用量の台帳を初期化し、当日の使用量を読み込む
+func newUsageLedger(ctx context.Context, cfg *config.Config, repo repository.APIUsageRepository) *external.UsageLedger {
+	ledger := external.NewUsageLedger(repo, cfg.Usage.DailyBudgets)
+	if err := ledger.Load(ctx); err != nil {
+		// 読み込めなくても使用量の記録と上限の判定は続ける（当日の他のバッチの分は含まれない）
+		log.Printf("Warning: APIの使用量の読み込みエラー: %v", err)
+	}
+	return ledger
+}
+
+// logAPIUsage 外部APIの当日の使用量を出力
+func logAPIUsage(usages []external.ProviderUsage) {
+	if len(usages) == 0 {
+		return
+	}
+	log.Println("  APIの本日の使用量:")
+	for _, line := range strings.Split(external.DescribeUsages(usages), "\n") {
+		log.Printf("  %s\n", line)
+	}
+}
+
+// newArticleSources 記事の取得元を初期化（Qiitaは常に有効、Zennは設定で有効化）
+func newArticleSources(cfg *config.Config, ledger *external.UsageLedger) []repository.ArticleSource {
+	sources := []repository.ArticleSource{external.NewQiitaClient(cfg.Qiita, ledger)}
+	if cfg.Zenn.Enabled {
+		sources = append(sources, external.NewZennClient(cfg.Zenn, ledger))
+		log.Println("Zennからの記事取得: 有効")
+	}
+	return sources
+}
+
+// newMetadataProviders 設定された順に書籍メタデータプロバイダを初期化
+func newMetadataProviders(cfg *config.Config, ledger *external.UsageLedger) []repository.BookMetadataProvider {
+	var providers []repository.BookMetadataProvider
+	var names []string
+	for _, name := range cfg.Metadata.Providers {
+		switch name {
+		case "rakuten":
+			providers = append(providers, external.NewRakutenClient(cfg.Rakuten, ledger))
+		case "openbd":
+			providers = append(providers, external.NewOpenBDClient(cfg.Metadata, ledger))
+		case "google_books":
+			providers = append(providers, external.NewGoogleBooksClient(cfg.Metadata, ledger))
+		case "ndl":
+			providers = append(providers, external.NewNDLClient(cfg.Metadata, ledger))
+		default:
+			log.Printf("Warning: 不明な書籍メタデータプロバイダを無視します: %s", name)
+			continue
+		}
+		names = append(names, name)
+	}
+	log.Printf("書籍メタデータプロバイダ: %s", strings.Join(names, " → "))
+	return providers
+}
+
+// runBatchProcess 記事取得バッチ処理を実行（戻り値は期限に近づいたため次回に持ち越した件数）
+func runBatchProcess(ctx context.Context, cfg *config.Config, batchRepo repository.BatchRepository, fetchMode *usecase.FetchModeOption, record *entity.BatchRun) (int, error) {
 	log.Println("===========================================")
 	log.Println("  TeckBook Compass Daily Batch")
 	log.Printf("  開始時刻: %s\n", time.Now().Format("2006-01-02 15:04:05"))
 	log.Println("===========================================")
 
-	// コンテキストを作成（タイムアウト付き）
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Hour)
+	// コンテキストを作成（タイムアウト付き、呼び出し元の期限の方が早い場合はそちらに従う）
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Hour)
 	defer cancel()
 
-	// リポジトリを初期化
-	batchRepo := postgres.NewBatchRepository(db.DB)
+	ledger := newUsageLedger(ctx, cfg, batchRepo)
+
+	// 記事の取得元を初期化
+	sources := newArticleSources(cfg, ledger)
 
 	// 外部APIクライアントを初期化
-	qiitaClient := external.NewQiitaClient(cfg.Qiita)
-	rakutenClient := external.NewRakutenClient(cfg.Rakuten)
+	metadataChain := usecase.NewBookMetadataChain(newMetadataProviders(cfg, ledger)...)
+	hatenaClient := external.NewHatenaClient(cfg.Hatena, ledger)
 	slackClient := external.NewSlackClient(cfg.Slack)
 
 	if slackClient.IsEnabled() {
@@ -315,13 +546,26 @@ func runBatchProcess(cfg *config.Config, db *postgres.DB, fetchMode *usecase.Fet
 		log.Println("Slack通知: 無効")
 	}
 
+	if hatenaClient.IsEnabled() {
+		log.Printf("はてなブックマーク数の取得: 有効（スコアの重み: %.2f）", cfg.Scoring.HatenaWeight)
+	}
+
+	if cfg.Comments.Enabled {
+		log.Printf("コメントからの書籍抽出: 有効（いいね%d以上の記事）", cfg.Comments.MinLikes)
+	}
+
 	// ユースケースを初期化
-	batchUsecase := usecase.NewBatchUsecase(batchRepo, qiitaClient, rakutenClient, slackClient)
+	batchUsecase := usecase.NewBatchUsecase(batchRepo, sources, metadataChain, hatenaClient, slackClient, cfg.Scoring, cfg.Comments, cfg.Crawl, cfg.Processing, ledger)
 
 	// バッチ処理を実行
 	result, err := batchUsecase.Run(ctx, fetchMode)
+	if result != nil {
+		record.Mode = result.FetchMode
+		record.Counters = result.Counters()
+		record.QueryStats = result.QueryRuns
+	}
 	if err != nil {
-		return fmt.Errorf("batch process error: %w", err)
+		return 0, fmt.Errorf("batch process error: %w", err)
 	}
 
 	// 結果を出力
@@ -331,21 +575,43 @@ func runBatchProcess(cfg *config.Config, db *postgres.DB, fetchMode *usecase.Fet
 	log.Printf("  取得モード:       %s\n", result.FetchMode)
 	log.Printf("  処理した記事数:   %d\n", result.ProcessedArticles)
 	log.Printf("  新規記事数:       %d\n", result.NewArticles)
+	log.Printf("  再抽出した記事数: %d\n", result.UpdatedArticles)
+	log.Printf("  未更新の記事数:   %d\n", result.UnchangedArticles)
+	log.Printf("  延期から再処理:   %d\n", result.RetriedArticles)
+	log.Printf("  延期した記事数:   %d\n", result.DeferredArticles)
+	if result.DeadlineReached {
+		log.Printf("  期限で持ち越し:   %d（次回の実行で再開）\n", result.RemainingArticles)
+	}
+	log.Printf("  記事処理:         %v（%.2f件/秒, ワーカー数: %d）\n", result.ProcessDuration.Round(time.Second), result.Throughput, result.Workers)
 	log.Printf("  処理した書籍数:   %d\n", result.ProcessedBooks)
 	log.Printf("  エラー数:         %d\n", result.Errors)
-	if result.NextPage > 0 {
-		log.Printf("  次回開始ページ:   %d\n", result.NextPage)
+	if len(result.Retries) > 0 {
+		clients := make([]string, 0, len(result.Retries))
+		for client := range result.Retries {
+			clients = append(clients, client)
+		}
+		sort.Strings(clients)
+		for _, client := range clients {
+			log.Printf("  リトライ回数:     %s %d 回\n", client, result.Retries[client])
+		}
+	}
+	for _, state := range result.CrawlStates {
+		log.Printf("  過去記事の進捗:   %s\n", state)
 	}
+	logAPIUsage(result.APIUsage)
 	log.Printf("  処理時間:         %v\n", result.EndTime.Sub(result.StartTime))
 	log.Println("===========================================")
 	log.Printf("  終了時刻: %s\n", time.Now().Format("2006-01-02 15:04:05"))
 	log.Println("===========================================")
 
-	return nil
+	if result.DeadlineReached {
+		return result.RemainingArticles, nil
+	}
+	return 0, nil
 }
 
-// runAmazonBatchProcess Amazon URL取得バッチ処理を実行
-func runAmazonBatchProcess(cfg *config.Config, db *postgres.DB, limit int) error {
+// runAmazonBatchProcess Amazon URL取得バッチ処理を実行（戻り値は期限に近づいたため次回に持ち越した件数）
+func runAmazonBatchProcess(ctx context.Context, cfg *config.Config, batchRepo repository.BatchRepository, limit int, record *entity.BatchRun) (int, error) {
 	log.Println("===========================================")
 	log.Println("  TeckBook Compass Amazon URL Fetch Batch")
 	log.Printf("  開始時刻: %s\n", time.Now().Format("2006-01-02 15:04:05"))
@@ -353,19 +619,18 @@ func runAmazonBatchProcess(cfg *config.Config, db *postgres.DB, limit int) error
 	log.Println("===========================================")
 
 	// コンテキストを作成（タイムアウト付き）
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Minute)
 	defer cancel()
 
-	// リポジトリを初期化
-	batchRepo := postgres.NewBatchRepository(db.DB)
+	ledger := newUsageLedger(ctx, cfg, batchRepo)
 
 	// 外部APIクライアントを初期化
-	amazonClient := external.NewAmazonClient(cfg.Amazon)
+	amazonClient := external.NewAmazonClient(cfg.Amazon, ledger)
 	slackClient := external.NewSlackClient(cfg.Slack)
 
 	if !amazonClient.IsEnabled() {
 		log.Println("Amazon API is disabled. Please set AMAZON_ENABLED=true in your environment.")
-		return fmt.Errorf("amazon api is disabled")
+		return 0, fmt.Errorf("amazon api is disabled")
 	}
 
 	if slackClient.IsEnabled() {
@@ -375,10 +640,13 @@ func runAmazonBatchProcess(cfg *config.Config, db *postgres.DB, limit int) error
 	}
 
 	// ユースケースを初期化
-	amazonBatchUsecase := usecase.NewAmazonBatchUsecase(batchRepo, amazonClient, slackClient)
+	amazonBatchUsecase := usecase.NewAmazonBatchUsecase(batchRepo, amazonClient, slackClient, cfg.Processing)
 
 	// バッチ処理を実行
 	result, err := amazonBatchUsecase.Run(ctx, limit)
+	if result != nil {
+		record.Counters = result.Counters()
+	}
 	if err != nil {
 		// エラーが発生しても結果は出力する
 		if result != nil {
@@ -390,10 +658,11 @@ func runAmazonBatchProcess(cfg *config.Config, db *postgres.DB, limit int) error
 			log.Printf("  未発見書籍数:     %d\n", result.NotFoundBooks)
 			log.Printf("  エラー数:         %d\n", result.Errors)
 			log.Printf("  エラー内容:       %s\n", result.ErrorMessage)
+			logAPIUsage([]external.ProviderUsage{result.APIUsage})
 			log.Printf("  処理時間:         %v\n", result.EndTime.Sub(result.StartTime))
 			log.Println("===========================================")
 		}
-		return err
+		return 0, err
 	}
 
 	// 結果を出力
@@ -403,11 +672,180 @@ func runAmazonBatchProcess(cfg *config.Config, db *postgres.DB, limit int) error
 	log.Printf("  処理した書籍数:   %d\n", result.ProcessedBooks)
 	log.Printf("  更新した書籍数:   %d\n", result.UpdatedBooks)
 	log.Printf("  未発見書籍数:     %d\n", result.NotFoundBooks)
+	log.Printf("  次回に持ち越し:   %d\n", result.SkippedBooks)
 	log.Printf("  エラー数:         %d\n", result.Errors)
+	if result.CircuitOpen {
+		log.Printf("  遮断により中断:   %s\n", result.ErrorMessage)
+	}
+	logAPIUsage([]external.ProviderUsage{result.APIUsage})
 	log.Printf("  処理時間:         %v\n", result.EndTime.Sub(result.StartTime))
 	log.Println("===========================================")
 	log.Printf("  終了時刻: %s\n", time.Now().Format("2006-01-02 15:04:05"))
 	log.Println("===========================================")
 
-	return nil
+	if result.DeadlineReached {
+		return result.SkippedBooks, nil
+	}
+	return 0, nil
+}
+
+// runHatenaBatchProcess はてなブックマーク数更新バッチ処理を実行（戻り値は期限に近づいたため次回に持ち越した件数）
+func runHatenaBatchProcess(ctx context.Context, cfg *config.Config, batchRepo repository.BatchRepository, limit int, record *entity.BatchRun) (int, error) {
+	log.Println("===========================================")
+	log.Println("  TeckBook Compass Hatena Bookmark Batch")
+	log.Printf("  開始時刻: %s\n", time.Now().Format("2006-01-02 15:04:05"))
+	log.Printf("  処理上限: %d 件\n", limit)
+	log.Println("===========================================")
+
+	// コンテキストを作成（タイムアウト付き）
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Minute)
+	defer cancel()
+
+	ledger := newUsageLedger(ctx, cfg, batchRepo)
+
+	// 外部APIクライアントを初期化
+	hatenaClient := external.NewHatenaClient(cfg.Hatena, ledger)
+	if !hatenaClient.IsEnabled() {
+		log.Println("Hatena Bookmark API is disabled. Please set HATENA_ENABLED=true in your environment.")
+		return 0, fmt.Errorf("hatena bookmark api is disabled")
+	}
+
+	// ユースケースを初期化
+	hatenaBatchUsecase := usecase.NewHatenaBatchUsecase(batchRepo, hatenaClient, cfg.Scoring, cfg.Processing)
+
+	// バッチ処理を実行
+	result, err := hatenaBatchUsecase.Run(ctx, limit)
+	if result != nil {
+		record.Counters = result.Counters()
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	// 結果を出力
+	log.Println("===========================================")
+	log.Println("  はてなブックマーク数更新バッチ結果")
+	log.Println("===========================================")
+	log.Printf("  処理した記事数:   %d\n", result.ProcessedArticles)
+	log.Printf("  ブックマーク有り: %d\n", result.BookmarkedArticles)
+	log.Printf("  スコア再計算:     %d\n", result.RecomputedBooks)
+	log.Printf("  次回に持ち越し:   %d\n", result.SkippedArticles)
+	log.Printf("  エラー数:         %d\n", result.Errors)
+	logAPIUsage(ledger.Usages())
+	log.Printf("  処理時間:         %v\n", result.EndTime.Sub(result.StartTime))
+	log.Println("===========================================")
+
+	if result.DeadlineReached {
+		return result.SkippedArticles, nil
+	}
+	return 0, nil
+}
+
+// runRefreshBooksBatchProcess 書籍情報再取得バッチ処理を実行（戻り値は期限に近づいたため次回に持ち越した件数）
+func runRefreshBooksBatchProcess(ctx context.Context, cfg *config.Config, batchRepo repository.BatchRepository, limit int, record *entity.BatchRun) (int, error) {
+	log.Println("===========================================")
+	log.Println("  TeckBook Compass Book Refresh Batch")
+	log.Printf("  開始時刻: %s\n", time.Now().Format("2006-01-02 15:04:05"))
+	log.Printf("  処理上限: %d 件\n", limit)
+	log.Println("===========================================")
+
+	// コンテキストを作成（タイムアウト付き）
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Minute)
+	defer cancel()
+
+	ledger := newUsageLedger(ctx, cfg, batchRepo)
+
+	// 外部APIクライアントを初期化
+	rakutenClient := external.NewRakutenClient(cfg.Rakuten, ledger)
+
+	// ユースケースを初期化
+	refreshUsecase := usecase.NewBookRefreshBatchUsecase(batchRepo, rakutenClient, cfg.Refresh, cfg.Processing)
+
+	// バッチ処理を実行
+	result, err := refreshUsecase.Run(ctx, limit)
+	if result != nil {
+		record.Counters = result.Counters()
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	// 結果を出力
+	log.Println("===========================================")
+	log.Println("  書籍情報再取得バッチ結果")
+	log.Println("===========================================")
+	log.Printf("  処理した書籍数:   %d\n", result.ProcessedBooks)
+	log.Printf("  更新した書籍数:   %d\n", result.UpdatedBooks)
+	log.Printf("  変更なし:         %d\n", result.UnchangedBooks)
+	log.Printf("  見つからない:     %d\n", result.NotFoundBooks)
+	log.Printf("  エラー数:         %d\n", result.Errors)
+	log.Printf("  次回に持ち越し:   %d\n", result.SkippedBooks)
+	log.Printf("  リトライ回数:     %d\n", result.Retries)
+	logAPIUsage([]external.ProviderUsage{result.APIUsage})
+	fields := make([]string, 0, len(result.FieldChanges))
+	for field := range result.FieldChanges {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	for _, field := range fields {
+		log.Printf("    %-14s %d 件変更\n", field, result.FieldChanges[field])
+	}
+	log.Printf("  処理時間:         %v\n", result.EndTime.Sub(result.StartTime))
+	log.Println("===========================================")
+
+	if result.DeadlineReached {
+		return result.SkippedBooks, nil
+	}
+	return 0, nil
+}
+
+// runRefreshArticlesBatchProcess 記事情報再取得バッチ処理を実行（戻り値は期限に近づいたため次回に持ち越した件数）
+func runRefreshArticlesBatchProcess(ctx context.Context, cfg *config.Config, batchRepo repository.BatchRepository, limit int, record *entity.BatchRun) (int, error) {
+	log.Println("===========================================")
+	log.Println("  TeckBook Compass Article Refresh Batch")
+	log.Printf("  開始時刻: %s\n", time.Now().Format("2006-01-02 15:04:05"))
+	log.Printf("  処理上限: %d 件（APIの予算: %d リクエスト）\n", limit, cfg.Refresh.ArticleAPIBudget)
+	log.Println("===========================================")
+
+	// コンテキストを作成（タイムアウト付き）
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Minute)
+	defer cancel()
+
+	ledger := newUsageLedger(ctx, cfg, batchRepo)
+
+	// ユースケースを初期化
+	refreshUsecase := usecase.NewArticleRefreshBatchUsecase(batchRepo, newArticleSources(cfg, ledger), cfg.Scoring, cfg.Refresh, cfg.Processing)
+
+	// バッチ処理を実行
+	result, err := refreshUsecase.Run(ctx, limit)
+	if result != nil {
+		record.Counters = result.Counters()
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	// 結果を出力
+	log.Println("===========================================")
+	log.Println("  記事情報再取得バッチ結果")
+	log.Println("===========================================")
+	log.Printf("  処理した記事数:   %d\n", result.ProcessedArticles)
+	log.Printf("  更新した記事数:   %d\n", result.UpdatedArticles)
+	log.Printf("  変更なし:         %d\n", result.UnchangedArticles)
+	log.Printf("  削除・非公開化:   %d\n", len(result.Tombstones))
+	for _, tombstone := range result.Tombstones {
+		log.Printf("    [%s] %s 紐付け書籍: %d 冊 (%s)\n", tombstone.Source, tombstone.ArticleID, len(tombstone.BookIDs), tombstone.Reason)
+	}
+	log.Printf("  スコア再計算:     %d 冊\n", result.RecomputedBooks)
+	log.Printf("  次回に持ち越し:   %d\n", result.SkippedArticles)
+	log.Printf("  APIリクエスト数:  %d\n", result.APICalls)
+	log.Printf("  エラー数:         %d\n", result.Errors)
+	logAPIUsage(ledger.Usages())
+	log.Printf("  処理時間:         %v\n", result.EndTime.Sub(result.StartTime))
+	log.Println("===========================================")
+
+	if result.DeadlineReached {
+		return result.SkippedArticles, nil
+	}
+	return 0, nil
 }
@@ -6,8 +6,11 @@ import (
 	"context"
 	"log"
 	"os"
+	"time"
 
 	"github.com/aws/aws-lambda-go/lambda"
+
+	"teckbook-compass-backend/internal/infrastructure/database/recording"
 )
 
 // ============================================================================
@@ -16,15 +19,20 @@ import (
 
 // LambdaEvent EventBridgeから受け取るイベント構造体
 type LambdaEvent struct {
-	Type  string `json:"type"`  // バッチの種類 ("article" or "amazon")
-	Mode  string `json:"mode"`  // 取得モード ("new", "historical", "auto") - articleバッチ用
-	Limit int    `json:"limit"` // 処理上限 - amazonバッチ用
+	Type   string `json:"type"`   // バッチの種類 ("article", "amazon", "hatena", "refresh-books" or "refresh-articles")
+	Mode   string `json:"mode"`   // 取得モード ("new", "historical", "auto") - articleバッチ用
+	Limit  int    `json:"limit"`  // 処理上限 - article以外のバッチ用
+	DryRun bool   `json:"dryRun"` // データベースに書き込まず、書き込む内容の差分を返す
 }
 
 // LambdaResponse Lambda用のレスポンス構造体
 type LambdaResponse struct {
-	Success bool   `json:"success"`
-	Message string `json:"message"`
+	Success   bool            `json:"success"`
+	Partial   bool            `json:"partial,omitempty"`   // 実行時間の上限に近づいたため途中で終了した
+	Remaining int             `json:"remaining,omitempty"` // 次回の実行に持ち越した件数
+	RunID     int64           `json:"run_id,omitempty"`    // 実行履歴（batch_runs）のID
+	Diff      *recording.Diff `json:"diff,omitempty"`      // ドライランで記録した書き込みの差分
+	Message   string          `json:"message"`
 }
 
 // ============================================================================
@@ -41,9 +49,11 @@ func main() {
 // ============================================================================
 
 // handleLambdaEvent Lambdaイベントをハンドリング
+// ctx の期限（Lambdaの実行時間の上限）から BATCH_DEADLINE_MARGIN_SECONDS 前に新しい処理を始めるのをやめ、
+// 途中までの結果を保存して部分的な成功（partial）として返す
 func handleLambdaEvent(ctx context.Context, event LambdaEvent) (LambdaResponse, error) {
-	log.Printf("Lambda event received: type=%s, mode=%s, limit=%d",
-		event.Type, event.Mode, event.Limit)
+	log.Printf("Lambda event received: type=%s, mode=%s, limit=%d, dryRun=%t",
+		event.Type, event.Mode, event.Limit, event.DryRun)
 
 	// イベントからパラメータを構築
 	params := buildBatchParams(event)
@@ -56,19 +66,30 @@ func handleLambdaEvent(ctx context.Context, event LambdaEvent) (LambdaResponse,
 	}
 	defer app.Close()
 
-	// Lambda環境ではファイルロックをスキップ
-	if err := app.AcquireLock(true); err != nil {
+	// 同じ種類のバッチの同時実行を防ぐ（EventBridgeの重複起動・CLIとの同時実行）
+	if err := app.AcquireLock(ctx, params); err != nil {
 		return LambdaResponse{Success: false, Message: err.Error()}, err
 	}
 
+	if deadline, ok := ctx.Deadline(); ok {
+		log.Printf("Lambdaの残り実行時間: %v", time.Until(deadline).Round(time.Second))
+	}
+
 	// バッチ実行
-	result := app.ExecuteBatch(params)
+	result := app.ExecuteBatch(ctx, params)
 
 	if !result.Success {
-		return LambdaResponse{Success: false, Message: result.Message}, nil
+		return LambdaResponse{Success: false, RunID: result.RunID, Diff: result.Diff, Message: result.Message}, nil
 	}
 
-	return LambdaResponse{Success: true, Message: result.Message}, nil
+	return LambdaResponse{
+		Success:   true,
+		Partial:   result.Partial,
+		Remaining: result.Remaining,
+		RunID:     result.RunID,
+		Diff:      result.Diff,
+		Message:   result.Message,
+	}, nil
 }
 
 // ============================================================================
@@ -87,9 +108,10 @@ func buildBatchParams(event LambdaEvent) BatchParams {
 	}
 
 	return BatchParams{
-		Type:  batchType,
-		Mode:  event.Mode,
-		Limit: event.Limit,
+		Type:   batchType,
+		Mode:   event.Mode,
+		Limit:  event.Limit,
+		DryRun: event.DryRun,
 	}
 }
 
@@ -3,11 +3,19 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
 	"flag"
 	"fmt"
 	"log"
 	"os"
+	"sort"
+	"strings"
+	"time"
 
+	"teckbook-compass-backend/internal/domain/entity"
+	"teckbook-compass-backend/internal/domain/repository"
+	"teckbook-compass-backend/internal/infrastructure/database/postgres"
 	"teckbook-compass-backend/internal/usecase"
 )
 
@@ -25,6 +33,46 @@ type cliFlags struct {
 	fetchHistorical  bool
 	runAmazonBatch   bool
 	amazonBatchLimit int
+	runHatenaBatch   bool
+	hatenaBatchLimit int
+	runRefreshBooks  bool
+	refreshLimit     int
+
+	runRefreshArticles  bool
+	refreshArticleLimit int
+
+	// スコアの作り直し用
+	recomputeScores bool
+
+	// 記事-書籍紐付けの管理用
+	listLinks         bool
+	linkSourceType    string
+	linkMinConfidence float64
+	linkMaxConfidence float64
+	linkBookID        string
+	linkArticleID     string
+	linkSentiment     string
+	linkOrigin        string
+	linkLimit         int
+
+	// 検索クエリの管理用
+	listQueries      bool
+	addQuery         string
+	enableQuery      int64
+	disableQuery     int64
+	setQueryPriority int64
+	querySource      string
+	queryPriority    int
+
+	// 実行履歴の表示用
+	history      bool
+	historyType  string
+	historyLimit int
+	showRun      int64
+	jsonOutput   bool
+
+	// ドライラン（バッチ共通）
+	dryRun bool
 }
 
 func parseFlags() *cliFlags {
@@ -39,6 +87,35 @@ func parseFlags() *cliFlags {
 	flag.BoolVar(&f.fetchHistorical, "fetch-historical", false, "Force fetch historical articles mode (use with -run-batch)")
 	flag.BoolVar(&f.runAmazonBatch, "run-amazon-batch", false, "Run Amazon URL fetch batch")
 	flag.IntVar(&f.amazonBatchLimit, "amazon-limit", 50, "Number of books to process in Amazon batch (default: 50)")
+	flag.BoolVar(&f.runHatenaBatch, "run-hatena-batch", false, "Run Hatena Bookmark count refresh batch")
+	flag.IntVar(&f.hatenaBatchLimit, "hatena-limit", 500, "Number of articles to process in Hatena batch (default: 500)")
+	flag.BoolVar(&f.runRefreshBooks, "run-refresh-books", false, "Refresh Rakuten rating, price, availability and thumbnail of stored books")
+	flag.IntVar(&f.refreshLimit, "refresh-books-limit", 200, "Number of books to process in refresh-books batch (default: 200)")
+	flag.BoolVar(&f.runRefreshArticles, "run-refresh-articles", false, "Refresh likes, stocks and comments of stored articles and detect deleted ones")
+	flag.IntVar(&f.refreshArticleLimit, "refresh-articles-limit", 500, "Number of articles to process in refresh-articles batch (default: 500)")
+	flag.BoolVar(&f.recomputeScores, "recompute-scores", false, "Rebuild daily scores of all books from stored article-book links")
+	flag.BoolVar(&f.listLinks, "list-links", false, "List article-book links with extraction provenance")
+	flag.StringVar(&f.linkSourceType, "link-source-type", "", "Filter links by source type (isbn13, isbn10, amazon_url, asin, title)")
+	flag.Float64Var(&f.linkMinConfidence, "link-min-confidence", -1, "Filter links with confidence >= value")
+	flag.Float64Var(&f.linkMaxConfidence, "link-max-confidence", -1, "Filter links with confidence <= value")
+	flag.StringVar(&f.linkBookID, "link-book", "", "Filter links by book ID (ISBN-13)")
+	flag.StringVar(&f.linkArticleID, "link-article", "", "Filter links by article ID")
+	flag.StringVar(&f.linkSentiment, "link-sentiment", "", "Filter links by mention sentiment (positive, neutral, negative)")
+	flag.StringVar(&f.linkOrigin, "link-origin", "", "Filter links by mention origin (body, comment)")
+	flag.IntVar(&f.linkLimit, "link-limit", 100, "Maximum number of links to list")
+	flag.BoolVar(&f.listQueries, "list-queries", false, "List search queries with their fetch and book-yield statistics")
+	flag.StringVar(&f.addQuery, "add-query", "", "Register a search query (use with -query-source and -query-priority)")
+	flag.Int64Var(&f.enableQuery, "enable-query", 0, "Enable the search query with the given ID")
+	flag.Int64Var(&f.disableQuery, "disable-query", 0, "Disable the search query with the given ID")
+	flag.Int64Var(&f.setQueryPriority, "set-query-priority", 0, "Change the priority of the search query with the given ID (use with -query-priority)")
+	flag.StringVar(&f.querySource, "query-source", "", "Article source of the search query (qiita, zenn). Defaults to qiita for -add-query")
+	flag.IntVar(&f.queryPriority, "query-priority", 0, "Priority of the search query (higher is fetched first)")
+	flag.BoolVar(&f.history, "history", false, "List recent batch runs with their outcome and counters")
+	flag.StringVar(&f.historyType, "history-type", "", "Filter batch runs by batch type (article, amazon, hatena, refresh-books, refresh-articles)")
+	flag.IntVar(&f.historyLimit, "history-limit", 20, "Maximum number of batch runs to list")
+	flag.Int64Var(&f.showRun, "show-run", 0, "Show the report of the batch run with the given ID")
+	flag.BoolVar(&f.jsonOutput, "json", false, "Output -history, -show-run and the -dry-run diff as JSON")
+	flag.BoolVar(&f.dryRun, "dry-run", false, "Run a batch without writing to the database and report the diff it would apply (use with -run-*)")
 
 	flag.Parse()
 	return f
@@ -73,7 +150,31 @@ func main() {
 		runArticleBatch(flags)
 
 	case flags.runAmazonBatch:
-		runAmazonBatch(flags.amazonBatchLimit)
+		runAmazonBatch(flags)
+
+	case flags.runHatenaBatch:
+		runHatenaBatch(flags)
+
+	case flags.runRefreshBooks:
+		runRefreshBooksBatch(flags)
+
+	case flags.runRefreshArticles:
+		runRefreshArticlesBatch(flags)
+
+	case flags.recomputeScores:
+		runRecomputeScores()
+
+	case flags.listLinks:
+		runListLinks(flags)
+
+	case flags.listQueries, flags.addQuery != "", flags.enableQuery > 0, flags.disableQuery > 0, flags.setQueryPriority > 0:
+		runManageQueries(flags)
+
+	case flags.history:
+		runShowHistory(flags)
+
+	case flags.showRun > 0:
+		runShowRun(flags)
 
 	default:
 		printUsage()
@@ -146,19 +247,21 @@ func runArticleBatch(flags *cliFlags) {
 	}
 	defer app.Close()
 
+	// 取得モードを決定
+	params := BatchParams{
+		Type:   BatchTypeArticle,
+		Mode:   determineFetchMode(flags),
+		DryRun: flags.dryRun,
+	}
+
 	// 排他ロック取得
-	if err := app.AcquireLock(false); err != nil {
+	if err := app.AcquireLock(context.Background(), params); err != nil {
 		log.Fatalf("ロック取得失敗: %v", err)
 	}
 
-	// 取得モードを決定
-	mode := determineFetchMode(flags)
-
 	// バッチ実行
-	result := app.ExecuteBatch(BatchParams{
-		Type: BatchTypeArticle,
-		Mode: mode,
-	})
+	result := app.ExecuteBatch(context.Background(), params)
+	printDryRunDiff(flags, result)
 
 	if !result.Success {
 		log.Fatalf("Batch process failed: %s", result.Message)
@@ -166,29 +269,374 @@ func runArticleBatch(flags *cliFlags) {
 }
 
 // runAmazonBatch Amazon URL取得バッチ実行
-func runAmazonBatch(limit int) {
+func runAmazonBatch(flags *cliFlags) {
 	app, err := NewApp()
 	if err != nil {
 		log.Fatalf("初期化失敗: %v", err)
 	}
 	defer app.Close()
 
+	params := BatchParams{
+		Type:   BatchTypeAmazon,
+		Limit:  flags.amazonBatchLimit,
+		DryRun: flags.dryRun,
+	}
+
 	// 排他ロック取得
-	if err := app.AcquireLock(false); err != nil {
+	if err := app.AcquireLock(context.Background(), params); err != nil {
 		log.Fatalf("ロック取得失敗: %v", err)
 	}
 
 	// バッチ実行
-	result := app.ExecuteBatch(BatchParams{
-		Type:  BatchTypeAmazon,
-		Limit: limit,
-	})
+	result := app.ExecuteBatch(context.Background(), params)
+	printDryRunDiff(flags, result)
 
 	if !result.Success {
 		log.Fatalf("Amazon batch process failed: %s", result.Message)
 	}
 }
 
+// runHatenaBatch はてなブックマーク数更新バッチ実行
+func runHatenaBatch(flags *cliFlags) {
+	app, err := NewApp()
+	if err != nil {
+		log.Fatalf("初期化失敗: %v", err)
+	}
+	defer app.Close()
+
+	params := BatchParams{
+		Type:   BatchTypeHatena,
+		Limit:  flags.hatenaBatchLimit,
+		DryRun: flags.dryRun,
+	}
+
+	// 排他ロック取得
+	if err := app.AcquireLock(context.Background(), params); err != nil {
+		log.Fatalf("ロック取得失敗: %v", err)
+	}
+
+	// バッチ実行
+	result := app.ExecuteBatch(context.Background(), params)
+	printDryRunDiff(flags, result)
+
+	if !result.Success {
+		log.Fatalf("Hatena batch process failed: %s", result.Message)
+	}
+}
+
+// runRefreshBooksBatch 書籍情報再取得バッチ実行
+func runRefreshBooksBatch(flags *cliFlags) {
+	app, err := NewApp()
+	if err != nil {
+		log.Fatalf("初期化失敗: %v", err)
+	}
+	defer app.Close()
+
+	params := BatchParams{
+		Type:   BatchTypeRefreshBooks,
+		Limit:  flags.refreshLimit,
+		DryRun: flags.dryRun,
+	}
+
+	// 排他ロック取得
+	if err := app.AcquireLock(context.Background(), params); err != nil {
+		log.Fatalf("ロック取得失敗: %v", err)
+	}
+
+	// バッチ実行
+	result := app.ExecuteBatch(context.Background(), params)
+	printDryRunDiff(flags, result)
+
+	if !result.Success {
+		log.Fatalf("Refresh-books batch process failed: %s", result.Message)
+	}
+}
+
+// runRefreshArticlesBatch 記事情報再取得バッチ実行
+func runRefreshArticlesBatch(flags *cliFlags) {
+	app, err := NewApp()
+	if err != nil {
+		log.Fatalf("初期化失敗: %v", err)
+	}
+	defer app.Close()
+
+	params := BatchParams{
+		Type:   BatchTypeRefreshArticles,
+		Limit:  flags.refreshArticleLimit,
+		DryRun: flags.dryRun,
+	}
+
+	// 排他ロック取得
+	if err := app.AcquireLock(context.Background(), params); err != nil {
+		log.Fatalf("ロック取得失敗: %v", err)
+	}
+
+	// バッチ実行
+	result := app.ExecuteBatch(context.Background(), params)
+	printDryRunDiff(flags, result)
+
+	if !result.Success {
+		log.Fatalf("Refresh-articles batch process failed: %s", result.Message)
+	}
+}
+
+// runRecomputeScores すべての書籍の日次スコアを保存済みの記事-書籍の紐付けから作り直す
+// 記事取得バッチと同時にスコアを書き込まないよう、記事取得バッチの排他ロックを取得して実行する
+func runRecomputeScores() {
+	app, err := NewApp()
+	if err != nil {
+		log.Fatalf("初期化失敗: %v", err)
+	}
+	defer app.Close()
+
+	ctx := context.Background()
+	if err := app.AcquireLock(ctx, BatchParams{Type: BatchTypeArticle}); err != nil {
+		log.Fatalf("ロック取得失敗: %v", err)
+	}
+
+	batchRepo := postgres.NewBatchRepository(app.DB.DB)
+	recomputer := usecase.NewScoreRecomputer(batchRepo, app.Config.Scoring)
+	recomputed, err := recomputer.RecomputeAll(ctx)
+	if err != nil {
+		log.Fatalf("スコアの再計算に失敗しました: %v", err)
+	}
+	log.Printf("書籍スコアを再計算しました: %d冊", recomputed)
+}
+
+// runListLinks 記事-書籍の紐付けを抽出元の情報で絞り込んで表示
+func runListLinks(flags *cliFlags) {
+	app, err := NewApp()
+	if err != nil {
+		log.Fatalf("初期化失敗: %v", err)
+	}
+	defer app.Close()
+
+	filter := repository.ArticleBookFilter{
+		SourceType: flags.linkSourceType,
+		BookID:     flags.linkBookID,
+		ArticleID:  flags.linkArticleID,
+		Sentiment:  flags.linkSentiment,
+		Origin:     flags.linkOrigin,
+		Limit:      flags.linkLimit,
+	}
+	if flags.linkMinConfidence >= 0 {
+		filter.MinConfidence = &flags.linkMinConfidence
+	}
+	if flags.linkMaxConfidence >= 0 {
+		filter.MaxConfidence = &flags.linkMaxConfidence
+	}
+
+	batchRepo := postgres.NewBatchRepository(app.DB.DB)
+	links, err := batchRepo.GetArticleBooks(context.Background(), filter)
+	if err != nil {
+		log.Fatalf("紐付けの取得に失敗しました: %v", err)
+	}
+
+	for _, link := range links {
+		fmt.Printf("%s\t%s\t%-7s\t%-10s\t%.2f\t%-8s\t%s\t%s\n",
+			link.ArticleID, link.BookID, link.Origin, link.SourceType, link.Confidence, link.Sentiment, link.MatchedText, link.Snippet)
+	}
+	fmt.Printf("%d件\n", len(links))
+}
+
+// runManageQueries 検索クエリの登録・有効化・無効化・優先度変更と、取得実績の一覧表示
+func runManageQueries(flags *cliFlags) {
+	app, err := NewApp()
+	if err != nil {
+		log.Fatalf("初期化失敗: %v", err)
+	}
+	defer app.Close()
+
+	ctx := context.Background()
+	batchRepo := postgres.NewBatchRepository(app.DB.DB)
+
+	if flags.querySource != "" && flags.querySource != entity.SourceQiita && flags.querySource != entity.SourceZenn {
+		log.Fatalf("不明な取得元です: %s", flags.querySource)
+	}
+
+	switch {
+	case flags.addQuery != "":
+		query := &entity.SearchQuery{
+			Source:   flags.querySource,
+			Query:    flags.addQuery,
+			Priority: flags.queryPriority,
+		}
+		if query.Source == "" {
+			query.Source = entity.SourceQiita
+		}
+		if err := batchRepo.AddSearchQuery(ctx, query); err != nil {
+			log.Fatalf("検索クエリの登録に失敗しました: %v", err)
+		}
+		log.Printf("検索クエリを登録しました: ID=%d [%s] %s（優先度: %d）", query.ID, query.Source, query.Query, query.Priority)
+
+	case flags.enableQuery > 0:
+		if err := batchRepo.SetSearchQueryEnabled(ctx, flags.enableQuery, true); err != nil {
+			log.Fatalf("検索クエリの有効化に失敗しました: %v", err)
+		}
+		log.Printf("検索クエリを有効化しました: ID=%d", flags.enableQuery)
+
+	case flags.disableQuery > 0:
+		if err := batchRepo.SetSearchQueryEnabled(ctx, flags.disableQuery, false); err != nil {
+			log.Fatalf("検索クエリの無効化に失敗しました: %v", err)
+		}
+		log.Printf("検索クエリを無効化しました: ID=%d", flags.disableQuery)
+
+	case flags.setQueryPriority > 0:
+		if err := batchRepo.SetSearchQueryPriority(ctx, flags.setQueryPriority, flags.queryPriority); err != nil {
+			log.Fatalf("検索クエリの優先度の変更に失敗しました: %v", err)
+		}
+		log.Printf("検索クエリの優先度を変更しました: ID=%d, 優先度: %d", flags.setQueryPriority, flags.queryPriority)
+	}
+
+	stats, err := batchRepo.GetSearchQueryStats(ctx, flags.querySource)
+	if err != nil {
+		log.Fatalf("検索クエリの取得に失敗しました: %v", err)
+	}
+
+	fmt.Println("ID\t取得元\t状態\t優先度\t実行回数\t取得\t新規\t重複\t処理記事\t書籍あり\t書籍/記事\t最終実行\tクエリ")
+	for _, s := range stats {
+		status := "有効"
+		if !s.Query.Enabled {
+			status = "無効"
+		}
+		lastRunAt := "-"
+		if s.LastRunAt != nil {
+			lastRunAt = s.LastRunAt.Format("2006-01-02 15:04")
+		}
+		fmt.Printf("%d\t%s\t%s\t%d\t%d\t%d\t%d\t%d\t%d\t%d\t%.2f\t%s\t%s\n",
+			s.Query.ID, s.Query.Source, status, s.Query.Priority, s.Runs,
+			s.Total.Fetched, s.Total.New, s.Total.Duplicates,
+			s.Total.ArticlesProcessed, s.Total.ArticlesWithBooks, s.Total.BooksPerArticle(),
+			lastRunAt, s.Query.Query)
+	}
+	fmt.Printf("%d件\n", len(stats))
+}
+
+// runShowHistory バッチの実行履歴を新しい順に表示
+func runShowHistory(flags *cliFlags) {
+	app, err := NewApp()
+	if err != nil {
+		log.Fatalf("初期化失敗: %v", err)
+	}
+	defer app.Close()
+
+	batchRepo := postgres.NewBatchRepository(app.DB.DB)
+	runs, err := batchRepo.ListBatchRuns(context.Background(), flags.historyType, flags.historyLimit)
+	if err != nil {
+		log.Fatalf("実行履歴の取得に失敗しました: %v", err)
+	}
+
+	if flags.jsonOutput {
+		if runs == nil {
+			runs = []*entity.BatchRun{}
+		}
+		printJSON(runs)
+		return
+	}
+
+	fmt.Println("ID\t種類\t結果\t開始\t処理時間\t件数")
+	for _, run := range runs {
+		fmt.Printf("%d\t%s\t%s\t%s\t%s\t%s\n",
+			run.ID, run.BatchType, run.Outcome, run.StartedAt.Format("2006-01-02 15:04"),
+			formatRunDuration(run), formatCounters(run))
+	}
+	fmt.Printf("%d件\n", len(runs))
+}
+
+// runShowRun バッチの実行履歴1件のレポートを表示
+func runShowRun(flags *cliFlags) {
+	app, err := NewApp()
+	if err != nil {
+		log.Fatalf("初期化失敗: %v", err)
+	}
+	defer app.Close()
+
+	batchRepo := postgres.NewBatchRepository(app.DB.DB)
+	run, err := batchRepo.GetBatchRun(context.Background(), flags.showRun)
+	if err != nil {
+		log.Fatalf("実行履歴の取得に失敗しました: %v", err)
+	}
+	if run == nil {
+		log.Fatalf("実行履歴が見つかりません: ID=%d", flags.showRun)
+	}
+
+	if flags.jsonOutput {
+		printJSON(run)
+		return
+	}
+
+	fmt.Printf("実行ID:       %d\n", run.ID)
+	fmt.Printf("種類:         %s（%s）\n", run.BatchType, BatchType(run.BatchType).String())
+	if run.Mode != "" {
+		fmt.Printf("取得モード:   %s\n", run.Mode)
+	}
+	params := make([]string, 0, len(run.Params))
+	for name, value := range run.Params {
+		params = append(params, fmt.Sprintf("%s=%v", name, value))
+	}
+	sort.Strings(params)
+	fmt.Printf("パラメータ:   %s\n", strings.Join(params, " "))
+	fmt.Printf("結果:         %s\n", run.Outcome)
+	fmt.Printf("開始:         %s\n", run.StartedAt.Format("2006-01-02 15:04:05"))
+	if run.FinishedAt != nil {
+		fmt.Printf("終了:         %s\n", run.FinishedAt.Format("2006-01-02 15:04:05"))
+	}
+	fmt.Printf("処理時間:     %s\n", formatRunDuration(run))
+	if run.ErrorSummary != "" {
+		fmt.Printf("エラー:       %s\n", run.ErrorSummary)
+	}
+
+	fmt.Println("件数:")
+	for _, name := range run.CounterNames() {
+		fmt.Printf("  %-22s %d\n", name, run.Counters[name])
+	}
+
+	if len(run.QueryStats) > 0 {
+		fmt.Println("検索クエリごとの取得実績:")
+		fmt.Println("  取得元\tモード\t取得\t新規\t重複\t処理記事\t書籍あり\t書籍/記事\tクエリ")
+		for _, q := range run.QueryStats {
+			fmt.Printf("  %s\t%s\t%d\t%d\t%d\t%d\t%d\t%.2f\t%s\n",
+				q.Source, q.FetchMode, q.Fetched, q.New, q.Duplicates,
+				q.ArticlesProcessed, q.ArticlesWithBooks, q.BooksPerArticle(), q.Query)
+		}
+	}
+}
+
+// formatRunDuration 実行履歴の処理時間（実行中の場合は "-"）
+func formatRunDuration(run *entity.BatchRun) string {
+	if run.FinishedAt == nil {
+		return "-"
+	}
+	return (time.Duration(run.DurationMs) * time.Millisecond).Round(time.Second).String()
+}
+
+// formatCounters 実行履歴の件数を名前順に1行で表す（0件は省略）
+func formatCounters(run *entity.BatchRun) string {
+	var parts []string
+	for _, name := range run.CounterNames() {
+		if count := run.Counters[name]; count != 0 {
+			parts = append(parts, fmt.Sprintf("%s=%d", name, count))
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// printDryRunDiff ドライランの差分をJSONで標準出力に書き出す（-dry-run と -json を指定した場合のみ）
+func printDryRunDiff(flags *cliFlags, result BatchResult) {
+	if flags.jsonOutput && result.Diff != nil {
+		printJSON(result.Diff)
+	}
+}
+
+// printJSON 値をインデント付きのJSONで標準出力に書き出す
+func printJSON(v any) {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		log.Fatalf("JSONの出力に失敗しました: %v", err)
+	}
+}
+
 // runBatchByEnvVar 環境変数からバッチを実行
 func runBatchByEnvVar() {
 	params := NewBatchParamsFromEnv()
@@ -201,12 +649,12 @@ func runBatchByEnvVar() {
 	defer app.Close()
 
 	// 排他ロック取得
-	if err := app.AcquireLock(false); err != nil {
+	if err := app.AcquireLock(context.Background(), params); err != nil {
 		log.Fatalf("ロック取得失敗: %v", err)
 	}
 
 	// バッチ実行
-	result := app.ExecuteBatch(params)
+	result := app.ExecuteBatch(context.Background(), params)
 
 	if !result.Success {
 		log.Fatalf("Batch process failed: %s", result.Message)
@@ -243,10 +691,62 @@ func printUsage() {
 	fmt.Println("  -fetch-historical  Force fetch historical articles mode (use with -run-batch)")
 	fmt.Println("  -run-amazon-batch  Run Amazon URL fetch batch")
 	fmt.Println("  -amazon-limit      Number of books to process in Amazon batch (default: 50)")
+	fmt.Println("  -run-hatena-batch  Refresh Hatena Bookmark counts of stored articles")
+	fmt.Println("  -hatena-limit      Number of articles to process in Hatena batch (default: 500)")
+	fmt.Println("  -run-refresh-books Refresh Rakuten rating, price, availability and thumbnail of stored books")
+	fmt.Println("  -refresh-books-limit  Number of books to process in refresh-books batch (default: 200)")
+	fmt.Println("  -run-refresh-articles Refresh likes, stocks and comments of stored articles and detect deleted ones")
+	fmt.Println("  -refresh-articles-limit  Number of articles to process in refresh-articles batch (default: 500)")
+	fmt.Println("  -recompute-scores  Rebuild daily scores of all books from stored article-book links")
+	fmt.Println("  -dry-run           Run any -run-* batch without writing to the database and report new books, links,")
+	fmt.Println("                     score deltas and category assignments (-json for JSON)")
+	fmt.Println("  -list-links        List article-book links (filter with -link-source-type, -link-min-confidence,")
+	fmt.Println("                     -link-max-confidence, -link-book, -link-article, -link-sentiment, -link-origin,")
+	fmt.Println("                     -link-limit)")
+	fmt.Println("  -list-queries      List search queries with fetch and book-yield statistics (filter with -query-source)")
+	fmt.Println("  -add-query         Register a search query, e.g. -add-query \"tag:book\" -query-source qiita -query-priority 10")
+	fmt.Println("  -enable-query      Enable the search query with the given ID")
+	fmt.Println("  -disable-query     Disable the search query with the given ID")
+	fmt.Println("  -set-query-priority  Change the priority of the search query with the given ID (use with -query-priority)")
+	fmt.Println("  -history           List recent batch runs (filter with -history-type, -history-limit; -json for JSON)")
+	fmt.Println("  -show-run          Show the report of the batch run with the given ID (-json for JSON)")
 	fmt.Println("\nEnvironment variables:")
-	fmt.Println("  BATCH_TYPE=article|amazon  Run batch directly without flags")
+	fmt.Println("  BATCH_TYPE=article|amazon|hatena|refresh-books|refresh-articles  Run batch directly without flags")
 	fmt.Println("  FETCH_MODE=new|historical  Fetch mode for article batch")
+	fmt.Println("  DRY_RUN=true               Run the batch without writing to the database (with BATCH_TYPE)")
 	fmt.Println("  AMAZON_LIMIT=50            Limit for amazon batch")
+	fmt.Println("  HATENA_LIMIT=500           Limit for hatena batch")
+	fmt.Println("  BOOK_REFRESH_LIMIT=200     Limit for refresh-books batch")
+	fmt.Println("  BOOK_REFRESH_MIN_AGE_DAYS=7  Only refresh books not refreshed for this many days")
+	fmt.Println("  ARTICLE_REFRESH_LIMIT=500  Limit for refresh-articles batch")
+	fmt.Println("  ARTICLE_REFRESH_MIN_AGE_DAYS=3  Only refresh articles not updated for this many days")
+	fmt.Println("  ARTICLE_REFRESH_API_BUDGET=300  Maximum source API requests per refresh-articles run")
+	fmt.Println("  HATENA_ENABLED=true        Fetch Hatena Bookmark counts for articles")
+	fmt.Println("  SCORE_HATENA_WEIGHT=0.5    Weight applied to Hatena Bookmark counts when scoring")
+	fmt.Println("  SCORE_MIN_CONFIDENCE=0.5   Ignore links below this confidence when scoring")
+	fmt.Println("  SCORE_NEGATIVE_WEIGHT=0.5  Weight applied to negative mentions when scoring")
+	fmt.Println("  SCORE_COMMENT_WEIGHT=0.5   Weight applied to mentions found in comments")
+	fmt.Println("  QIITA_COMMENTS_ENABLED=true  Extract books from comments of popular articles")
+	fmt.Println("  QIITA_COMMENTS_MIN_LIKES=20  Minimum likes for an article's comments to be fetched")
+	fmt.Println("  QIITA_HISTORICAL_WINDOW_DAYS=30  Date window size for historical crawling")
+	fmt.Println("  QIITA_HISTORICAL_FLOOR=2011-09-01  Oldest date historical crawling goes back to")
+	fmt.Println("  CRAWL_NEW_FETCH_OVERLAP_HOURS=24  Overlap with the previous new-article fetch window")
+	fmt.Println("  CRAWL_HISTORICAL_QUERIES_PER_RUN=3  Limit historical crawling to the highest-yield queries")
+	fmt.Println("  BATCH_WORKERS=4            Number of workers processing articles concurrently")
+	fmt.Println("  QIITA_RATE_LIMIT=1         Max Qiita API requests per second (also ZENN_/RAKUTEN_/AMAZON_/HATENA_RATE_LIMIT)")
+	fmt.Println("  CIRCUIT_FAILURE_THRESHOLD=5  Consecutive failures before an external API client stops sending requests")
+	fmt.Println("  CIRCUIT_COOLDOWN_SECONDS=60  Seconds before a tripped client retries")
+	fmt.Println("  RETRY_QUEUE_LIMIT=50       Deferred articles reprocessed per run")
+	fmt.Println("  BATCH_CHECKPOINT_INTERVAL=20  Articles processed between checkpoints; interrupted runs resume (0 disables)")
+	fmt.Println("  BATCH_DEADLINE_MARGIN_SECONDS=60  Stop taking new work this long before the deadline")
+	fmt.Println("  BATCH_LOCK_LEASE_SECONDS=60  Lease for the per-batch-type lock (renewed every third)")
+	fmt.Println("  LOOKUP_RECHECK_HOURS=24    Hours before re-checking an ISBN/title that was not found (doubles per miss)")
+	fmt.Println("  LOOKUP_RECHECK_MAX_DAYS=30  Upper bound for the re-check interval")
+	fmt.Println("  API_DAILY_BUDGETS=amazon:8640,rakuten:5000  Daily call budget per external API provider")
+	fmt.Println("  ZENN_ENABLED=true          Also fetch articles from Zenn")
+	fmt.Println("  SCORE_SOURCE_WEIGHTS=qiita:1.0,zenn:0.8  Weight applied per article source when scoring")
+	fmt.Println("  BOOK_METADATA_PROVIDERS=rakuten,openbd,google_books,ndl  Book metadata providers in fallback order")
+	fmt.Println("  GOOGLE_BOOKS_API_KEY=...   API key for Google Books (optional)")
 	os.Exit(1)
 }
 
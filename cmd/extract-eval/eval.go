@@ -0,0 +1,293 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"teckbook-compass-backend/internal/infrastructure/extractor"
+)
+
+// ============================================================================
+// 型定義
+// ============================================================================
+
+// ExpectedBook フィクスチャに記載された正解の書籍
+// ISBN / ASIN / Title のいずれか1つを指定する
+type ExpectedBook struct {
+	ISBN       string `json:"isbn,omitempty"`
+	ASIN       string `json:"asin,omitempty"`
+	Title      string `json:"title,omitempty"`
+	SourceType string `json:"sourceType"` // 期待する抽出元の種類
+}
+
+// FixtureLabel フィクスチャのラベルファイル（<name>.json）
+type FixtureLabel struct {
+	Expected []ExpectedBook `json:"expected"`
+}
+
+// Fixture 評価用フィクスチャ（Markdown本文 + 正解ラベル）
+type Fixture struct {
+	Name     string
+	Markdown string
+	Label    FixtureLabel
+}
+
+// Metrics 適合率・再現率
+type Metrics struct {
+	TruePositives  int     `json:"truePositives"`  // 正解と一致した抽出数
+	Predicted      int     `json:"predicted"`      // 抽出数
+	Found          int     `json:"found"`          // 抽出できた正解数
+	Expected       int     `json:"expected"`       // 正解数
+	Precision      float64 `json:"precision"`      // 適合率
+	Recall         float64 `json:"recall"`         // 再現率
+	FalsePositives int     `json:"falsePositives"` // 誤抽出数
+}
+
+// FixtureDiff フィクスチャごとの差分
+type FixtureDiff struct {
+	Name       string   `json:"name"`
+	Missing    []string `json:"missing,omitempty"`    // 抽出できなかった正解
+	Unexpected []string `json:"unexpected,omitempty"` // 正解にない抽出結果
+	Mismatched []string `json:"mismatched,omitempty"` // 正解と一致したが期待と異なる抽出元で抽出された結果
+}
+
+// Report 評価結果
+type Report struct {
+	Overall      Metrics             `json:"overall"`
+	BySourceType map[string]*Metrics `json:"bySourceType"`
+	Fixtures     []FixtureDiff       `json:"fixtures,omitempty"`
+}
+
+// ============================================================================
+// フィクスチャ読み込み
+// ============================================================================
+
+// loadFixtures ディレクトリから <name>.md と <name>.json の組を読み込む
+func loadFixtures(dir string) ([]Fixture, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.md"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list fixtures: %w", err)
+	}
+	sort.Strings(paths)
+
+	fixtures := make([]Fixture, 0, len(paths))
+	for _, mdPath := range paths {
+		name := strings.TrimSuffix(filepath.Base(mdPath), ".md")
+
+		markdown, err := os.ReadFile(mdPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read fixture %s: %w", mdPath, err)
+		}
+
+		labelPath := strings.TrimSuffix(mdPath, ".md") + ".json"
+		labelJSON, err := os.ReadFile(labelPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read label %s: %w", labelPath, err)
+		}
+
+		var label FixtureLabel
+		if err := json.Unmarshal(labelJSON, &label); err != nil {
+			return nil, fmt.Errorf("failed to parse label %s: %w", labelPath, err)
+		}
+
+		fixtures = append(fixtures, Fixture{
+			Name:     name,
+			Markdown: string(markdown),
+			Label:    label,
+		})
+	}
+
+	return fixtures, nil
+}
+
+// ============================================================================
+// 評価
+// ============================================================================
+
+// evaluate 全フィクスチャに対して抽出を実行し、抽出元の種類ごとに集計する
+func evaluate(bookExtractor *extractor.BookExtractor, fixtures []Fixture) *Report {
+	report := &Report{BySourceType: make(map[string]*Metrics)}
+
+	metricsFor := func(sourceType string) *Metrics {
+		m, ok := report.BySourceType[sourceType]
+		if !ok {
+			m = &Metrics{}
+			report.BySourceType[sourceType] = m
+		}
+		return m
+	}
+
+	for _, fixture := range fixtures {
+		// 正解をキーで索引
+		expected := make(map[string]ExpectedBook, len(fixture.Label.Expected))
+		for _, book := range fixture.Label.Expected {
+			expected[expectedKey(book)] = book
+		}
+
+		extracted := bookExtractor.ExtractFromText(fixture.Markdown)
+		predicted := make(map[string]string, len(extracted)) // キー → 抽出元の種類
+		diff := FixtureDiff{Name: fixture.Name}
+
+		// 適合率: 抽出結果の抽出元ごとに集計（種類別は期待する抽出元と一致した場合のみ正解とする）
+		for _, book := range extracted {
+			key := extractedKey(book)
+			predicted[key] = book.SourceType
+
+			m := metricsFor(book.SourceType)
+			m.Predicted++
+			report.Overall.Predicted++
+			want, ok := expected[key]
+			if !ok {
+				diff.Unexpected = append(diff.Unexpected, fmt.Sprintf("%s (%s)", key, book.SourceType))
+				continue
+			}
+			report.Overall.TruePositives++
+			if want.SourceType == book.SourceType {
+				m.TruePositives++
+			} else {
+				diff.Mismatched = append(diff.Mismatched, fmt.Sprintf("%s (%s -> %s)", key, want.SourceType, book.SourceType))
+			}
+		}
+
+		// 再現率: 正解の期待する抽出元ごとに集計（種類別は期待する抽出元で抽出できた場合のみ数える）
+		for key, book := range expected {
+			m := metricsFor(book.SourceType)
+			m.Expected++
+			report.Overall.Expected++
+			sourceType, ok := predicted[key]
+			if !ok {
+				diff.Missing = append(diff.Missing, fmt.Sprintf("%s (%s)", key, book.SourceType))
+				continue
+			}
+			report.Overall.Found++
+			if sourceType == book.SourceType {
+				m.Found++
+			}
+		}
+
+		sort.Strings(diff.Missing)
+		sort.Strings(diff.Unexpected)
+		sort.Strings(diff.Mismatched)
+		if len(diff.Missing) > 0 || len(diff.Unexpected) > 0 || len(diff.Mismatched) > 0 {
+			report.Fixtures = append(report.Fixtures, diff)
+		}
+	}
+
+	report.Overall.finalize()
+	for _, m := range report.BySourceType {
+		m.finalize()
+	}
+
+	return report
+}
+
+// finalize 件数から適合率・再現率を計算（分母が0の場合は1.0とみなす）
+func (m *Metrics) finalize() {
+	m.FalsePositives = m.Predicted - m.TruePositives
+	m.Precision = ratio(m.TruePositives, m.Predicted)
+	m.Recall = ratio(m.Found, m.Expected)
+}
+
+// ratio 割合を計算
+func ratio(numerator, denominator int) float64 {
+	if denominator == 0 {
+		return 1.0
+	}
+	return float64(numerator) / float64(denominator)
+}
+
+// extractedKey 抽出結果の比較用キー
+func extractedKey(book extractor.ExtractedBook) string {
+	switch {
+	case book.ISBN != "":
+		return "isbn:" + book.ISBN
+	case book.ASIN != "":
+		return "asin:" + book.ASIN
+	default:
+		return "title:" + book.Title
+	}
+}
+
+// expectedKey 正解の比較用キー
+func expectedKey(book ExpectedBook) string {
+	switch {
+	case book.ISBN != "":
+		return "isbn:" + strings.ReplaceAll(book.ISBN, "-", "")
+	case book.ASIN != "":
+		return "asin:" + book.ASIN
+	default:
+		return "title:" + book.Title
+	}
+}
+
+// ============================================================================
+// ベースライン比較
+// ============================================================================
+
+// loadBaseline ベースラインを読み込む
+func loadBaseline(path string) (*Report, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read baseline: %w", err)
+	}
+
+	var baseline Report
+	if err := json.Unmarshal(data, &baseline); err != nil {
+		return nil, fmt.Errorf("failed to parse baseline: %w", err)
+	}
+	return &baseline, nil
+}
+
+// writeBaseline 評価結果をベースラインとして保存（フィクスチャごとの差分は含めない）
+func writeBaseline(path string, report *Report) error {
+	baseline := Report{
+		Overall:      report.Overall,
+		BySourceType: report.BySourceType,
+	}
+
+	data, err := json.MarshalIndent(baseline, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal baseline: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write baseline: %w", err)
+	}
+	return nil
+}
+
+// findRegressions ベースラインから許容幅を超えて悪化した指標を列挙
+func findRegressions(baseline, current *Report, tolerance float64) []string {
+	var regressions []string
+
+	check := func(scope string, base, cur *Metrics) {
+		if cur.Precision < base.Precision-tolerance {
+			regressions = append(regressions, fmt.Sprintf("%s precision: %.3f -> %.3f", scope, base.Precision, cur.Precision))
+		}
+		if cur.Recall < base.Recall-tolerance {
+			regressions = append(regressions, fmt.Sprintf("%s recall: %.3f -> %.3f", scope, base.Recall, cur.Recall))
+		}
+	}
+
+	check("overall", &baseline.Overall, &current.Overall)
+
+	sourceTypes := make([]string, 0, len(baseline.BySourceType))
+	for sourceType := range baseline.BySourceType {
+		sourceTypes = append(sourceTypes, sourceType)
+	}
+	sort.Strings(sourceTypes)
+
+	for _, sourceType := range sourceTypes {
+		cur, ok := current.BySourceType[sourceType]
+		if !ok {
+			cur = &Metrics{}
+			cur.finalize()
+		}
+		check(sourceType, baseline.BySourceType[sourceType], cur)
+	}
+
+	return regressions
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+
+	"teckbook-compass-backend/internal/infrastructure/extractor"
+)
+
+// ============================================================================
+// CLI フラグ定義
+// ============================================================================
+
+type cliFlags struct {
+	fixturesDir   string
+	baselinePath  string
+	writeBaseline bool
+	tolerance     float64
+	jsonOutput    bool
+}
+
+func parseFlags() *cliFlags {
+	f := &cliFlags{}
+
+	flag.StringVar(&f.fixturesDir, "fixtures", "cmd/extract-eval/testdata/fixtures", "Directory of labeled fixtures (<name>.md + <name>.json)")
+	flag.StringVar(&f.baselinePath, "baseline", "", "Baseline metrics JSON to compare against (fails on regression)")
+	flag.BoolVar(&f.writeBaseline, "write-baseline", false, "Write the current metrics to -baseline instead of comparing")
+	flag.Float64Var(&f.tolerance, "tolerance", 0.0, "Allowed drop in precision/recall before failing")
+	flag.BoolVar(&f.jsonOutput, "json", false, "Print the report as JSON")
+
+	flag.Parse()
+	return f
+}
+
+// ============================================================================
+// メイン処理
+// ============================================================================
+
+func main() {
+	flags := parseFlags()
+
+	fixtures, err := loadFixtures(flags.fixturesDir)
+	if err != nil {
+		log.Fatalf("フィクスチャの読み込みに失敗しました: %v", err)
+	}
+	if len(fixtures) == 0 {
+		log.Fatalf("フィクスチャが見つかりません: %s", flags.fixturesDir)
+	}
+
+	report := evaluate(extractor.NewBookExtractor(), fixtures)
+
+	if flags.jsonOutput {
+		printJSON(report)
+	} else {
+		printReport(report, len(fixtures))
+	}
+
+	if flags.baselinePath == "" {
+		return
+	}
+
+	// ベースラインの更新
+	if flags.writeBaseline {
+		if err := writeBaseline(flags.baselinePath, report); err != nil {
+			log.Fatalf("ベースラインの保存に失敗しました: %v", err)
+		}
+		log.Printf("ベースラインを更新しました: %s", flags.baselinePath)
+		return
+	}
+
+	// ベースラインとの比較
+	baseline, err := loadBaseline(flags.baselinePath)
+	if err != nil {
+		log.Fatalf("ベースラインの読み込みに失敗しました: %v", err)
+	}
+
+	regressions := findRegressions(baseline, report, flags.tolerance)
+	if len(regressions) > 0 {
+		fmt.Fprintln(os.Stderr, "\nベースラインから悪化した指標:")
+		for _, r := range regressions {
+			fmt.Fprintf(os.Stderr, "  - %s\n", r)
+		}
+		os.Exit(1)
+	}
+
+	fmt.Fprintln(os.Stderr, "\nベースラインからの悪化はありません")
+}
+
+// ============================================================================
+// 出力
+// ============================================================================
+
+// printReport 評価結果を表形式で出力
+func printReport(report *Report, fixtureCount int) {
+	fmt.Println("===========================================")
+	fmt.Println("  BookExtractor 評価結果")
+	fmt.Println("===========================================")
+	fmt.Printf("  フィクスチャ数: %d\n\n", fixtureCount)
+
+	fmt.Printf("  %-12s %9s %9s %6s %6s %6s\n", "source", "precision", "recall", "tp", "pred", "exp")
+
+	sourceTypes := make([]string, 0, len(report.BySourceType))
+	for sourceType := range report.BySourceType {
+		sourceTypes = append(sourceTypes, sourceType)
+	}
+	sort.Strings(sourceTypes)
+
+	for _, sourceType := range sourceTypes {
+		printMetricsRow(sourceType, report.BySourceType[sourceType])
+	}
+	fmt.Println("  ---------------------------------------------------")
+	printMetricsRow("overall", &report.Overall)
+
+	if len(report.Fixtures) > 0 {
+		fmt.Println("\n===========================================")
+		fmt.Println("  フィクスチャごとの差分")
+		fmt.Println("===========================================")
+		for _, diff := range report.Fixtures {
+			fmt.Printf("  [%s]\n", diff.Name)
+			for _, m := range diff.Missing {
+				fmt.Printf("    - missing:    %s\n", m)
+			}
+			for _, u := range diff.Unexpected {
+				fmt.Printf("    + unexpected: %s\n", u)
+			}
+			for _, m := range diff.Mismatched {
+				fmt.Printf("    ~ mismatched: %s\n", m)
+			}
+		}
+	}
+}
+
+// printMetricsRow 指標を1行で出力
+func printMetricsRow(name string, m *Metrics) {
+	fmt.Printf("  %-12s %9.3f %9.3f %6d %6d %6d\n", name, m.Precision, m.Recall, m.TruePositives, m.Predicted, m.Expected)
+}
+
+// printJSON 評価結果をJSONで出力
+func printJSON(report *Report) {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		log.Fatalf("JSONの生成に失敗しました: %v", err)
+	}
+	fmt.Println(string(data))
+}
+
+// ============================================================================
+// ログ初期化
+// ============================================================================
+
+func init() {
+	log.SetFlags(log.LstdFlags | log.Lshortfile)
+}
@@ -0,0 +1,11 @@
+package entity
+
+import "time"
+
+// APIUsage 外部APIの日ごと・エンドポイントごとの呼び出し回数（api_usage）
+type APIUsage struct {
+	UsageDate time.Time // 日付（日本時間）
+	Provider  string    // プロバイダ（qiita, rakuten, amazon など）
+	Endpoint  string    // エンドポイント（items.search など）
+	Calls     int       // 呼び出し回数
+}
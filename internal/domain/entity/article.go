@@ -2,19 +2,29 @@ package entity
 
 import "time"
 
-// Article Qiita記事エンティティ
+// Article 記事エンティティ
 type Article struct {
-	ID          string    // Qiita記事ID
-	Title       string    // 記事タイトル
-	URL         string    // 記事URL
-	Body        string    // 記事本文（HTML or Markdown）
-	Likes       int       // いいね数
-	Stocks      int       // ストック数
-	Comments    int       // コメント数
-	Tags        []string  // タグ名配列
-	PublishedAt time.Time // 公開日時
-	CreatedAt   time.Time // DB登録日時
-	UpdatedAt   time.Time // DB更新日時
+	ID              string    // 記事ID（取得元での記事ID）
+	Source          string    // 取得元（"qiita", "zenn"）
+	Title           string    // 記事タイトル
+	URL             string    // 記事URL
+	Body            string    // 記事本文（HTML or Markdown）
+	Likes           int       // いいね数
+	Stocks          int       // ストック数
+	Comments        int       // コメント数
+	Tags            []string  // タグ名配列
+	PublishedAt     time.Time // 公開日時
+	SourceUpdatedAt time.Time // 取得元での更新日時
+	CreatedAt       time.Time // DB登録日時
+	UpdatedAt       time.Time // DB更新日時
+
+	HatenaBookmarks int // はてなブックマーク数（article_metrics）
+}
+
+// Popularity 記事の人気度
+// いいね数 + ストック数 * 1.5 + はてなブックマーク数 * hatenaWeight
+func (a *Article) Popularity(hatenaWeight float64) float64 {
+	return float64(a.Likes) + float64(a.Stocks)*1.5 + float64(a.HatenaBookmarks)*hatenaWeight
 }
 
 // ArticleTag 記事タグエンティティ
@@ -27,12 +37,28 @@ type ArticleTag struct {
 
 // ArticleBook 記事と書籍の紐付けエンティティ
 type ArticleBook struct {
-	ID        int64     // ID
-	ArticleID string    // 記事ID
-	BookID    string    // 書籍ID（ISBN）
-	CreatedAt time.Time // 作成日時
+	ID             int64         // ID
+	ArticleID      string        // 記事ID
+	BookID         string        // 書籍ID（ISBN）
+	Origin         MentionOrigin // 言及箇所（本文 or コメント）
+	SourceType     string        // 抽出元の種類（"isbn13", "amazon_url", "title" など）
+	Confidence     float64       // 抽出の確からしさ（0.0-1.0）
+	MatchedText    string        // マッチした生のトークン
+	Snippet        string        // マッチ箇所の前後テキスト
+	Excerpt        string        // 言及箇所を含む数文の抜粋（表示用）
+	Sentiment      Sentiment     // 言及の極性
+	SentimentScore float64       // 極性スコア（正: ポジティブ, 負: ネガティブ）
+	CreatedAt      time.Time     // 作成日時
 }
 
+// MentionOrigin 書籍への言及が見つかった箇所
+type MentionOrigin string
+
+const (
+	MentionOriginBody    MentionOrigin = "body"    // 記事本文
+	MentionOriginComment MentionOrigin = "comment" // 記事へのコメント
+)
+
 // QiitaAPIArticle Qiita APIから取得した記事
 type QiitaAPIArticle struct {
 	ID            string     `json:"id"`
@@ -53,6 +79,24 @@ type QiitaTag struct {
 	Name string `json:"name"`
 }
 
+// QiitaComment Qiita APIから取得した記事コメント
+type QiitaComment struct {
+	ID           string    `json:"id"`
+	Body         string    `json:"body"`
+	RenderedBody string    `json:"rendered_body"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// ToArticleComment QiitaCommentを取得元に依存しないコメントに変換
+func (c *QiitaComment) ToArticleComment() *ArticleComment {
+	return &ArticleComment{
+		ID:        c.ID,
+		Body:      c.Body,
+		CreatedAt: c.CreatedAt,
+	}
+}
+
 // GetTagNames タグ名の配列を取得
 func (a *QiitaAPIArticle) GetTagNames() []string {
 	tags := make([]string, len(a.Tags))
@@ -62,17 +106,20 @@ func (a *QiitaAPIArticle) GetTagNames() []string {
 	return tags
 }
 
-// ToArticle QiitaAPIArticleをArticleエンティティに変換
-func (a *QiitaAPIArticle) ToArticle() *Article {
-	return &Article{
-		ID:          a.ID,
-		Title:       a.Title,
-		URL:         a.URL,
-		Body:        a.Body,
-		Likes:       a.LikesCount,
-		Stocks:      a.StocksCount,
-		Comments:    a.CommentsCount,
-		Tags:        a.GetTagNames(),
-		PublishedAt: a.CreatedAt,
+// ToSourceArticle QiitaAPIArticleを取得元に依存しない記事に変換
+func (a *QiitaAPIArticle) ToSourceArticle() *SourceArticle {
+	return &SourceArticle{
+		Source:       SourceQiita,
+		ID:           a.ID,
+		Title:        a.Title,
+		URL:          a.URL,
+		Body:         a.Body,
+		RenderedBody: a.RenderedBody,
+		Likes:        a.LikesCount,
+		Stocks:       a.StocksCount,
+		Comments:     a.CommentsCount,
+		Tags:         a.GetTagNames(),
+		CreatedAt:    a.CreatedAt,
+		UpdatedAt:    a.UpdatedAt,
 	}
 }
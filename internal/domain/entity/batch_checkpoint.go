@@ -0,0 +1,52 @@
+package entity
+
+import "time"
+
+// BatchCheckpointStatus 記事取得バッチの進捗の状態
+type BatchCheckpointStatus string
+
+const (
+	BatchCheckpointProcessing  BatchCheckpointStatus = "processing"   // 記事を処理中（未処理の記事から再開する）
+	BatchCheckpointScoresSaved BatchCheckpointStatus = "scores_saved" // スコアを保存済み（加算し直さずに後処理から再開する）
+)
+
+// BatchCheckpoint 記事取得バッチの進捗（batch_checkpoints）
+// 取得した記事・処理済みの記事・集計途中のスコアを保存し、中断したバッチを途中から再開する
+type BatchCheckpoint struct {
+	ID               int64
+	Status           BatchCheckpointStatus
+	FetchMode        string               // 取得モードの表示用文字列
+	Sources          []CheckpointSource   // 取得元ごとの取得モード（再開後のバッチ状態の更新に使う）
+	Scores           []*BookScore         // 処理済みの記事から集計したスコア（保存前）
+	RecomputeBookIDs []string             // スコアを再計算する書籍
+	Articles         []*CheckpointArticle // 処理する記事（取得した順）
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+}
+
+// CheckpointSource 進捗に保存する取得元ごとの取得モード
+type CheckpointSource struct {
+	Source   string    `json:"source"`
+	StatusID string    `json:"status_id"`
+	Mode     FetchMode `json:"mode"`
+}
+
+// CheckpointArticle 進捗に保存する記事（本文は保存せず、再開時に取得元から取得し直す）
+type CheckpointArticle struct {
+	Source    string
+	ArticleID string
+	Edited    bool // 取得元で編集されていたため紐付けを作り直す記事
+	Retried   bool // 延期していたため処理し直す記事
+	Processed bool
+}
+
+// Remaining 未処理の記事数
+func (c *BatchCheckpoint) Remaining() int {
+	remaining := 0
+	for _, article := range c.Articles {
+		if !article.Processed {
+			remaining++
+		}
+	}
+	return remaining
+}
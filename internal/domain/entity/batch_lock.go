@@ -0,0 +1,24 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+)
+
+// BatchLockHolder バッチの排他ロックの保持者（batch_locks）
+type BatchLockHolder struct {
+	BatchType   string    // バッチの種類（article, amazon など）
+	Holder      string    // 保持しているホスト名
+	PID         int       // 保持しているプロセスID
+	BackendPID  int       // ロックを保持しているデータベース接続のバックエンドプロセスID
+	AcquiredAt  time.Time // ロックを取得した時刻
+	HeartbeatAt time.Time // 最後にリースを延長した時刻
+	ExpiresAt   time.Time // リース期限
+	Expired     bool      // リース期限を過ぎている（保持者のハートビートが途絶えている）
+}
+
+// String ログ・エラーメッセージ用の表記
+func (h *BatchLockHolder) String() string {
+	return fmt.Sprintf("%s (PID: %d, 取得: %s, 最終更新: %s)",
+		h.Holder, h.PID, h.AcquiredAt.Format("2006-01-02 15:04:05"), h.HeartbeatAt.Format("2006-01-02 15:04:05"))
+}
@@ -0,0 +1,52 @@
+package entity
+
+import (
+	"sort"
+	"time"
+)
+
+// BatchRunOutcome バッチの実行結果
+type BatchRunOutcome string
+
+const (
+	BatchRunRunning   BatchRunOutcome = "running"   // 実行中（終了を記録できずに停止した場合もこのまま残る）
+	BatchRunSucceeded BatchRunOutcome = "succeeded" // 最後まで処理した
+	BatchRunPartial   BatchRunOutcome = "partial"   // 期限に近づいたため途中で終了した（残りは次回に処理する）
+	BatchRunFailed    BatchRunOutcome = "failed"    // エラーで終了した
+)
+
+// BatchRun バッチの1回の実行履歴（batch_runs）
+// 件数はバッチの種類ごとに異なるため、名前 → 件数で記録する（processed_articles, new_books など）
+type BatchRun struct {
+	ID           int64             `json:"id"`
+	BatchType    string            `json:"batch_type"`
+	Mode         string            `json:"mode,omitempty"` // 取得モード（記事取得バッチのみ）
+	Params       map[string]any    `json:"params"`         // 実行パラメータ（処理上限など）
+	Counters     map[string]int    `json:"counters"`       // 処理件数・エラー数など
+	QueryStats   []*SearchQueryRun `json:"query_stats"`    // 検索クエリごとの取得実績（記事取得バッチのみ）
+	Outcome      BatchRunOutcome   `json:"outcome"`        // 実行結果
+	ErrorSummary string            `json:"error_summary"`  // エラーで終了した場合のエラー
+	StartedAt    time.Time         `json:"started_at"`     // 開始時刻
+	FinishedAt   *time.Time        `json:"finished_at"`    // 終了時刻（実行中は nil）
+	DurationMs   int64             `json:"duration_ms"`    // 処理時間（ミリ秒）
+}
+
+// Finish 実行結果を記録する
+func (r *BatchRun) Finish(outcome BatchRunOutcome, err error, now time.Time) {
+	r.Outcome = outcome
+	if err != nil {
+		r.ErrorSummary = err.Error()
+	}
+	r.FinishedAt = &now
+	r.DurationMs = now.Sub(r.StartedAt).Milliseconds()
+}
+
+// CounterNames 記録した件数の名前（名前順）
+func (r *BatchRun) CounterNames() []string {
+	names := make([]string, 0, len(r.Counters))
+	for name := range r.Counters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
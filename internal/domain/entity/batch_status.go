@@ -18,6 +18,11 @@ const (
 	BatchStatusIDQiitaFetch = "qiita_fetch"
 )
 
+// BatchStatusIDForSource 取得元ごとのバッチ状態ID（Qiitaは BatchStatusIDQiitaFetch と同じ）
+func BatchStatusIDForSource(source string) string {
+	return source + "_fetch"
+}
+
 // ShouldFetchNewArticles 最新記事を取得すべきかどうかを判定
 // 1日1回（最後の最新記事取得から24時間以上経過している場合）に実行
 func (bs *BatchStatus) ShouldFetchNewArticles() bool {
@@ -14,6 +14,7 @@ type BookDetail struct {
 	Tags                 []string             // タグ配列
 	Overview             string               // 概要
 	QiitaArticles        []QiitaArticle       // Qiita紹介記事一覧
+	MentionSentiment     SentimentSummary     // 記事での言及の極性集計
 	RakutenReviewSummary RakutenReviewSummary // 楽天レビューサマリー
 	PurchaseLinks        PurchaseLinks        // 購入リンク
 }
@@ -25,6 +26,7 @@ type QiitaArticle struct {
 	Likes    int    // いいね（LGTM）数
 	Stocks   int    // ストック数
 	Comments int    // コメント数
+	Excerpt  string // 記事内で書籍に言及している箇所の抜粋
 }
 
 // RakutenReviewSummary 楽天レビューサマリー
@@ -0,0 +1,65 @@
+package entity
+
+import (
+	"strings"
+	"time"
+)
+
+// BookLookupType 書籍メタデータの検索方法
+type BookLookupType string
+
+const (
+	BookLookupISBN  BookLookupType = "isbn"  // ISBNで検索
+	BookLookupTitle BookLookupType = "title" // タイトルで検索
+)
+
+// BookLookup 書籍メタデータの検索結果のキャッシュ（book_lookups）
+// 見つかった場合は書籍のISBNを、見つからなかった場合は次に問い合わせる時刻を保持する
+type BookLookup struct {
+	Type        BookLookupType
+	Key         string    // 正規化したISBN・タイトル（BookLookupKey）
+	ISBN        string    // 見つかった書籍のISBN-13（見つからなかった場合は空）
+	Misses      int       // 連続して見つからなかった回数
+	CheckedAt   time.Time // 最後に問い合わせた時刻
+	NextCheckAt time.Time // 見つからなかった場合に次に問い合わせる時刻
+}
+
+// BookLookupKey 検索結果のキャッシュのキー（ISBNはISBN-13、タイトルは小文字にして空白をまとめる）
+func BookLookupKey(lookupType BookLookupType, value string) string {
+	if lookupType == BookLookupISBN {
+		return ToISBN13(value)
+	}
+	return strings.ToLower(strings.Join(strings.Fields(value), " "))
+}
+
+// Found 書籍が見つかった検索かどうか
+func (l *BookLookup) Found() bool {
+	return l.ISBN != ""
+}
+
+// ShouldRecheck 外部APIに問い合わせ直す時刻を過ぎているか（見つかった検索は問い合わせない）
+func (l *BookLookup) ShouldRecheck(now time.Time) bool {
+	return !l.Found() && !now.Before(l.NextCheckAt)
+}
+
+// RecordFound 見つかった書籍のISBNを記録する
+func (l *BookLookup) RecordFound(isbn string, now time.Time) {
+	l.ISBN = isbn
+	l.Misses = 0
+	l.CheckedAt = now
+	l.NextCheckAt = time.Time{}
+}
+
+// RecordMiss 見つからなかったことを記録し、次に問い合わせる時刻を決める
+// 間隔は interval から見つからないたびに倍にし、maxInterval を上限とする
+func (l *BookLookup) RecordMiss(now time.Time, interval, maxInterval time.Duration) {
+	l.ISBN = ""
+	l.Misses++
+	l.CheckedAt = now
+
+	wait := interval
+	for i := 1; i < l.Misses && wait < maxInterval; i++ {
+		wait *= 2
+	}
+	l.NextCheckAt = now.Add(min(wait, maxInterval))
+}
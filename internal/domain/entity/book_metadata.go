@@ -0,0 +1,126 @@
+package entity
+
+import (
+	"strings"
+	"time"
+)
+
+// 書籍メタデータのフィールド名（取得元の記録に使用）
+const (
+	MetadataFieldTitle       = "title"
+	MetadataFieldAuthor      = "author"
+	MetadataFieldPublisher   = "publisher"
+	MetadataFieldPublishedAt = "published_at"
+	MetadataFieldPrice       = "price"
+	MetadataFieldOverview    = "overview"
+	MetadataFieldThumbnail   = "thumbnail"
+	MetadataFieldRakutenURL  = "rakuten_url"
+	MetadataFieldRating      = "rating"
+
+	// 書籍情報の再取得バッチでのみ扱うフィールド
+	MetadataFieldAvailability = "availability"
+)
+
+// completeOverviewRunes 概要がこの文字数以上あれば他のプロバイダで補完しない
+const completeOverviewRunes = 100
+
+// BookMetadata 書籍メタデータ
+// 複数のプロバイダ（楽天, openBD, Google Books, NDL）の結果をフィールドごとにマージしたもの
+type BookMetadata struct {
+	ISBN        string     // ISBN-13（書籍ID）
+	Title       string     // タイトル
+	Author      string     // 著者名
+	Publisher   string     // 出版社
+	PublishedAt *time.Time // 出版日
+	Price       int        // 価格
+	Overview    string     // 概要
+	Thumbnail   string     // 表紙画像URL
+	RakutenURL  string     // 楽天の商品URL（アフィリエイトURL）
+	Rating      float64    // 楽天の平均評価
+	ReviewCount int        // 楽天のレビュー数
+
+	// Sources フィールド名 → 値を採用したプロバイダ名
+	Sources map[string]string
+}
+
+// Merge 別プロバイダの結果をマージする
+// 空のフィールドは other の値で埋め、概要はより長いものを採用する
+func (m *BookMetadata) Merge(other *BookMetadata, provider string) {
+	if other == nil {
+		return
+	}
+	if m.Sources == nil {
+		m.Sources = make(map[string]string)
+	}
+
+	if m.ISBN == "" {
+		m.ISBN = other.ISBN
+	}
+	mergeString(&m.Title, other.Title, MetadataFieldTitle, provider, m.Sources)
+	mergeString(&m.Author, other.Author, MetadataFieldAuthor, provider, m.Sources)
+	mergeString(&m.Publisher, other.Publisher, MetadataFieldPublisher, provider, m.Sources)
+	mergeString(&m.Thumbnail, other.Thumbnail, MetadataFieldThumbnail, provider, m.Sources)
+	mergeString(&m.RakutenURL, other.RakutenURL, MetadataFieldRakutenURL, provider, m.Sources)
+
+	if m.PublishedAt == nil && other.PublishedAt != nil {
+		m.PublishedAt = other.PublishedAt
+		m.Sources[MetadataFieldPublishedAt] = provider
+	}
+	if m.Price == 0 && other.Price > 0 {
+		m.Price = other.Price
+		m.Sources[MetadataFieldPrice] = provider
+	}
+	if m.ReviewCount == 0 && other.ReviewCount > 0 {
+		m.Rating = other.Rating
+		m.ReviewCount = other.ReviewCount
+		m.Sources[MetadataFieldRating] = provider
+	}
+
+	// 概要は情報量の多い（長い）方を採用
+	if len([]rune(strings.TrimSpace(other.Overview))) > len([]rune(strings.TrimSpace(m.Overview))) {
+		m.Overview = other.Overview
+		m.Sources[MetadataFieldOverview] = provider
+	}
+}
+
+// IsComplete 表示に使う主要なフィールドがすべて埋まっているか（概要は十分な長さがあるか）
+func (m *BookMetadata) IsComplete() bool {
+	return m.Title != "" &&
+		m.Author != "" &&
+		m.Publisher != "" &&
+		m.PublishedAt != nil &&
+		m.Thumbnail != "" &&
+		len([]rune(m.Overview)) >= completeOverviewRunes
+}
+
+// mergeString 空の文字列フィールドを埋めて取得元を記録
+func mergeString(dst *string, value, field, provider string, sources map[string]string) {
+	if *dst != "" || strings.TrimSpace(value) == "" {
+		return
+	}
+	*dst = value
+	sources[field] = provider
+}
+
+// ToISBN13 ISBNをISBN-13に正規化（ハイフン除去、ISBN-10は変換）
+func ToISBN13(isbn string) string {
+	isbn = strings.ReplaceAll(strings.ReplaceAll(isbn, "-", ""), " ", "")
+	if len(isbn) != 10 {
+		return isbn
+	}
+
+	// 978 + ISBN-10の最初の9桁 + チェックデジット
+	prefix := "978" + isbn[:9]
+	sum := 0
+	for i := 0; i < 12; i++ {
+		digit := int(prefix[i] - '0')
+		if i%2 == 0 {
+			sum += digit
+		} else {
+			sum += digit * 3
+		}
+	}
+	checkDigit := (10 - (sum % 10)) % 10
+
+	return prefix + string(rune('0'+checkDigit))
+}
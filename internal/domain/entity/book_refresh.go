@@ -0,0 +1,59 @@
+package entity
+
+import "fmt"
+
+// BookRakutenSnapshot 楽天から取得して定期的に更新する書籍情報
+type BookRakutenSnapshot struct {
+	Price        int     // 価格
+	Rating       float64 // 平均評価
+	ReviewCount  int     // レビュー数
+	Availability string  // 在庫状況（楽天の availability コード）
+	Thumbnail    string  // 表紙画像URL
+}
+
+// BookFieldChange 書籍情報の変更内容
+type BookFieldChange struct {
+	Field  string // フィールド名
+	Before string // 変更前の値
+	After  string // 変更後の値
+}
+
+// String 変更内容を "field: before → after" 形式で返す
+func (c BookFieldChange) String() string {
+	return fmt.Sprintf("%s: %s → %s", c.Field, c.Before, c.After)
+}
+
+// SnapshotFromRakuten 楽天の書籍情報から更新対象のフィールドを取り出す
+func SnapshotFromRakuten(rb *RakutenBook) BookRakutenSnapshot {
+	book := rb.ToBook()
+	return BookRakutenSnapshot{
+		Price:        rb.ItemPrice,
+		Rating:       book.Rating,
+		ReviewCount:  rb.ReviewCount,
+		Availability: rb.Availability,
+		Thumbnail:    rb.LargeImageURL,
+	}
+}
+
+// Diff 現在の値から next への変更内容を返す
+// next の空の値（取得できなかった項目）は変更とみなさない
+func (s BookRakutenSnapshot) Diff(next BookRakutenSnapshot) []BookFieldChange {
+	var changes []BookFieldChange
+	if next.Price > 0 && next.Price != s.Price {
+		changes = append(changes, BookFieldChange{MetadataFieldPrice, fmt.Sprint(s.Price), fmt.Sprint(next.Price)})
+	}
+	if next.ReviewCount > 0 && (next.Rating != s.Rating || next.ReviewCount != s.ReviewCount) {
+		changes = append(changes, BookFieldChange{
+			MetadataFieldRating,
+			fmt.Sprintf("%.2f (%d件)", s.Rating, s.ReviewCount),
+			fmt.Sprintf("%.2f (%d件)", next.Rating, next.ReviewCount),
+		})
+	}
+	if next.Availability != "" && next.Availability != s.Availability {
+		changes = append(changes, BookFieldChange{MetadataFieldAvailability, s.Availability, next.Availability})
+	}
+	if next.Thumbnail != "" && next.Thumbnail != s.Thumbnail {
+		changes = append(changes, BookFieldChange{MetadataFieldThumbnail, s.Thumbnail, next.Thumbnail})
+	}
+	return changes
+}
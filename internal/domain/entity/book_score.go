@@ -14,16 +14,18 @@ type BookScoreDaily struct {
 
 // BookScore バッチ処理中に使用する書籍スコア
 type BookScore struct {
-	BookID            string    // 書籍ID（ISBN）
-	Score             float64   // 累積スコア
-	ArticleCount      int       // 記事数
-	LatestArticleDate time.Time // 紐づく記事の最新投稿日
+	BookID            string    `json:"book_id"`             // 書籍ID（ISBN）
+	Score             float64   `json:"score"`               // 累積スコア
+	ArticleCount      int       `json:"article_count"`       // 記事数
+	LatestArticleDate time.Time `json:"latest_article_date"` // 紐づく記事の最新投稿日
 }
 
 // AddScore スコアを加算し、最新記事投稿日を更新
-func (bs *BookScore) AddScore(likes int, stocks int, articleCreatedAt time.Time) {
-	// スコア計算: いいね数 + ストック数 * 1.5
-	bs.Score += float64(likes) + float64(stocks)*1.5
+// popularity: 記事の人気度（Article.Popularity）
+// weight: 言及の重み（ネガティブな言及の減点などに使用、通常は1.0）
+func (bs *BookScore) AddScore(popularity float64, articleCreatedAt time.Time, weight float64) {
+	// スコア計算: 記事の人気度 * 重み
+	bs.Score += popularity * weight
 	bs.ArticleCount++
 
 	// 最新記事投稿日を更新
@@ -0,0 +1,136 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+)
+
+// CrawlState 取得元・クエリごとの取得状態（crawl_states）
+// 過去記事取得の進捗、最新記事の取得日時、取得件数の実績を持ち、クエリの実行順の決定に使う
+// 日付で検索できる取得元では、期間 [WindowStart, WindowEnd) を新しい方から古い方へ遡って取得する
+type CrawlState struct {
+	Source      string     // 取得元
+	Query       string     // 検索クエリ（Zennはトピック名）
+	WindowStart time.Time  // 取得中の期間の開始日（この日を含む）
+	WindowEnd   time.Time  // 取得中の期間の終了日（この日を含まない、ゼロ値は未開始）
+	WindowDays  int        // 期間の日数（件数が多い期間では分割して短くする）
+	Page        int        // 取得中の期間で次に取得するページ
+	CompletedAt *time.Time // 下限日まで遡って取得し終えた日時（過去記事を取り尽くしたクエリ）
+
+	LastNewFetchedAt *time.Time // 最新記事取得モードで最後に取得した日時
+
+	// 取得件数の実績（両モードの累計）
+	Runs         int // 取得した回数
+	FetchedCount int // 取得件数の累計
+	NewCount     int // 新規件数（実行内の重複排除後）の累計
+	LastNewCount int // 直近の取得での新規件数
+
+	Priority int // 検索クエリの優先度（search_queries の値、保存しない）
+}
+
+// NewCrawlState 未開始のCrawlStateを生成
+func NewCrawlState(source, query string) *CrawlState {
+	return &CrawlState{
+		Source: source,
+		Query:  query,
+		Page:   1,
+	}
+}
+
+// IsCompleted 全期間の取得が完了しているか
+func (c *CrawlState) IsCompleted() bool {
+	return c.CompletedAt != nil
+}
+
+// IsStarted 期間の取得を開始しているか
+func (c *CrawlState) IsStarted() bool {
+	return !c.WindowEnd.IsZero()
+}
+
+// Start 終了日 end から windowDays 日の期間で取得を開始する
+func (c *CrawlState) Start(end time.Time, windowDays int) {
+	c.WindowEnd = truncateDate(end)
+	c.WindowDays = windowDays
+	c.WindowStart = c.WindowEnd.AddDate(0, 0, -windowDays)
+	c.Page = 1
+}
+
+// Subdivide 期間の件数が取得上限を超える場合に、期間を新しい方の半分に狭める
+// 古い方の半分は次の期間として取得される。1日の期間はそれ以上分割できないため false を返す
+func (c *CrawlState) Subdivide() bool {
+	days := int(c.WindowEnd.Sub(c.WindowStart).Hours() / 24)
+	if days <= 1 {
+		return false
+	}
+	c.WindowDays = days / 2
+	c.WindowStart = c.WindowEnd.AddDate(0, 0, -c.WindowDays)
+	c.Page = 1
+	return true
+}
+
+// Advance 現在の期間を取得し終えたので、windowDays 日の一つ前の期間に進む
+// floor（取得元のサービス開始日など）より前には遡らず、到達した場合は完了とする
+func (c *CrawlState) Advance(windowDays int, floor time.Time, now time.Time) {
+	floor = truncateDate(floor)
+	c.WindowEnd = c.WindowStart
+	c.WindowDays = windowDays
+	c.WindowStart = c.WindowEnd.AddDate(0, 0, -windowDays)
+	c.Page = 1
+
+	if !c.WindowEnd.After(floor) {
+		c.Complete(now)
+		return
+	}
+	if c.WindowStart.Before(floor) {
+		c.WindowStart = floor
+	}
+}
+
+// Complete 全期間の取得を完了とする
+func (c *CrawlState) Complete(now time.Time) {
+	c.CompletedAt = &now
+}
+
+// NewFetchSince 最新記事取得の基準日時（前回の取得日時から overlap だけ遡った日時、未取得の場合は nil）
+// 境界付近の記事の取りこぼしを防ぐため、前回の取得範囲と重ねて取得する
+func (c *CrawlState) NewFetchSince(overlap time.Duration) *time.Time {
+	if c.LastNewFetchedAt == nil {
+		return nil
+	}
+	since := c.LastNewFetchedAt.Add(-overlap)
+	return &since
+}
+
+// RecordRun 1回の取得の件数を実績に加算する
+func (c *CrawlState) RecordRun(fetched, newCount int) {
+	c.Runs++
+	c.FetchedCount += fetched
+	c.NewCount += newCount
+	c.LastNewCount = newCount
+}
+
+// Yield 1回の取得あたりの新規件数（未実行の場合は0）
+func (c *CrawlState) Yield() float64 {
+	if c.Runs == 0 {
+		return 0
+	}
+	return float64(c.NewCount) / float64(c.Runs)
+}
+
+// String 進捗の表示用文字列
+func (c *CrawlState) String() string {
+	switch {
+	case c.IsCompleted():
+		return fmt.Sprintf("[%s] %s: 完了", c.Source, c.Query)
+	case c.IsStarted():
+		return fmt.Sprintf("[%s] %s: %s〜%s ページ%d", c.Source, c.Query,
+			c.WindowStart.Format("2006-01-02"), c.WindowEnd.AddDate(0, 0, -1).Format("2006-01-02"), c.Page)
+	default:
+		return fmt.Sprintf("[%s] %s: ページ%d", c.Source, c.Query, c.Page)
+	}
+}
+
+// truncateDate 日付のみに切り捨てる
+func truncateDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
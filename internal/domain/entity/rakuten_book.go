@@ -86,6 +86,24 @@ func (rb *RakutenBook) ToBook() *Book {
 	}
 }
 
+// ToMetadata RakutenBookを書籍メタデータに変換
+func (rb *RakutenBook) ToMetadata() *BookMetadata {
+	book := rb.ToBook()
+	return &BookMetadata{
+		ISBN:        rb.ISBN,
+		Title:       rb.Title,
+		Author:      rb.Author,
+		Publisher:   rb.PublisherName,
+		PublishedAt: book.PublishedAt,
+		Price:       rb.ItemPrice,
+		Overview:    rb.ItemCaption,
+		Thumbnail:   rb.LargeImageURL,
+		RakutenURL:  rb.AffiliateURL,
+		Rating:      book.Rating,
+		ReviewCount: rb.ReviewCount,
+	}
+}
+
 // parseJapaneseDate 日本語形式の日付をパース（例: "2024年01月01日"）
 func parseJapaneseDate(dateStr string) (time.Time, error) {
 	// パターンに応じてパース
@@ -0,0 +1,14 @@
+package entity
+
+import "time"
+
+// RetryQueueItem 外部APIの障害で処理を延期した記事（retry_queue）
+// 遮断中のプロバイダで書籍を調べられなかった記事を、次回以降のバッチで紐付けから作り直す
+type RetryQueueItem struct {
+	Source    string // 取得元
+	ArticleID string
+	Reason    string // 延期した理由（遮断されていたクライアント名）
+	Attempts  int    // 延期した回数
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
@@ -0,0 +1,46 @@
+package entity
+
+import "time"
+
+// SearchQuery 記事の検索クエリ（search_queries）
+// 取得元ごとに有効・無効と優先度を管理し、バッチ処理は有効なクエリのみを優先度の高い順に取得する
+type SearchQuery struct {
+	ID        int64
+	Source    string // 取得元
+	Query     string // 検索クエリ（Qiitaは検索構文 "tag:book" なども可、Zennはトピック名）
+	Enabled   bool   // 取得対象とするか
+	Priority  int    // 優先度（大きいほど先に取得する）
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// SearchQueryRun 検索クエリの1回の取得実績（search_query_runs）
+type SearchQueryRun struct {
+	Source            string    `json:"source"`
+	Query             string    `json:"query"`
+	FetchMode         string    `json:"fetch_mode"`          // 取得モード（new / historical）
+	Fetched           int       `json:"fetched"`             // 取得件数
+	New               int       `json:"new"`                 // 新規件数（実行内の重複排除後）
+	Duplicates        int       `json:"duplicates"`          // 重複件数
+	ArticlesProcessed int       `json:"articles_processed"`  // 処理した記事数（このクエリで最初に見つかった記事）
+	ArticlesWithBooks int       `json:"articles_with_books"` // 書籍が見つかった記事数
+	BooksLinked       int       `json:"books_linked"`        // 記事と書籍の紐付け数
+	RunAt             time.Time `json:"run_at"`              // 実行日時
+}
+
+// RecordArticle 処理した記事と紐付けた書籍数を加算する
+func (r *SearchQueryRun) RecordArticle(booksLinked int) {
+	r.ArticlesProcessed++
+	r.BooksLinked += booksLinked
+	if booksLinked > 0 {
+		r.ArticlesWithBooks++
+	}
+}
+
+// BooksPerArticle 記事1件あたりの書籍の紐付け数（記事がない場合は0）
+func (r *SearchQueryRun) BooksPerArticle() float64 {
+	if r.ArticlesProcessed == 0 {
+		return 0
+	}
+	return float64(r.BooksLinked) / float64(r.ArticlesProcessed)
+}
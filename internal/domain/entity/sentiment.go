@@ -0,0 +1,28 @@
+package entity
+
+// Sentiment 記事内での書籍への言及の極性
+type Sentiment string
+
+const (
+	SentimentPositive Sentiment = "positive" // おすすめしている
+	SentimentNeutral  Sentiment = "neutral"  // 中立・判定不能
+	SentimentNegative Sentiment = "negative" // おすすめしていない
+)
+
+// SentimentSummary 書籍への言及の極性集計
+type SentimentSummary struct {
+	Positive int // ポジティブな言及数
+	Neutral  int // 中立な言及数
+	Negative int // ネガティブな言及数
+}
+
+// RecommendationRatio おすすめ率（ポジティブ / (ポジティブ + ネガティブ)）
+// 極性のある言及がない場合はnilを返す
+func (s SentimentSummary) RecommendationRatio() *float64 {
+	polarized := s.Positive + s.Negative
+	if polarized == 0 {
+		return nil
+	}
+	ratio := float64(s.Positive) / float64(polarized)
+	return &ratio
+}
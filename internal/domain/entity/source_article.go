@@ -0,0 +1,81 @@
+package entity
+
+import "time"
+
+// 記事の取得元
+const (
+	SourceQiita = "qiita" // Qiita
+	SourceZenn  = "zenn"  // Zenn
+)
+
+// SourceArticle 取得元（Qiita, Zenn など）から取得した記事
+// 取得元ごとのAPIレスポンスをこの形に変換してバッチ処理に渡す
+type SourceArticle struct {
+	Source       string    // 取得元
+	ID           string    // 取得元での記事ID
+	Title        string    // 記事タイトル
+	URL          string    // 記事URL
+	Body         string    // 記事本文（Markdown、取得できない場合は空）
+	RenderedBody string    // 記事本文（HTML）
+	Likes        int       // いいね数
+	Stocks       int       // ストック・ブックマーク数
+	Comments     int       // コメント数
+	Tags         []string  // タグ名配列
+	CreatedAt    time.Time // 公開日時
+	UpdatedAt    time.Time // 更新日時
+
+	HatenaBookmarks int  // はてなブックマーク数（取得元ではなくバッチ処理で付与）
+	HatenaFetched   bool // はてなブックマーク数を取得できたか（取得できなかった記事は保存済みの件数を上書きしない）
+
+	Query string // この記事を見つけた検索クエリ（一覧取得時に付与、単体取得では空）
+}
+
+// ArticleComment 取得元から取得した記事コメント
+type ArticleComment struct {
+	ID        string    // コメントID
+	Body      string    // コメント本文（Markdown）
+	CreatedAt time.Time // 投稿日時
+}
+
+// ToArticle SourceArticleをArticleエンティティに変換
+func (a *SourceArticle) ToArticle() *Article {
+	return &Article{
+		ID:              a.ID,
+		Source:          a.Source,
+		Title:           a.Title,
+		URL:             a.URL,
+		Body:            a.Body,
+		Likes:           a.Likes,
+		Stocks:          a.Stocks,
+		Comments:        a.Comments,
+		Tags:            a.Tags,
+		PublishedAt:     a.CreatedAt,
+		SourceUpdatedAt: a.UpdatedAt,
+
+		HatenaBookmarks: a.HatenaBookmarks,
+	}
+}
+
+// QueryStats クエリごとの取得統計
+type QueryStats struct {
+	Source     string // 取得元
+	Query      string // クエリ文字列
+	Fetched    int    // 取得件数
+	New        int    // 新規件数（重複排除後）
+	Duplicates int    // 重複件数
+}
+
+// FetchStats 取得統計
+type FetchStats struct {
+	QueryStats []QueryStats // クエリごとの統計
+	Total      int          // 合計件数
+}
+
+// Merge 別の取得元の統計を合算
+func (s *FetchStats) Merge(other *FetchStats) {
+	if other == nil {
+		return
+	}
+	s.QueryStats = append(s.QueryStats, other.QueryStats...)
+	s.Total += other.Total
+}
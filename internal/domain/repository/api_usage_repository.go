@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"teckbook-compass-backend/internal/domain/entity"
+)
+
+// APIUsageRepository 外部APIの使用量の記録
+type APIUsageRepository interface {
+	// AddAPIUsage 日付・プロバイダ・エンドポイントごとの呼び出し回数を加算
+	AddAPIUsage(ctx context.Context, usage *entity.APIUsage) error
+	// GetAPIUsage 指定日の呼び出し回数をプロバイダ・エンドポイントごとに取得
+	GetAPIUsage(ctx context.Context, usageDate time.Time) ([]*entity.APIUsage, error)
+}
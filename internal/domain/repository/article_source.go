@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"teckbook-compass-backend/internal/domain/entity"
+)
+
+// ArticleSource 記事の取得元（Qiita, Zenn など）
+type ArticleSource interface {
+	// Name 取得元の識別子（articles.source に保存する値）
+	Name() string
+
+	// DefaultQueries 取得元ごとの既定の検索クエリ（search_queries に取得元のクエリが未登録の場合に使用）
+	DefaultQueries() []string
+
+	// FetchNewArticles 最新記事を取得（クエリごとの LastNewFetchedAt から overlap だけ遡った日時以降の記事）
+	// 取得元が更新日時で検索できる場合は、その期間に編集された記事も含む
+	// 取得に成功したクエリは states の LastNewFetchedAt が更新される
+	FetchNewArticles(ctx context.Context, states []*entity.CrawlState, overlap time.Duration, maxPagesPerQuery int) ([]*entity.SourceArticle, *entity.FetchStats, error)
+
+	// FetchHistoricalArticles 過去記事を取得（クエリごとの進捗から各 pagesPerQuery ページまで）
+	// states は取得後の進捗に更新される
+	FetchHistoricalArticles(ctx context.Context, states []*entity.CrawlState, pagesPerQuery int) ([]*entity.SourceArticle, *entity.FetchStats, error)
+
+	// GetArticle 記事IDで記事を取得（削除・非公開化されている場合は external.ErrArticleNotFound）
+	GetArticle(ctx context.Context, articleID string) (*entity.SourceArticle, error)
+}
+
+// CommentSource 記事コメントの取得に対応した取得元
+type CommentSource interface {
+	// GetComments 記事のコメント一覧を取得
+	GetComments(ctx context.Context, articleID string) ([]*entity.ArticleComment, error)
+}
+
+// StocksCountSource 記事のストック数の個別取得に対応した取得元
+type StocksCountSource interface {
+	// GetArticleStocksCount 記事のストック数を取得
+	GetArticleStocksCount(ctx context.Context, articleID string) (int, error)
+}
@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+
+	"teckbook-compass-backend/internal/domain/entity"
+)
+
+// BatchCheckpointRepository 記事取得バッチの進捗の保存（中断したバッチの再開に使う）
+type BatchCheckpointRepository interface {
+	// CreateBatchCheckpoint 処理する記事とともに進捗を作成（checkpoint.ID・CreatedAt を設定する）
+	CreateBatchCheckpoint(ctx context.Context, checkpoint *entity.BatchCheckpoint) error
+	// GetBatchCheckpoint 未完了の進捗を記事とともに取得（ない場合は nil）
+	GetBatchCheckpoint(ctx context.Context) (*entity.BatchCheckpoint, error)
+	// SaveBatchCheckpointProgress 処理済みの記事と集計途中のスコア・再計算する書籍を1つのトランザクションで保存
+	SaveBatchCheckpointProgress(ctx context.Context, checkpoint *entity.BatchCheckpoint, processedArticleIDs []string) error
+	// SaveBatchCheckpointScores 集計したスコアを book_scores_daily に加算し、進捗をスコア保存済みにする（1つのトランザクション）
+	SaveBatchCheckpointScores(ctx context.Context, checkpoint *entity.BatchCheckpoint) error
+	// DeleteBatchCheckpoint 完了したバッチの進捗を削除
+	DeleteBatchCheckpoint(ctx context.Context, id int64) error
+}
@@ -12,13 +12,19 @@ type BatchRepository interface {
 	// Article関連
 	ArticleExists(ctx context.Context, articleID string) (bool, error)
 	SaveArticle(ctx context.Context, article *entity.Article) error
+	// GetArticleSourceUpdatedAts 保存済みの記事の取得元での更新日時を取得（未保存の記事は含まれず、未記録の場合は nil）
+	GetArticleSourceUpdatedAts(ctx context.Context, articleIDs []string) (map[string]*time.Time, error)
 	SaveArticleTags(ctx context.Context, articleID string, tags []string) error
-	SaveArticleBook(ctx context.Context, articleID string, bookID string) error
+	SaveArticleBook(ctx context.Context, articleBook *entity.ArticleBook) error
+	// DeleteArticleBooks 記事と書籍の紐付けをすべて削除
+	DeleteArticleBooks(ctx context.Context, articleID string) error
+	// GetArticleBooks 条件に一致する記事-書籍の紐付けを取得（管理用）
+	GetArticleBooks(ctx context.Context, filter ArticleBookFilter) ([]*entity.ArticleBook, error)
 
 	// Book関連
 	BookExists(ctx context.Context, bookID string) (bool, error)
 	GetBookIDByISBN(ctx context.Context, isbn string) (string, error) // ISBN-10/13どちらでも検索可能
-	SaveBook(ctx context.Context, book *entity.RakutenBook) error
+	SaveBook(ctx context.Context, book *entity.BookMetadata) error    // 複数プロバイダからマージしたメタデータを保存
 	UpdateBookScore(ctx context.Context, bookID string, score float64) error
 	GetExistingBookScore(ctx context.Context, bookID string) (float64, error)
 
@@ -34,16 +40,135 @@ type BatchRepository interface {
 	// BatchStatus関連
 	GetBatchStatus(ctx context.Context, id string) (*entity.BatchStatus, error)
 	UpdateBatchStatusForNewFetch(ctx context.Context, id string, lastFetchedAt time.Time) error
-	UpdateBatchStatusForHistoricalFetch(ctx context.Context, id string, nextPage int) error
+	UpdateBatchStatusForHistoricalFetch(ctx context.Context, id string) error // 過去記事取得の進捗は crawl_states で管理
+
+	// CrawlState関連
+	// GetCrawlStates 取得元のクエリごとの取得状態を取得
+	GetCrawlStates(ctx context.Context, source string) ([]*entity.CrawlState, error)
+	// SaveCrawlState クエリの取得状態を保存
+	SaveCrawlState(ctx context.Context, state *entity.CrawlState) error
+
+	// SearchQuery関連
+	// GetSearchQueries 検索クエリを優先度の高い順に取得（source が空の場合は全取得元、enabledOnly で有効なもののみ）
+	GetSearchQueries(ctx context.Context, source string, enabledOnly bool) ([]*entity.SearchQuery, error)
+	// AddSearchQuery 検索クエリを登録（登録済みの場合は有効化して優先度を更新）し、IDを設定する
+	AddSearchQuery(ctx context.Context, query *entity.SearchQuery) error
+	// SetSearchQueryEnabled 検索クエリの有効・無効を切り替える
+	SetSearchQueryEnabled(ctx context.Context, id int64, enabled bool) error
+	// SetSearchQueryPriority 検索クエリの優先度を変更
+	SetSearchQueryPriority(ctx context.Context, id int64, priority int) error
+	// SaveSearchQueryRun 検索クエリの1回の取得実績を保存
+	SaveSearchQueryRun(ctx context.Context, run *entity.SearchQueryRun) error
+	// GetSearchQueryStats 検索クエリごとの取得実績の累計を取得（管理用）
+	GetSearchQueryStats(ctx context.Context, source string) ([]*SearchQueryStats, error)
+
+	// RetryQueue関連
+	// EnqueueRetry 処理を延期した記事を登録（登録済みの場合は延期した回数を加算）
+	EnqueueRetry(ctx context.Context, item *entity.RetryQueueItem) error
+	// GetRetryQueue 処理を延期した記事を延期した日時の古い順に取得
+	GetRetryQueue(ctx context.Context, limit int) ([]*entity.RetryQueueItem, error)
+	// DeleteRetry 処理を延期した記事を削除
+	DeleteRetry(ctx context.Context, source, articleID string) error
+
+	// BookLookup関連
+	// GetBookLookup 書籍メタデータの検索結果のキャッシュを取得（未登録の場合は nil）
+	GetBookLookup(ctx context.Context, lookupType entity.BookLookupType, key string) (*entity.BookLookup, error)
+	// SaveBookLookup 書籍メタデータの検索結果のキャッシュを保存（登録済みの場合は上書き）
+	SaveBookLookup(ctx context.Context, lookup *entity.BookLookup) error
+
+	// APIの使用量関連
+	APIUsageRepository
+
+	// バッチの進捗関連
+	BatchCheckpointRepository
+
+	// バッチの実行履歴関連
+	BatchRunRepository
 
 	// ErrorLog関連
 	SaveErrorLog(ctx context.Context, log *ErrorLog) error
 
+	// ArticleMetrics関連
+	// SaveHatenaBookmarks 記事のはてなブックマーク数を保存
+	SaveHatenaBookmarks(ctx context.Context, articleID string, count int) error
+	// GetArticlesForHatenaUpdate はてなブックマーク数が未取得・取得日時が古い順に記事を取得
+	GetArticlesForHatenaUpdate(ctx context.Context, limit int) ([]*ArticleForHatenaUpdate, error)
+
 	// Amazon API用
 	// GetBooksWithoutAmazonURLByScore スコアが高い順にamazon_urlがない書籍を取得
 	GetBooksWithoutAmazonURLByScore(ctx context.Context, limit int) ([]*BookForAmazonUpdate, error)
 	// UpdateBookAmazonURL 書籍のAmazon URLを更新
 	UpdateBookAmazonURL(ctx context.Context, bookID string, amazonURL string) error
+
+	// 記事情報の再取得関連
+	// GetArticlesForMetricsRefresh 指定した取得元の削除されていない記事のうち、最終更新・最後に再取得に失敗してから minAge 以上経過した記事を古い順に取得
+	GetArticlesForMetricsRefresh(ctx context.Context, sources []string, minAge time.Duration, limit int) ([]*ArticleForMetricsRefresh, error)
+	// UpdateArticleMetrics 再取得した記事のタイトル・いいね数・ストック数・コメント数を更新
+	UpdateArticleMetrics(ctx context.Context, article *entity.Article) error
+	// MarkArticleDeleted 取得元で削除・非公開化された記事に削除日時を記録
+	MarkArticleDeleted(ctx context.Context, articleID string) error
+	// MarkArticleRefreshFailed 再取得に失敗した記事に失敗日時を記録（次の再取得を minAge 後まで遅らせる）
+	MarkArticleRefreshFailed(ctx context.Context, articleID string) error
+
+	// スコア再計算関連
+	// GetLinkedBookIDs 記事に紐付いている書籍IDを取得
+	GetLinkedBookIDs(ctx context.Context, articleID string) ([]string, error)
+	// GetBookIDsForScoring 日次スコアまたは記事-書籍の紐付けがある書籍IDをすべて取得（スコアを作り直す対象）
+	GetBookIDsForScoring(ctx context.Context) ([]string, error)
+	// GetBookMentionsForScoring 削除されていない記事から書籍への言及を記事ごとに1件（本文を優先）取得
+	GetBookMentionsForScoring(ctx context.Context, bookID string) ([]*BookMentionForScoring, error)
+	// ReplaceBookScoresDaily 書籍の日次スコアをすべて置き換える（scoresが空の場合は削除のみ）
+	ReplaceBookScoresDaily(ctx context.Context, bookID string, scores []*entity.BookScore) error
+
+	// 書籍情報の再取得関連
+	// GetBooksForMetadataRefresh 最終取得から minAge 以上経過した書籍を、経過日数×人気度の高い順に取得
+	GetBooksForMetadataRefresh(ctx context.Context, minAge time.Duration, limit int) ([]*BookForMetadataRefresh, error)
+	// UpdateBookRakutenSnapshot 再取得した楽天の書籍情報で更新（空の値は既存の値を維持）
+	UpdateBookRakutenSnapshot(ctx context.Context, bookID string, snapshot entity.BookRakutenSnapshot) error
+	// MarkBookMetadataRefreshed 再取得日時のみ更新（楽天で見つからなかった書籍用）
+	MarkBookMetadataRefreshed(ctx context.Context, bookID string) error
+}
+
+// ArticleForHatenaUpdate はてなブックマーク数更新用の記事情報
+type ArticleForHatenaUpdate struct {
+	ID              string
+	URL             string
+	HatenaBookmarks int // 保存済みのはてなブックマーク数（未取得の場合は0）
+}
+
+// ArticleForMetricsRefresh 記事情報再取得用の記事情報
+type ArticleForMetricsRefresh struct {
+	ID        string
+	Source    string
+	Title     string
+	Likes     int
+	Stocks    int
+	Comments  int
+	UpdatedAt time.Time
+}
+
+// BookMentionForScoring スコア再計算用の書籍への言及
+type BookMentionForScoring struct {
+	Article    *entity.Article // 言及している記事（人気度の計算に必要な項目のみ）
+	Origin     entity.MentionOrigin
+	Confidence float64
+	Sentiment  entity.Sentiment
+}
+
+// BookForMetadataRefresh 書籍情報再取得用の書籍情報
+type BookForMetadataRefresh struct {
+	ID      string // ISBN-13
+	Title   string
+	Score   float64                    // 累計スコア（人気度）
+	Current entity.BookRakutenSnapshot // 現在保存されている値
+}
+
+// SearchQueryStats 検索クエリと取得実績の累計（search_query_runs の合計）
+type SearchQueryStats struct {
+	Query     *entity.SearchQuery
+	Total     entity.SearchQueryRun // 取得件数・書籍の紐付け数などの累計
+	Runs      int                   // 実行回数
+	LastRunAt *time.Time            // 最終実行日時（未実行の場合はnil）
 }
 
 // BookForAmazonUpdate Amazon URL更新用の書籍情報
@@ -54,6 +179,19 @@ type BookForAmazonUpdate struct {
 	Score  float64
 }
 
+// ArticleBookFilter 記事-書籍紐付けの絞り込み条件（ゼロ値の項目は条件に含めない）
+type ArticleBookFilter struct {
+	SourceType    string   // 抽出元の種類
+	MinConfidence *float64 // 確からしさの下限（この値を含む）
+	MaxConfidence *float64 // 確からしさの上限（この値を含む）
+	BookID        string   // 書籍ID
+	ArticleID     string   // 記事ID
+	MatchedText   string   // マッチしたトークン（部分一致）
+	Sentiment     string   // 言及の極性
+	Origin        string   // 言及箇所（"body" or "comment"）
+	Limit         int      // 取得上限
+}
+
 // ErrorLog エラーログ
 type ErrorLog struct {
 	BatchProcess   string
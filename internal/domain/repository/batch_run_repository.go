@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+
+	"teckbook-compass-backend/internal/domain/entity"
+)
+
+// BatchRunRepository バッチの実行履歴（batch_runs）の保存と参照
+type BatchRunRepository interface {
+	// CreateBatchRun 実行の開始を記録（run.ID を設定する）
+	CreateBatchRun(ctx context.Context, run *entity.BatchRun) error
+	// FinishBatchRun 実行結果（件数・検索クエリごとの取得実績・結果・エラー）を記録
+	FinishBatchRun(ctx context.Context, run *entity.BatchRun) error
+	// ListBatchRuns 実行履歴を新しい順に取得（batchType が空の場合はすべての種類）
+	ListBatchRuns(ctx context.Context, batchType string, limit int) ([]*entity.BatchRun, error)
+	// GetBatchRun 実行履歴を取得（ない場合は nil）
+	GetBatchRun(ctx context.Context, id int64) (*entity.BatchRun, error)
+}
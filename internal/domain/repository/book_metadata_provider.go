@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+
+	"teckbook-compass-backend/internal/domain/entity"
+)
+
+// BookMetadataProvider 書籍メタデータの取得元（楽天, openBD, Google Books, NDL など）
+type BookMetadataProvider interface {
+	// Name プロバイダ名（フィールドごとの取得元として記録する値）
+	Name() string
+
+	// LookupByISBN ISBNで書籍メタデータを取得
+	LookupByISBN(ctx context.Context, isbn string) (*entity.BookMetadata, error)
+}
+
+// BookTitleSearcher タイトル検索に対応したプロバイダ
+type BookTitleSearcher interface {
+	// SearchMetadataByTitle タイトルで書籍メタデータを検索（関連度の高い順）
+	SearchMetadataByTitle(ctx context.Context, title string) ([]*entity.BookMetadata, error)
+}
@@ -10,8 +10,8 @@ type BookRepository interface {
 	// GetTopBooksByCategory カテゴリ別のトップ書籍を取得
 	GetTopBooksByCategory(ctx context.Context, categoryID string, limit int) ([]*entity.Book, error)
 
-	// GetRankings 総合ランキングを取得
-	GetRankings(ctx context.Context, rangeType string, limit int, offset int, categoryID string) ([]*entity.Book, error)
+	// GetRankings 総合ランキングを取得（sourceを指定した場合はその取得元の記事で言及された書籍に絞り込む）
+	GetRankings(ctx context.Context, rangeType string, limit int, offset int, categoryID string, source string) ([]*entity.Book, error)
 
 	// GetBookByID 書籍IDで書籍詳細を取得
 	GetBookByID(ctx context.Context, bookID string) (*entity.BookDetail, error)
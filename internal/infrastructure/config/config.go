@@ -4,6 +4,9 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -32,9 +35,91 @@ type Config struct {
 	Env        string
 	Database   DatabaseConfig
 	Qiita      QiitaConfig
+	Zenn       ZennConfig
+	Hatena     HatenaConfig
 	Rakuten    RakutenConfig
+	Metadata   MetadataConfig
+	Refresh    RefreshConfig
 	Amazon     AmazonConfig
 	Slack      SlackConfig
+	Scoring    ScoringConfig
+	Comments   CommentsConfig
+	Crawl      CrawlConfig
+	Processing ProcessingConfig
+	Usage      UsageConfig
+	Lock       LockConfig
+}
+
+// ScoringConfig 書籍スコア計算の設定
+type ScoringConfig struct {
+	MinConfidence  float64 // スコアに加算する紐付けの確からしさの下限（0の場合は全件加算）
+	NegativeWeight float64 // ネガティブな言及に掛ける重み（1.0で減点なし、0で加算しない）
+	CommentWeight  float64 // コメントでの言及に掛ける重み（本文は1.0）
+	// 取得元ごとに掛ける重み（未指定の取得元は1.0）
+	SourceWeights map[string]float64
+	// はてなブックマーク数に掛ける重み（0の場合はスコアに含めない）
+	HatenaWeight float64
+}
+
+// SourceWeight 取得元の重みを返す（未指定の場合は1.0）
+func (c ScoringConfig) SourceWeight(source string) float64 {
+	if w, ok := c.SourceWeights[source]; ok {
+		return w
+	}
+	return 1.0
+}
+
+// CommentsConfig Qiita記事コメントからの書籍抽出設定
+type CommentsConfig struct {
+	Enabled  bool // コメントからの抽出を有効にするか
+	MinLikes int  // コメントを取得する記事のいいね数の下限
+}
+
+// CrawlConfig クエリごとの取得状態（crawl_states）に基づく取得スケジュールの設定
+type CrawlConfig struct {
+	// 過去記事取得モードで1回に取得するクエリ数の上限（0の場合は未完了の全クエリ）
+	// 未実行のクエリを優先し、次に1回あたりの新規件数が多いクエリから選ぶ
+	HistoricalQueriesPerRun int
+
+	// 最新記事取得で前回の取得日時から遡って重ねて取得する時間（境界付近の取りこぼし防止）
+	NewFetchOverlap time.Duration
+}
+
+// ProcessingConfig 取得した記事の処理（書籍の抽出・紐付け）の設定
+type ProcessingConfig struct {
+	Workers         int // 記事を並行して処理するワーカー数
+	RetryQueueLimit int // 外部APIの障害で処理を延期した記事を1回に再処理する件数の上限
+	// 進捗（処理済みの記事・集計途中のスコア）を保存する間隔（記事数、0以下で保存しない）
+	// 中断したバッチは次回の実行で保存した時点から再開する
+	CheckpointInterval int
+	// 呼び出し元の期限（Lambdaの実行時間の上限等）に対して残す余裕
+	// 期限までの残りがこれ以下になったら新しい処理を始めず、途中までの結果を保存して終了する
+	DeadlineMargin time.Duration
+
+	// 書籍が見つからなかったISBN・タイトルを問い合わせ直すまでの間隔（見つからないたびに倍にする、0以下でキャッシュしない）
+	LookupRecheckInterval time.Duration
+	// 問い合わせ直すまでの間隔の上限
+	LookupRecheckMaxInterval time.Duration
+}
+
+// UsageConfig 外部APIの使用量（api_usage）の記録と1日の上限の設定
+type UsageConfig struct {
+	// プロバイダ（qiita, zenn, hatena, rakuten, openbd, google_books, ndl, amazon）ごとの1日の呼び出し回数の上限
+	// 未指定のプロバイダは上限なし（使用量の記録のみ）。日付は日本時間で区切る
+	DailyBudgets map[string]int
+}
+
+// LockConfig バッチの排他制御（バッチの種類ごとのアドバイザリロック）の設定
+type LockConfig struct {
+	// ロックのリース期間。保持者は期間の1/3ごとに更新し、更新が途絶えて期限を過ぎたロックは
+	// 次に起動したバッチが保持者の接続を切断して引き継ぐ
+	Lease time.Duration
+}
+
+// CircuitConfig 外部APIクライアントのサーキットブレーカー設定（クライアントごとに状態を持つ）
+type CircuitConfig struct {
+	FailureThreshold int           // 連続してこの回数失敗すると遮断する（0以下で無効）
+	CoolDown         time.Duration // 遮断してから試行を再開するまでの時間
 }
 
 // SlackConfig Slack通知設定
@@ -49,6 +134,29 @@ type SlackConfig struct {
 type QiitaConfig struct {
 	AccessToken string
 	BaseURL     string
+
+	// 過去記事取得の設定
+	HistoricalWindowDays int       // 検索期間の初期日数（件数が多い期間は分割、少ない期間は延長）
+	HistoricalFloor      time.Time // この日より前には遡らない
+
+	RateLimit float64 // 1秒あたりのリクエスト数の上限（0以下で無制限）
+	Circuit   CircuitConfig
+}
+
+// ZennConfig Zenn API設定
+type ZennConfig struct {
+	BaseURL   string
+	Enabled   bool    // Zennからの記事取得を有効にするか
+	RateLimit float64 // 1秒あたりのリクエスト数の上限（0以下で無制限）
+	Circuit   CircuitConfig
+}
+
+// HatenaConfig はてなブックマーク件数API設定
+type HatenaConfig struct {
+	BaseURL   string
+	Enabled   bool    // はてなブックマーク数の取得を有効にするか
+	RateLimit float64 // 1秒あたりのリクエスト数の上限（0以下で無制限）
+	Circuit   CircuitConfig
 }
 
 // RakutenConfig 楽天ブックスAPI設定
@@ -57,6 +165,26 @@ type RakutenConfig struct {
 	ApplicationSecret string
 	AffiliateID       string
 	BaseURL           string
+	RateLimit         float64 // 1秒あたりのリクエスト数の上限（0以下で無制限）
+	Circuit           CircuitConfig
+}
+
+// MetadataConfig 書籍メタデータ取得の設定
+type MetadataConfig struct {
+	// Providers 問い合わせるプロバイダの順序（rakuten, openbd, google_books, ndl）
+	Providers          []string
+	OpenBDBaseURL      string
+	GoogleBooksBaseURL string
+	GoogleBooksAPIKey  string // 未設定でも利用可能（1日あたりの上限が低くなる）
+	NDLBaseURL         string
+	Circuit            CircuitConfig // プロバイダ（openBD, Google Books, NDL）ごとに適用
+}
+
+// RefreshConfig 保存済み書籍・記事の情報再取得の設定
+type RefreshConfig struct {
+	BookMinAgeDays    int // 前回の取得からこの日数以上経過した書籍のみ再取得する
+	ArticleMinAgeDays int // 前回の更新からこの日数以上経過した記事のみ再取得する
+	ArticleAPIBudget  int // 記事の再取得1回あたりに使う取得元APIのリクエスト数の上限
 }
 
 // AmazonConfig Amazon Product Advertising API設定
@@ -66,7 +194,9 @@ type AmazonConfig struct {
 	PartnerTag string
 	Region     string
 	BaseURL    string
-	Enabled    bool // Amazon APIを有効にするかどうか（後で追加するため）
+	Enabled    bool    // Amazon APIを有効にするかどうか（後で追加するため）
+	RateLimit  float64 // 1秒あたりのリクエスト数の上限（PA-APIは1リクエスト/秒）
+	Circuit    CircuitConfig
 }
 
 // DatabaseConfig データベース接続設定
@@ -104,10 +234,123 @@ func NewConfig() *Config {
 		Env:        env,
 		Database:   newDatabaseConfig(),
 		Qiita:      newQiitaConfig(),
+		Zenn:       newZennConfig(),
+		Hatena:     newHatenaConfig(),
 		Rakuten:    newRakutenConfig(),
+		Metadata:   newMetadataConfig(),
+		Refresh:    newRefreshConfig(),
 		Amazon:     newAmazonConfig(),
 		Slack:      newSlackConfig(),
+		Scoring:    newScoringConfig(),
+		Comments:   newCommentsConfig(),
+		Crawl: CrawlConfig{
+			HistoricalQueriesPerRun: getEnvInt("CRAWL_HISTORICAL_QUERIES_PER_RUN", 0),
+			NewFetchOverlap:         time.Duration(getEnvInt("CRAWL_NEW_FETCH_OVERLAP_HOURS", 24)) * time.Hour,
+		},
+		Processing: ProcessingConfig{
+			Workers:                  getEnvInt("BATCH_WORKERS", 4),
+			RetryQueueLimit:          getEnvInt("RETRY_QUEUE_LIMIT", 50),
+			CheckpointInterval:       getEnvInt("BATCH_CHECKPOINT_INTERVAL", 20),
+			DeadlineMargin:           time.Duration(getEnvInt("BATCH_DEADLINE_MARGIN_SECONDS", 60)) * time.Second,
+			LookupRecheckInterval:    time.Duration(getEnvInt("LOOKUP_RECHECK_HOURS", 24)) * time.Hour,
+			LookupRecheckMaxInterval: time.Duration(getEnvInt("LOOKUP_RECHECK_MAX_DAYS", 30)) * 24 * time.Hour,
+		},
+		Usage: UsageConfig{
+			// PA-APIの初期の上限（1日8,640回）に合わせる
+			DailyBudgets: getEnvLimits("API_DAILY_BUDGETS", "amazon:8640"),
+		},
+		Lock: LockConfig{
+			Lease: time.Duration(getEnvInt("BATCH_LOCK_LEASE_SECONDS", 60)) * time.Second,
+		},
+	}
+}
+
+// newScoringConfig 書籍スコア計算の設定を初期化
+func newScoringConfig() ScoringConfig {
+	return ScoringConfig{
+		MinConfidence:  getEnvFloat("SCORE_MIN_CONFIDENCE", 0),
+		NegativeWeight: getEnvFloat("SCORE_NEGATIVE_WEIGHT", 1.0),
+		CommentWeight:  getEnvFloat("SCORE_COMMENT_WEIGHT", 0.5),
+		SourceWeights:  getEnvWeights("SCORE_SOURCE_WEIGHTS"),
+		HatenaWeight:   getEnvFloat("SCORE_HATENA_WEIGHT", 0),
+	}
+}
+
+// newCommentsConfig コメントからの書籍抽出設定を初期化
+func newCommentsConfig() CommentsConfig {
+	return CommentsConfig{
+		Enabled:  os.Getenv("QIITA_COMMENTS_ENABLED") == "true",
+		MinLikes: getEnvInt("QIITA_COMMENTS_MIN_LIKES", 20),
+	}
+}
+
+// getEnvInt 環境変数から整数を取得（未設定・不正値の場合はデフォルト値）
+func getEnvInt(key string, defaultValue int) int {
+	if v := os.Getenv(key); v != "" {
+		if i, err := strconv.Atoi(v); err == nil {
+			return i
+		}
+		log.Printf("警告: %sの値が不正です（デフォルト値を使用）: %s", key, v)
+	}
+	return defaultValue
+}
+
+// getEnvFloat 環境変数から数値を取得（未設定・不正値の場合はデフォルト値）
+func getEnvFloat(key string, defaultValue float64) float64 {
+	if v := os.Getenv(key); v != "" {
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			return f
+		}
+		log.Printf("警告: %sの値が不正です（デフォルト値を使用）: %s", key, v)
 	}
+	return defaultValue
+}
+
+// getEnvWeights 環境変数から "key:weight" のカンマ区切りリストを取得（例: "qiita:1.0,zenn:0.8"）
+func getEnvWeights(key string) map[string]float64 {
+	weights := make(map[string]float64)
+	v := os.Getenv(key)
+	if v == "" {
+		return weights
+	}
+	for _, pair := range strings.Split(v, ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(pair), ":")
+		if !ok {
+			log.Printf("警告: %sの値が不正です（無視します）: %s", key, pair)
+			continue
+		}
+		f, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			log.Printf("警告: %sの値が不正です（無視します）: %s", key, pair)
+			continue
+		}
+		weights[strings.TrimSpace(name)] = f
+	}
+	return weights
+}
+
+// getEnvLimits 環境変数から "key:limit" のカンマ区切りリストを取得（例: "amazon:8640,rakuten:5000"）
+// 未設定の場合は defaultValue を使用する
+func getEnvLimits(key string, defaultValue string) map[string]int {
+	limits := make(map[string]int)
+	v := getEnvString(key, defaultValue)
+	if v == "" {
+		return limits
+	}
+	for _, pair := range strings.Split(v, ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(pair), ":")
+		if !ok {
+			log.Printf("警告: %sの値が不正です（無視します）: %s", key, pair)
+			continue
+		}
+		i, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil {
+			log.Printf("警告: %sの値が不正です（無視します）: %s", key, pair)
+			continue
+		}
+		limits[strings.TrimSpace(name)] = i
+	}
+	return limits
 }
 
 // newSlackConfig Slack通知設定を初期化
@@ -134,9 +377,53 @@ func newQiitaConfig() QiitaConfig {
 		baseURL = "https://qiita.com/api/v2"
 	}
 
+	// Qiitaのサービス開始日より前には遡らない
+	floor := time.Date(2011, 9, 1, 0, 0, 0, 0, time.UTC)
+	if v := os.Getenv("QIITA_HISTORICAL_FLOOR"); v != "" {
+		if t, err := time.Parse("2006-01-02", v); err == nil {
+			floor = t
+		} else {
+			log.Printf("警告: QIITA_HISTORICAL_FLOORの値が不正です（デフォルト値を使用）: %s", v)
+		}
+	}
+
 	return QiitaConfig{
-		AccessToken: os.Getenv("QIITA_ACCESS_TOKEN"),
-		BaseURL:     baseURL,
+		AccessToken:          os.Getenv("QIITA_ACCESS_TOKEN"),
+		BaseURL:              baseURL,
+		HistoricalWindowDays: getEnvInt("QIITA_HISTORICAL_WINDOW_DAYS", 30),
+		HistoricalFloor:      floor,
+		RateLimit:            getEnvFloat("QIITA_RATE_LIMIT", 1),
+		Circuit:              newCircuitConfig(),
+	}
+}
+
+// newZennConfig Zenn API設定を初期化
+func newZennConfig() ZennConfig {
+	baseURL := os.Getenv("ZENN_BASE_URL")
+	if baseURL == "" {
+		baseURL = "https://zenn.dev/api"
+	}
+
+	return ZennConfig{
+		BaseURL:   baseURL,
+		Enabled:   os.Getenv("ZENN_ENABLED") == "true",
+		RateLimit: getEnvFloat("ZENN_RATE_LIMIT", 2),
+		Circuit:   newCircuitConfig(),
+	}
+}
+
+// newHatenaConfig はてなブックマーク件数API設定を初期化
+func newHatenaConfig() HatenaConfig {
+	baseURL := os.Getenv("HATENA_BASE_URL")
+	if baseURL == "" {
+		baseURL = "https://bookmark.hatenaapis.com"
+	}
+
+	return HatenaConfig{
+		BaseURL:   baseURL,
+		Enabled:   os.Getenv("HATENA_ENABLED") == "true",
+		RateLimit: getEnvFloat("HATENA_RATE_LIMIT", 1),
+		Circuit:   newCircuitConfig(),
 	}
 }
 
@@ -152,7 +439,56 @@ func newRakutenConfig() RakutenConfig {
 		ApplicationSecret: os.Getenv("RAKUTEN_APPLICATION_SECRET"),
 		AffiliateID:       os.Getenv("RAKUTEN_AFFILIATE_ID"),
 		BaseURL:           baseURL,
+		RateLimit:         getEnvFloat("RAKUTEN_RATE_LIMIT", 1),
+		Circuit:           newCircuitConfig(),
+	}
+}
+
+// newMetadataConfig 書籍メタデータ取得の設定を初期化
+func newMetadataConfig() MetadataConfig {
+	providers := []string{"rakuten", "openbd", "google_books", "ndl"}
+	if v := os.Getenv("BOOK_METADATA_PROVIDERS"); v != "" {
+		providers = providers[:0]
+		for _, name := range strings.Split(v, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				providers = append(providers, name)
+			}
+		}
+	}
+
+	return MetadataConfig{
+		Providers:          providers,
+		OpenBDBaseURL:      getEnvString("OPENBD_BASE_URL", "https://api.openbd.jp/v1"),
+		GoogleBooksBaseURL: getEnvString("GOOGLE_BOOKS_BASE_URL", "https://www.googleapis.com/books/v1"),
+		GoogleBooksAPIKey:  os.Getenv("GOOGLE_BOOKS_API_KEY"),
+		NDLBaseURL:         getEnvString("NDL_BASE_URL", "https://ndlsearch.ndl.go.jp/api"),
+		Circuit:            newCircuitConfig(),
+	}
+}
+
+// newCircuitConfig 外部APIクライアントのサーキットブレーカー設定を初期化
+func newCircuitConfig() CircuitConfig {
+	return CircuitConfig{
+		FailureThreshold: getEnvInt("CIRCUIT_FAILURE_THRESHOLD", 5),
+		CoolDown:         time.Duration(getEnvInt("CIRCUIT_COOLDOWN_SECONDS", 60)) * time.Second,
+	}
+}
+
+// newRefreshConfig 保存済み書籍・記事の情報再取得の設定を初期化
+func newRefreshConfig() RefreshConfig {
+	return RefreshConfig{
+		BookMinAgeDays:    getEnvInt("BOOK_REFRESH_MIN_AGE_DAYS", 7),
+		ArticleMinAgeDays: getEnvInt("ARTICLE_REFRESH_MIN_AGE_DAYS", 3),
+		ArticleAPIBudget:  getEnvInt("ARTICLE_REFRESH_API_BUDGET", 300),
+	}
+}
+
+// getEnvString 環境変数から文字列を取得（未設定の場合はデフォルト値）
+func getEnvString(key string, defaultValue string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
 	}
+	return defaultValue
 }
 
 // newAmazonConfig Amazon Product Advertising API設定を初期化
@@ -167,6 +503,8 @@ func newAmazonConfig() AmazonConfig {
 		Region:     "us-west-2",
 		BaseURL:    "webservices.amazon.co.jp",
 		Enabled:    enabled,
+		RateLimit:  getEnvFloat("AMAZON_RATE_LIMIT", 1),
+		Circuit:    newCircuitConfig(),
 	}
 }
 
@@ -22,7 +22,7 @@ func (r *BookRepositoryMock) GetTopBooksByCategory(ctx context.Context, category
 }
 
 // GetRankings 総合ランキングを取得（モックデータ）
-func (r *BookRepositoryMock) GetRankings(ctx context.Context, rangeType string, limit int, offset int, categoryID string) ([]*entity.Book, error) {
+func (r *BookRepositoryMock) GetRankings(ctx context.Context, rangeType string, limit int, offset int, categoryID string, source string) ([]*entity.Book, error) {
 	// モックデータを作成
 	allBooks := r.createMockRankingData(rangeType)
 
@@ -255,6 +255,7 @@ func (r *BookRepositoryMock) GetBookByID(ctx context.Context, bookID string) (*e
 					Likes:    42,
 					Stocks:   15,
 					Comments: 3,
+					Excerpt:  "新人研修の課題図書として読みました。変更に強いコードを書くための考え方が具体例つきで整理されています。",
 				},
 				{
 					Title:    "【書評】良いコード／悪いコードで学ぶ設計入門を読んでみた",
@@ -262,6 +263,7 @@ func (r *BookRepositoryMock) GetBookByID(ctx context.Context, bookID string) (*e
 					Likes:    128,
 					Stocks:   67,
 					Comments: 12,
+					Excerpt:  "クラス設計で迷ったときに読み返しています。悪いコード例が身に覚えのあるものばかりで刺さりました。",
 				},
 				{
 					Title:    "設計入門書として「良いコード/悪いコード」がおすすめな理由",
@@ -269,8 +271,12 @@ func (r *BookRepositoryMock) GetBookByID(ctx context.Context, bookID string) (*e
 					Likes:    85,
 					Stocks:   32,
 					Comments: 5,
+					Excerpt:  "設計の入門書を一冊だけ挙げるならこれです。",
 				},
 			},
+			MentionSentiment: entity.SentimentSummary{
+				Positive: 3,
+			},
 			RakutenReviewSummary: entity.RakutenReviewSummary{
 				AverageRating: 4.4,
 				TotalReviews:  128,
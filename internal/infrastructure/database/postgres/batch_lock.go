@@ -0,0 +1,277 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"teckbook-compass-backend/internal/domain/entity"
+)
+
+const (
+	// batchLockNamespace アドバイザリロックのキーの1つ目（他の用途のアドバイザリロックと区別する）
+	// 2つ目のキーはバッチの種類のハッシュ値（hashtext）
+	batchLockNamespace = 7462
+
+	// defaultBatchLockLease リース期間が指定されていない場合のリース期間
+	defaultBatchLockLease = 60 * time.Second
+
+	// batchLockTakeOverWait 期限切れの保持者の接続を切断してからロックが解放されるまで待つ時間
+	batchLockTakeOverWait = 5 * time.Second
+)
+
+// ErrBatchLocked 別のプロセスが同じ種類のバッチのロックを保持している（errors.Is で判定）
+var ErrBatchLocked = errors.New("batch is locked by another process")
+
+// BatchLockedError ロックを保持しているバッチの種類と保持者
+type BatchLockedError struct {
+	BatchType string
+	Holder    *entity.BatchLockHolder // 保持者（記録されていない場合は nil）
+}
+
+// Error エラーメッセージ
+func (e *BatchLockedError) Error() string {
+	if e.Holder == nil {
+		return fmt.Sprintf("別のバッチプロセスが実行中です (%s)", e.BatchType)
+	}
+	return fmt.Sprintf("別のバッチプロセスが実行中です (%s): %s", e.BatchType, e.Holder)
+}
+
+// Is ErrBatchLocked と一致する
+func (e *BatchLockedError) Is(target error) bool {
+	return target == ErrBatchLocked
+}
+
+// BatchLock バッチの種類ごとの排他ロック
+// PostgreSQLのアドバイザリロックを専用の接続で保持し、保持者の情報とリース期限を batch_locks に記録する
+// リース期間の1/3ごとにハートビートで期限を延長し、接続が切れる等で延長できなかった場合は Lost で通知する
+type BatchLock struct {
+	conn      *sql.Conn
+	batchType string
+	lease     time.Duration
+
+	stop     chan struct{}
+	done     chan struct{}
+	lost     chan struct{}
+	stopOnce sync.Once
+}
+
+// AcquireBatchLock バッチの種類ごとのロックを取得する（取得できるまで待たない）
+// 他のプロセスが保持している場合は BatchLockedError を返す。保持者のリース期限が切れている場合は
+// ハートビートが途絶えた（プロセスが停止した・Lambdaの実行環境が凍結された等）とみなし、保持者の接続を切断して引き継ぐ
+func AcquireBatchLock(ctx context.Context, db *sql.DB, batchType string, lease time.Duration) (*BatchLock, error) {
+	if lease <= 0 {
+		lease = defaultBatchLockLease
+	}
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get connection for batch lock: %w", err)
+	}
+	l := &BatchLock{
+		conn:      conn,
+		batchType: batchType,
+		lease:     lease,
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+		lost:      make(chan struct{}),
+	}
+
+	acquired, err := l.tryLock(ctx)
+	if err == nil && !acquired {
+		acquired, err = l.takeOverExpired(ctx)
+	}
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if !acquired {
+		holder, err := l.holder(ctx)
+		if err != nil {
+			log.Printf("Warning: ロックの保持者の取得エラー (%s): %v\n", batchType, err)
+		}
+		conn.Close()
+		return nil, &BatchLockedError{BatchType: batchType, Holder: holder}
+	}
+
+	if err := l.recordHolder(ctx); err != nil {
+		l.unlock(ctx)
+		return nil, err
+	}
+
+	go l.heartbeat()
+	return l, nil
+}
+
+// Lost ロックを失った（リースを延長できなかった）場合に閉じられるチャネル
+func (l *BatchLock) Lost() <-chan struct{} {
+	return l.lost
+}
+
+// Release ハートビートを止め、保持者の記録を削除してロックを解放する
+func (l *BatchLock) Release() error {
+	l.stopOnce.Do(func() { close(l.stop) })
+	<-l.done
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if _, err := l.conn.ExecContext(ctx, `
+		DELETE FROM batch_locks WHERE batch_type = $1 AND backend_pid = pg_backend_pid()
+	`, l.batchType); err != nil {
+		log.Printf("Warning: ロックの保持者の削除エラー (%s): %v\n", l.batchType, err)
+	}
+	return l.unlock(ctx)
+}
+
+// tryLock アドバイザリロックの取得を試みる
+func (l *BatchLock) tryLock(ctx context.Context) (bool, error) {
+	var acquired bool
+	if err := l.conn.QueryRowContext(ctx,
+		`SELECT pg_try_advisory_lock($1::int, hashtext($2))`, batchLockNamespace, l.batchType,
+	).Scan(&acquired); err != nil {
+		return false, fmt.Errorf("failed to acquire batch lock: %w", err)
+	}
+	return acquired, nil
+}
+
+// unlock アドバイザリロックを解放して接続を閉じる
+// 解放できなかった場合は、ロックが接続プールに残らないよう接続を破棄する
+func (l *BatchLock) unlock(ctx context.Context) error {
+	_, err := l.conn.ExecContext(ctx,
+		`SELECT pg_advisory_unlock($1::int, hashtext($2))`, batchLockNamespace, l.batchType)
+	if err != nil {
+		_ = l.conn.Raw(func(any) error { return driver.ErrBadConn })
+		l.conn.Close()
+		return fmt.Errorf("failed to release batch lock: %w", err)
+	}
+	return l.conn.Close()
+}
+
+// takeOverExpired リース期限が切れた保持者の接続を切断し、ロックを引き継ぐ
+// 記録上の保持者が実際にロックを保持している場合のみ切断する（記録が古く別の接続が保持している場合は切断しない）
+func (l *BatchLock) takeOverExpired(ctx context.Context) (bool, error) {
+	holder, err := l.holder(ctx)
+	if err != nil {
+		return false, err
+	}
+	if holder == nil || !holder.Expired {
+		return false, nil
+	}
+
+	var terminated bool
+	err = l.conn.QueryRowContext(ctx, `
+		SELECT pg_terminate_backend(pid)
+		FROM pg_locks
+		WHERE locktype = 'advisory' AND granted
+			AND pid = $1 AND classid = $2::int::oid AND objid = hashtext($3)::oid AND objsubid = 2
+	`, holder.BackendPID, batchLockNamespace, l.batchType).Scan(&terminated)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return false, fmt.Errorf("failed to terminate expired batch lock holder: %w", err)
+	}
+	if terminated {
+		log.Printf("Warning: %sのロックのリース期限が切れているため、保持者の接続を切断して引き継ぎます: %s\n", l.batchType, holder)
+	}
+
+	// 切断した接続のロックが解放されるまで待つ
+	deadline := time.Now().Add(batchLockTakeOverWait)
+	for {
+		acquired, err := l.tryLock(ctx)
+		if err != nil || acquired || !terminated || time.Now().After(deadline) {
+			return acquired, err
+		}
+		select {
+		case <-time.After(200 * time.Millisecond):
+		case <-ctx.Done():
+			return false, ctx.Err()
+		}
+	}
+}
+
+// holder ロックの保持者の記録を取得（記録がない場合は nil）
+func (l *BatchLock) holder(ctx context.Context) (*entity.BatchLockHolder, error) {
+	h := &entity.BatchLockHolder{}
+	err := l.conn.QueryRowContext(ctx, `
+		SELECT batch_type, holder, pid, backend_pid, acquired_at, heartbeat_at, expires_at, expires_at < NOW()
+		FROM batch_locks
+		WHERE batch_type = $1
+	`, l.batchType).Scan(&h.BatchType, &h.Holder, &h.PID, &h.BackendPID, &h.AcquiredAt, &h.HeartbeatAt, &h.ExpiresAt, &h.Expired)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get batch lock holder: %w", err)
+	}
+	return h, nil
+}
+
+// recordHolder 保持者の情報とリース期限を記録する
+func (l *BatchLock) recordHolder(ctx context.Context) error {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+
+	_, err = l.conn.ExecContext(ctx, `
+		INSERT INTO batch_locks (batch_type, holder, pid, backend_pid, acquired_at, heartbeat_at, expires_at)
+		VALUES ($1, $2, $3, pg_backend_pid(), NOW(), NOW(), NOW() + make_interval(secs => $4))
+		ON CONFLICT (batch_type) DO UPDATE SET
+			holder = EXCLUDED.holder,
+			pid = EXCLUDED.pid,
+			backend_pid = EXCLUDED.backend_pid,
+			acquired_at = EXCLUDED.acquired_at,
+			heartbeat_at = EXCLUDED.heartbeat_at,
+			expires_at = EXCLUDED.expires_at
+	`, l.batchType, host, os.Getpid(), l.lease.Seconds())
+	if err != nil {
+		return fmt.Errorf("failed to record batch lock holder: %w", err)
+	}
+	return nil
+}
+
+// heartbeat リース期間の1/3ごとにリース期限を延長する（延長できなかった場合は Lost を閉じて終了）
+func (l *BatchLock) heartbeat() {
+	defer close(l.done)
+
+	interval := l.lease / 3
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-l.stop:
+			return
+		case <-ticker.C:
+			if err := l.extend(interval); err != nil {
+				log.Printf("Warning: %sのロックのリースを延長できませんでした: %v\n", l.batchType, err)
+				close(l.lost)
+				return
+			}
+		}
+	}
+}
+
+// extend リース期限を延長する（他のプロセスに引き継がれていた場合はエラー）
+func (l *BatchLock) extend(timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	res, err := l.conn.ExecContext(ctx, `
+		UPDATE batch_locks
+		SET heartbeat_at = NOW(), expires_at = NOW() + make_interval(secs => $2)
+		WHERE batch_type = $1 AND backend_pid = pg_backend_pid()
+	`, l.batchType, l.lease.Seconds())
+	if err != nil {
+		return fmt.Errorf("failed to extend batch lock: %w", err)
+	}
+	if rows, err := res.RowsAffected(); err == nil && rows == 0 {
+		return errors.New("batch lock was taken over by another process")
+	}
+	return nil
+}
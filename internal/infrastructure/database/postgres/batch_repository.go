@@ -35,23 +35,31 @@ func (r *BatchRepositoryImpl) ArticleExists(ctx context.Context, articleID strin
 // SaveArticle 記事を保存
 func (r *BatchRepositoryImpl) SaveArticle(ctx context.Context, article *entity.Article) error {
 	query := `
-		INSERT INTO articles (id, title, url, likes, stocks, comments, published_at, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, NOW(), NOW())
+		INSERT INTO articles (id, source, title, url, likes, stocks, comments, published_at, source_updated_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NOW(), NOW())
 		ON CONFLICT (id) DO UPDATE SET
 			title = EXCLUDED.title,
 			likes = EXCLUDED.likes,
 			stocks = EXCLUDED.stocks,
 			comments = EXCLUDED.comments,
+			source_updated_at = COALESCE(EXCLUDED.source_updated_at, articles.source_updated_at),
+			deleted_at = NULL,
 			updated_at = NOW()
 	`
+	var sourceUpdatedAt interface{}
+	if !article.SourceUpdatedAt.IsZero() {
+		sourceUpdatedAt = article.SourceUpdatedAt
+	}
 	_, err := r.db.ExecContext(ctx, query,
 		article.ID,
+		sourceOrDefault(article.Source),
 		article.Title,
 		article.URL,
 		article.Likes,
 		article.Stocks,
 		article.Comments,
 		article.PublishedAt,
+		sourceUpdatedAt,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to save article: %w", err)
@@ -59,6 +67,54 @@ func (r *BatchRepositoryImpl) SaveArticle(ctx context.Context, article *entity.A
 	return nil
 }
 
+// GetArticleSourceUpdatedAts 保存済み（削除されていない）記事の取得元での更新日時を取得
+// 保存されていない記事はマップに含まれず、更新日時が未記録の記事は nil になる
+func (r *BatchRepositoryImpl) GetArticleSourceUpdatedAts(ctx context.Context, articleIDs []string) (map[string]*time.Time, error) {
+	result := make(map[string]*time.Time, len(articleIDs))
+	if len(articleIDs) == 0 {
+		return result, nil
+	}
+
+	placeholders := make([]string, len(articleIDs))
+	args := make([]interface{}, len(articleIDs))
+	for i, id := range articleIDs {
+		placeholders[i] = fmt.Sprintf("$%d", i+1)
+		args[i] = id
+	}
+
+	query := fmt.Sprintf(`
+		SELECT id, source_updated_at
+		FROM articles
+		WHERE id IN (%s) AND deleted_at IS NULL
+	`, joinStrings(placeholders, ", "))
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get article source updated_at: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id string
+		var sourceUpdatedAt sql.NullTime
+		if err := rows.Scan(&id, &sourceUpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan article source updated_at: %w", err)
+		}
+		if sourceUpdatedAt.Valid {
+			t := sourceUpdatedAt.Time
+			result[id] = &t
+		} else {
+			result[id] = nil
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate article source updated_at: %w", err)
+	}
+
+	return result, nil
+}
+
 // SaveArticleTags 記事タグを保存
 func (r *BatchRepositoryImpl) SaveArticleTags(ctx context.Context, articleID string, tags []string) error {
 	// 既存のタグを削除
@@ -80,20 +136,135 @@ func (r *BatchRepositoryImpl) SaveArticleTags(ctx context.Context, articleID str
 	return nil
 }
 
-// SaveArticleBook 記事と書籍の紐付けを保存
-func (r *BatchRepositoryImpl) SaveArticleBook(ctx context.Context, articleID string, bookID string) error {
+// DeleteArticleBooks 記事と書籍の紐付けをすべて削除（書籍の抽出をやり直す前に使用）
+func (r *BatchRepositoryImpl) DeleteArticleBooks(ctx context.Context, articleID string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM article_books WHERE article_id = $1`, articleID)
+	if err != nil {
+		return fmt.Errorf("failed to delete article_books: %w", err)
+	}
+	return nil
+}
+
+// SaveArticleBook 記事と書籍の紐付けを保存（既存の紐付けは抽出元の情報を更新）
+func (r *BatchRepositoryImpl) SaveArticleBook(ctx context.Context, articleBook *entity.ArticleBook) error {
 	query := `
-		INSERT INTO article_books (article_id, book_id, created_at)
-		VALUES ($1, $2, NOW())
-		ON CONFLICT (article_id, book_id) DO NOTHING
+		INSERT INTO article_books (
+			article_id, book_id, origin, source_type, confidence, matched_text, snippet, excerpt,
+			sentiment, sentiment_score, created_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, NOW())
+		ON CONFLICT (article_id, book_id, origin) DO UPDATE SET
+			source_type = EXCLUDED.source_type,
+			confidence = EXCLUDED.confidence,
+			matched_text = EXCLUDED.matched_text,
+			snippet = EXCLUDED.snippet,
+			excerpt = EXCLUDED.excerpt,
+			sentiment = EXCLUDED.sentiment,
+			sentiment_score = EXCLUDED.sentiment_score
 	`
-	_, err := r.db.ExecContext(ctx, query, articleID, bookID)
+	_, err := r.db.ExecContext(ctx, query,
+		articleBook.ArticleID,
+		articleBook.BookID,
+		originOrDefault(articleBook.Origin),
+		nullIfEmpty(articleBook.SourceType),
+		articleBook.Confidence,
+		nullIfEmpty(articleBook.MatchedText),
+		nullIfEmpty(articleBook.Snippet),
+		nullIfEmpty(articleBook.Excerpt),
+		nullIfEmpty(string(articleBook.Sentiment)),
+		articleBook.SentimentScore,
+	)
 	if err != nil {
 		return fmt.Errorf("failed to save article_book: %w", err)
 	}
 	return nil
 }
 
+// GetArticleBooks 条件に一致する記事-書籍の紐付けを取得（確からしさの低い順）
+func (r *BatchRepositoryImpl) GetArticleBooks(ctx context.Context, filter repository.ArticleBookFilter) ([]*entity.ArticleBook, error) {
+	var conditions []string
+	var args []interface{}
+
+	addCondition := func(format string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(format, len(args)))
+	}
+
+	if filter.SourceType != "" {
+		addCondition("source_type = $%d", filter.SourceType)
+	}
+	if filter.MinConfidence != nil {
+		addCondition("confidence >= $%d", *filter.MinConfidence)
+	}
+	if filter.MaxConfidence != nil {
+		addCondition("confidence <= $%d", *filter.MaxConfidence)
+	}
+	if filter.BookID != "" {
+		addCondition("book_id = $%d", filter.BookID)
+	}
+	if filter.ArticleID != "" {
+		addCondition("article_id = $%d", filter.ArticleID)
+	}
+	if filter.MatchedText != "" {
+		addCondition("matched_text ILIKE $%d", "%"+filter.MatchedText+"%")
+	}
+	if filter.Sentiment != "" {
+		addCondition("sentiment = $%d", filter.Sentiment)
+	}
+	if filter.Origin != "" {
+		addCondition("origin = $%d", filter.Origin)
+	}
+
+	query := `
+		SELECT id, article_id, book_id, origin, COALESCE(source_type, ''), COALESCE(confidence, 0),
+			COALESCE(matched_text, ''), COALESCE(snippet, ''), COALESCE(excerpt, ''),
+			COALESCE(sentiment, ''), COALESCE(sentiment_score, 0), created_at
+		FROM article_books
+	`
+	if len(conditions) > 0 {
+		query += " WHERE " + joinStrings(conditions, " AND ")
+	}
+	query += " ORDER BY confidence ASC NULLS FIRST, id"
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get article_books: %w", err)
+	}
+	defer rows.Close()
+
+	var articleBooks []*entity.ArticleBook
+	for rows.Next() {
+		var ab entity.ArticleBook
+		if err := rows.Scan(
+			&ab.ID,
+			&ab.ArticleID,
+			&ab.BookID,
+			&ab.Origin,
+			&ab.SourceType,
+			&ab.Confidence,
+			&ab.MatchedText,
+			&ab.Snippet,
+			&ab.Excerpt,
+			&ab.Sentiment,
+			&ab.SentimentScore,
+			&ab.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan article_book: %w", err)
+		}
+		articleBooks = append(articleBooks, &ab)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate article_books: %w", err)
+	}
+
+	return articleBooks, nil
+}
+
 // BookExists 書籍が既に存在するか確認（ISBN-10とISBN-13の両方でチェック）
 func (r *BatchRepositoryImpl) BookExists(ctx context.Context, bookID string) (bool, error) {
 	var exists bool
@@ -121,67 +292,63 @@ func (r *BatchRepositoryImpl) GetBookIDByISBN(ctx context.Context, isbn string)
 }
 
 // SaveBook 書籍を保存
-func (r *BatchRepositoryImpl) SaveBook(ctx context.Context, book *entity.RakutenBook) error {
-	// 出版日をパース
-	var publishedDate *time.Time
-	if book.SalesDate != "" {
-		patterns := []string{
-			"2006年01月02日",
-			"2006年01月",
-			"2006年1月2日",
-			"2006年1月",
-		}
-		for _, pattern := range patterns {
-			if t, err := time.Parse(pattern, book.SalesDate); err == nil {
-				publishedDate = &t
-				break
-			}
-		}
-	}
+// 複数プロバイダからマージしたメタデータを保存し、フィールドごとの取得元をmetadata_sourcesに記録する
+func (r *BatchRepositoryImpl) SaveBook(ctx context.Context, book *entity.BookMetadata) error {
+	// ISBN-13からISBN-10を計算
+	isbn10 := convertISBN13to10(book.ISBN)
 
-	// 評価をパース
-	var rating float64
-	if book.ReviewAverage != "" {
-		fmt.Sscanf(book.ReviewAverage, "%f", &rating)
+	// 価格・評価は取得できた場合のみ保存
+	var price, rating, reviewCount interface{}
+	if book.Price > 0 {
+		price = book.Price
+	}
+	if _, ok := book.Sources[entity.MetadataFieldRating]; ok {
+		rating = book.Rating
+		reviewCount = book.ReviewCount
 	}
 
-	// ISBN-13からISBN-10を計算
-	isbn10 := convertISBN13to10(book.ISBN)
+	sourcesJSON, err := json.Marshal(book.Sources)
+	if err != nil {
+		return fmt.Errorf("failed to marshal metadata sources: %w", err)
+	}
 
 	query := `
 		INSERT INTO books (
 			id, isbn10, title, author, publisher, published_date, price,
 			thumbnail_url, rakuten_url, rakuten_average_rating, rakuten_review_count,
-			overview, latest_mentioned_at, created_at, updated_at
+			overview, metadata_sources, latest_mentioned_at, created_at, updated_at
 		)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, NOW(), NOW(), NOW())
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, NOW(), NOW(), NOW())
 		ON CONFLICT (id) DO UPDATE SET
 			isbn10 = COALESCE(EXCLUDED.isbn10, books.isbn10),
 			title = EXCLUDED.title,
-			author = EXCLUDED.author,
-			publisher = EXCLUDED.publisher,
-			price = EXCLUDED.price,
+			author = COALESCE(EXCLUDED.author, books.author),
+			publisher = COALESCE(EXCLUDED.publisher, books.publisher),
+			published_date = COALESCE(EXCLUDED.published_date, books.published_date),
+			price = COALESCE(EXCLUDED.price, books.price),
 			thumbnail_url = COALESCE(EXCLUDED.thumbnail_url, books.thumbnail_url),
 			rakuten_url = COALESCE(EXCLUDED.rakuten_url, books.rakuten_url),
-			rakuten_average_rating = EXCLUDED.rakuten_average_rating,
-			rakuten_review_count = EXCLUDED.rakuten_review_count,
+			rakuten_average_rating = COALESCE(EXCLUDED.rakuten_average_rating, books.rakuten_average_rating),
+			rakuten_review_count = COALESCE(EXCLUDED.rakuten_review_count, books.rakuten_review_count),
 			overview = COALESCE(EXCLUDED.overview, books.overview),
+			metadata_sources = EXCLUDED.metadata_sources,
 			latest_mentioned_at = NOW(),
 			updated_at = NOW()
 	`
-	_, err := r.db.ExecContext(ctx, query,
+	_, err = r.db.ExecContext(ctx, query,
 		book.ISBN,
 		isbn10,
 		book.Title,
-		book.Author,
-		book.PublisherName,
-		publishedDate,
-		book.ItemPrice,
-		book.LargeImageURL,
-		book.AffiliateURL,
+		nullIfEmpty(book.Author),
+		nullIfEmpty(book.Publisher),
+		book.PublishedAt,
+		price,
+		nullIfEmpty(book.Thumbnail),
+		nullIfEmpty(book.RakutenURL),
 		rating,
-		book.ReviewCount,
-		book.ItemCaption,
+		reviewCount,
+		nullIfEmpty(book.Overview),
+		sourcesJSON,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to save book: %w", err)
@@ -217,16 +384,18 @@ func (r *BatchRepositoryImpl) GetExistingBookScore(ctx context.Context, bookID s
 	return score, nil
 }
 
+// addBookScoreDailyQuery 書籍スコア日次集計に加算するクエリ
+const addBookScoreDailyQuery = `
+	INSERT INTO book_scores_daily (book_id, date, score, article_count, created_at)
+	VALUES ($1, $2, $3, $4, NOW())
+	ON CONFLICT (book_id, date) DO UPDATE SET
+		score = book_scores_daily.score + EXCLUDED.score,
+		article_count = book_scores_daily.article_count + EXCLUDED.article_count
+`
+
 // SaveBookScoreDaily 書籍スコア日次集計を保存
 func (r *BatchRepositoryImpl) SaveBookScoreDaily(ctx context.Context, bookID string, date time.Time, score float64, articleCount int) error {
-	query := `
-		INSERT INTO book_scores_daily (book_id, date, score, article_count, created_at)
-		VALUES ($1, $2, $3, $4, NOW())
-		ON CONFLICT (book_id, date) DO UPDATE SET
-			score = book_scores_daily.score + EXCLUDED.score,
-			article_count = book_scores_daily.article_count + EXCLUDED.article_count
-	`
-	_, err := r.db.ExecContext(ctx, query, bookID, date, score, articleCount)
+	_, err := r.db.ExecContext(ctx, addBookScoreDailyQuery, bookID, date, score, articleCount)
 	if err != nil {
 		return fmt.Errorf("failed to save book score daily: %w", err)
 	}
@@ -384,19 +553,93 @@ func (r *BatchRepositoryImpl) UpdateBatchStatusForNewFetch(ctx context.Context,
 }
 
 // UpdateBatchStatusForHistoricalFetch 過去記事取得後のバッチ状態を更新
-func (r *BatchRepositoryImpl) UpdateBatchStatusForHistoricalFetch(ctx context.Context, id string, nextPage int) error {
+// 過去記事取得の進捗は crawl_states で管理するため、実行日時のみ更新する
+func (r *BatchRepositoryImpl) UpdateBatchStatusForHistoricalFetch(ctx context.Context, id string) error {
 	query := `
 		UPDATE batch_statuses
-		SET next_page = $2, last_run_at = NOW()
+		SET last_run_at = NOW()
 		WHERE id = $1
 	`
-	_, err := r.db.ExecContext(ctx, query, id, nextPage)
+	_, err := r.db.ExecContext(ctx, query, id)
 	if err != nil {
 		return fmt.Errorf("failed to update batch status for historical fetch: %w", err)
 	}
 	return nil
 }
 
+// SaveHatenaBookmarks 記事のはてなブックマーク数を保存
+func (r *BatchRepositoryImpl) SaveHatenaBookmarks(ctx context.Context, articleID string, count int) error {
+	query := `
+		INSERT INTO article_metrics (article_id, hatena_bookmarks, hatena_fetched_at, created_at, updated_at)
+		VALUES ($1, $2, NOW(), NOW(), NOW())
+		ON CONFLICT (article_id) DO UPDATE SET
+			hatena_bookmarks = EXCLUDED.hatena_bookmarks,
+			hatena_fetched_at = NOW(),
+			updated_at = NOW()
+	`
+	_, err := r.db.ExecContext(ctx, query, articleID, count)
+	if err != nil {
+		return fmt.Errorf("failed to save hatena bookmarks: %w", err)
+	}
+	return nil
+}
+
+// GetArticlesForHatenaUpdate はてなブックマーク数が未取得・取得日時が古い順に記事を取得
+func (r *BatchRepositoryImpl) GetArticlesForHatenaUpdate(ctx context.Context, limit int) ([]*repository.ArticleForHatenaUpdate, error) {
+	query := `
+		SELECT a.id, a.url, COALESCE(am.hatena_bookmarks, 0)
+		FROM articles a
+		LEFT JOIN article_metrics am ON a.id = am.article_id
+		WHERE a.deleted_at IS NULL
+		ORDER BY am.hatena_fetched_at ASC NULLS FIRST, a.published_at DESC
+		LIMIT $1
+	`
+	rows, err := r.db.QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get articles for hatena update: %w", err)
+	}
+	defer rows.Close()
+
+	var articles []*repository.ArticleForHatenaUpdate
+	for rows.Next() {
+		var article repository.ArticleForHatenaUpdate
+		if err := rows.Scan(&article.ID, &article.URL, &article.HatenaBookmarks); err != nil {
+			return nil, fmt.Errorf("failed to scan article: %w", err)
+		}
+		articles = append(articles, &article)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return articles, nil
+}
+
+// originOrDefault 言及箇所が未指定の場合は本文とみなす
+func originOrDefault(origin entity.MentionOrigin) entity.MentionOrigin {
+	if origin == "" {
+		return entity.MentionOriginBody
+	}
+	return origin
+}
+
+// sourceOrDefault 取得元が未指定の場合はQiitaとみなす
+func sourceOrDefault(source string) string {
+	if source == "" {
+		return entity.SourceQiita
+	}
+	return source
+}
+
+// nullIfEmpty 空文字の場合はNULLとして扱う
+func nullIfEmpty(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
 // joinStrings 文字列スライスを結合
 func joinStrings(strs []string, sep string) string {
 	if len(strs) == 0 {
@@ -490,3 +733,1030 @@ func convertISBN13to10(isbn13 string) *string {
 	isbn10 := body + checkChar
 	return &isbn10
 }
+
+// GetBooksForMetadataRefresh 最終取得から minAge 以上経過した書籍を、経過日数×人気度の高い順に取得
+// 人気度は累計スコアの対数で重み付けし、スコアのない書籍も経過日数に応じて対象になるようにする
+func (r *BatchRepositoryImpl) GetBooksForMetadataRefresh(ctx context.Context, minAge time.Duration, limit int) ([]*repository.BookForMetadataRefresh, error) {
+	query := `
+		SELECT b.id, b.title, COALESCE(s.total_score, 0),
+			b.price, b.rakuten_average_rating, b.rakuten_review_count,
+			b.rakuten_availability, b.thumbnail_url
+		FROM books b
+		LEFT JOIN (
+			SELECT book_id, SUM(score) AS total_score
+			FROM book_scores_daily
+			GROUP BY book_id
+		) s ON s.book_id = b.id
+		WHERE COALESCE(b.metadata_refreshed_at, b.created_at) < NOW() - make_interval(secs => $1)
+		ORDER BY
+			EXTRACT(EPOCH FROM NOW() - COALESCE(b.metadata_refreshed_at, b.created_at)) / 86400.0
+			* LN(2 + GREATEST(COALESCE(s.total_score, 0), 0)) DESC
+		LIMIT $2
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, minAge.Seconds(), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get books for metadata refresh: %w", err)
+	}
+	defer rows.Close()
+
+	var books []*repository.BookForMetadataRefresh
+	for rows.Next() {
+		var book repository.BookForMetadataRefresh
+		var price, reviewCount sql.NullInt64
+		var rating sql.NullFloat64
+		var availability, thumbnail sql.NullString
+		if err := rows.Scan(&book.ID, &book.Title, &book.Score,
+			&price, &rating, &reviewCount, &availability, &thumbnail); err != nil {
+			return nil, fmt.Errorf("failed to scan book: %w", err)
+		}
+		book.Current = entity.BookRakutenSnapshot{
+			Price:        int(price.Int64),
+			Rating:       rating.Float64,
+			ReviewCount:  int(reviewCount.Int64),
+			Availability: availability.String,
+			Thumbnail:    thumbnail.String,
+		}
+		books = append(books, &book)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate books: %w", err)
+	}
+
+	return books, nil
+}
+
+// UpdateBookRakutenSnapshot 再取得した楽天の書籍情報で更新（空の値は既存の値を維持）
+func (r *BatchRepositoryImpl) UpdateBookRakutenSnapshot(ctx context.Context, bookID string, snapshot entity.BookRakutenSnapshot) error {
+	var price, rating, reviewCount interface{}
+	if snapshot.Price > 0 {
+		price = snapshot.Price
+	}
+	if snapshot.ReviewCount > 0 {
+		rating = snapshot.Rating
+		reviewCount = snapshot.ReviewCount
+	}
+
+	query := `
+		UPDATE books SET
+			price = COALESCE($2, price),
+			rakuten_average_rating = COALESCE($3, rakuten_average_rating),
+			rakuten_review_count = COALESCE($4, rakuten_review_count),
+			rakuten_availability = COALESCE($5, rakuten_availability),
+			thumbnail_url = COALESCE($6, thumbnail_url),
+			metadata_refreshed_at = NOW(),
+			updated_at = NOW()
+		WHERE id = $1
+	`
+	_, err := r.db.ExecContext(ctx, query, bookID,
+		price, rating, reviewCount,
+		nullIfEmpty(snapshot.Availability),
+		nullIfEmpty(snapshot.Thumbnail),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update book rakuten snapshot: %w", err)
+	}
+	return nil
+}
+
+// MarkBookMetadataRefreshed 再取得日時のみ更新（楽天で見つからなかった書籍用）
+func (r *BatchRepositoryImpl) MarkBookMetadataRefreshed(ctx context.Context, bookID string) error {
+	query := `UPDATE books SET metadata_refreshed_at = NOW() WHERE id = $1`
+	_, err := r.db.ExecContext(ctx, query, bookID)
+	if err != nil {
+		return fmt.Errorf("failed to mark book metadata refreshed: %w", err)
+	}
+	return nil
+}
+
+// GetArticlesForMetricsRefresh 指定した取得元の削除されていない記事のうち、最終更新・最後に再取得に失敗してから minAge 以上経過した記事を古い順に取得
+func (r *BatchRepositoryImpl) GetArticlesForMetricsRefresh(ctx context.Context, sources []string, minAge time.Duration, limit int) ([]*repository.ArticleForMetricsRefresh, error) {
+	if len(sources) == 0 {
+		return nil, nil
+	}
+
+	// プレースホルダーを生成（$1: 経過秒数, $2: 件数, $3以降: 取得元）
+	placeholders := make([]string, len(sources))
+	args := []interface{}{minAge.Seconds(), limit}
+	for i, source := range sources {
+		placeholders[i] = fmt.Sprintf("$%d", i+3)
+		args = append(args, source)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT id, source, title, likes, stocks, comments, updated_at
+		FROM articles
+		WHERE deleted_at IS NULL
+			AND source IN (%s)
+			AND updated_at < NOW() - make_interval(secs => $1)
+			AND (metrics_refresh_attempted_at IS NULL OR metrics_refresh_attempted_at < NOW() - make_interval(secs => $1))
+		ORDER BY GREATEST(updated_at, metrics_refresh_attempted_at) ASC
+		LIMIT $2
+	`, joinStrings(placeholders, ","))
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get articles for metrics refresh: %w", err)
+	}
+	defer rows.Close()
+
+	var articles []*repository.ArticleForMetricsRefresh
+	for rows.Next() {
+		var a repository.ArticleForMetricsRefresh
+		var likes, stocks, comments sql.NullInt64
+		if err := rows.Scan(&a.ID, &a.Source, &a.Title, &likes, &stocks, &comments, &a.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan article: %w", err)
+		}
+		a.Likes = int(likes.Int64)
+		a.Stocks = int(stocks.Int64)
+		a.Comments = int(comments.Int64)
+		articles = append(articles, &a)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate articles: %w", err)
+	}
+
+	return articles, nil
+}
+
+// UpdateArticleMetrics 再取得した記事のタイトル・いいね数・ストック数・コメント数を更新
+func (r *BatchRepositoryImpl) UpdateArticleMetrics(ctx context.Context, article *entity.Article) error {
+	query := `
+		UPDATE articles SET
+			title = $2,
+			likes = $3,
+			stocks = $4,
+			comments = $5,
+			updated_at = NOW()
+		WHERE id = $1
+	`
+	_, err := r.db.ExecContext(ctx, query, article.ID, article.Title, article.Likes, article.Stocks, article.Comments)
+	if err != nil {
+		return fmt.Errorf("failed to update article metrics: %w", err)
+	}
+	return nil
+}
+
+// MarkArticleDeleted 取得元で削除・非公開化された記事に削除日時を記録
+func (r *BatchRepositoryImpl) MarkArticleDeleted(ctx context.Context, articleID string) error {
+	query := `
+		UPDATE articles SET deleted_at = NOW(), updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL
+	`
+	_, err := r.db.ExecContext(ctx, query, articleID)
+	if err != nil {
+		return fmt.Errorf("failed to mark article deleted: %w", err)
+	}
+	return nil
+}
+
+// MarkArticleRefreshFailed 再取得に失敗した記事に失敗日時を記録（次の再取得を minAge 後まで遅らせる）
+func (r *BatchRepositoryImpl) MarkArticleRefreshFailed(ctx context.Context, articleID string) error {
+	query := `UPDATE articles SET metrics_refresh_attempted_at = NOW() WHERE id = $1`
+	_, err := r.db.ExecContext(ctx, query, articleID)
+	if err != nil {
+		return fmt.Errorf("failed to mark article refresh failed: %w", err)
+	}
+	return nil
+}
+
+// GetLinkedBookIDs 記事に紐付いている書籍IDを取得
+func (r *BatchRepositoryImpl) GetLinkedBookIDs(ctx context.Context, articleID string) ([]string, error) {
+	query := `SELECT DISTINCT book_id FROM article_books WHERE article_id = $1`
+	rows, err := r.db.QueryContext(ctx, query, articleID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get linked book IDs: %w", err)
+	}
+	defer rows.Close()
+
+	var bookIDs []string
+	for rows.Next() {
+		var bookID string
+		if err := rows.Scan(&bookID); err != nil {
+			return nil, fmt.Errorf("failed to scan book ID: %w", err)
+		}
+		bookIDs = append(bookIDs, bookID)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate book IDs: %w", err)
+	}
+
+	return bookIDs, nil
+}
+
+// GetBookIDsForScoring 日次スコアまたは記事-書籍の紐付けがある書籍IDをすべて取得（スコアを作り直す対象）
+func (r *BatchRepositoryImpl) GetBookIDsForScoring(ctx context.Context) ([]string, error) {
+	query := `
+		SELECT book_id FROM book_scores_daily
+		UNION
+		SELECT book_id FROM article_books
+		ORDER BY book_id
+	`
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get book IDs for scoring: %w", err)
+	}
+	defer rows.Close()
+
+	var bookIDs []string
+	for rows.Next() {
+		var bookID string
+		if err := rows.Scan(&bookID); err != nil {
+			return nil, fmt.Errorf("failed to scan book ID: %w", err)
+		}
+		bookIDs = append(bookIDs, bookID)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate book IDs: %w", err)
+	}
+
+	return bookIDs, nil
+}
+
+// GetBookMentionsForScoring 削除されていない記事から書籍への言及を記事ごとに1件（本文を優先）取得
+func (r *BatchRepositoryImpl) GetBookMentionsForScoring(ctx context.Context, bookID string) ([]*repository.BookMentionForScoring, error) {
+	query := `
+		SELECT DISTINCT ON (a.id)
+			a.id, a.source, COALESCE(a.published_at, a.created_at),
+			COALESCE(a.likes, 0), COALESCE(a.stocks, 0), COALESCE(am.hatena_bookmarks, 0),
+			ab.origin, COALESCE(ab.confidence, 1.0), COALESCE(ab.sentiment, '')
+		FROM article_books ab
+		INNER JOIN articles a ON ab.article_id = a.id
+		LEFT JOIN article_metrics am ON a.id = am.article_id
+		WHERE ab.book_id = $1 AND a.deleted_at IS NULL
+		ORDER BY a.id, (ab.origin = 'body') DESC
+	`
+	rows, err := r.db.QueryContext(ctx, query, bookID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get book mentions: %w", err)
+	}
+	defer rows.Close()
+
+	var mentions []*repository.BookMentionForScoring
+	for rows.Next() {
+		article := &entity.Article{}
+		mention := &repository.BookMentionForScoring{Article: article}
+		if err := rows.Scan(
+			&article.ID, &article.Source, &article.PublishedAt,
+			&article.Likes, &article.Stocks, &article.HatenaBookmarks,
+			&mention.Origin, &mention.Confidence, &mention.Sentiment,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan book mention: %w", err)
+		}
+		mentions = append(mentions, mention)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate book mentions: %w", err)
+	}
+
+	return mentions, nil
+}
+
+// ReplaceBookScoresDaily 書籍の日次スコアをすべて置き換える（scoresが空の場合は削除のみ）
+func (r *BatchRepositoryImpl) ReplaceBookScoresDaily(ctx context.Context, bookID string, scores []*entity.BookScore) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM book_scores_daily WHERE book_id = $1`, bookID); err != nil {
+		return fmt.Errorf("failed to delete book scores daily: %w", err)
+	}
+
+	query := `
+		INSERT INTO book_scores_daily (book_id, date, score, article_count, created_at)
+		VALUES ($1, $2, $3, $4, NOW())
+	`
+	for _, score := range scores {
+		date := score.LatestArticleDate.Truncate(24 * time.Hour)
+		if _, err := tx.ExecContext(ctx, query, bookID, date, score.Score, score.ArticleCount); err != nil {
+			return fmt.Errorf("failed to insert book score daily: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// GetCrawlStates 取得元のクエリごとの取得状態を取得
+func (r *BatchRepositoryImpl) GetCrawlStates(ctx context.Context, source string) ([]*entity.CrawlState, error) {
+	query := `
+		SELECT source, query, window_start, window_end, window_days, page, completed_at,
+		       last_new_fetched_at, runs, fetched_count, new_count, last_new_count
+		FROM crawl_states
+		WHERE source = $1
+	`
+	rows, err := r.db.QueryContext(ctx, query, source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get crawl states: %w", err)
+	}
+	defer rows.Close()
+
+	var states []*entity.CrawlState
+	for rows.Next() {
+		var state entity.CrawlState
+		var windowStart, windowEnd, completedAt, lastNewFetchedAt sql.NullTime
+		if err := rows.Scan(&state.Source, &state.Query, &windowStart, &windowEnd,
+			&state.WindowDays, &state.Page, &completedAt,
+			&lastNewFetchedAt, &state.Runs, &state.FetchedCount, &state.NewCount, &state.LastNewCount); err != nil {
+			return nil, fmt.Errorf("failed to scan crawl state: %w", err)
+		}
+		if windowStart.Valid {
+			state.WindowStart = windowStart.Time
+		}
+		if windowEnd.Valid {
+			state.WindowEnd = windowEnd.Time
+		}
+		if completedAt.Valid {
+			state.CompletedAt = &completedAt.Time
+		}
+		if lastNewFetchedAt.Valid {
+			state.LastNewFetchedAt = &lastNewFetchedAt.Time
+		}
+		states = append(states, &state)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate crawl states: %w", err)
+	}
+
+	return states, nil
+}
+
+// SaveCrawlState クエリの取得状態を保存
+func (r *BatchRepositoryImpl) SaveCrawlState(ctx context.Context, state *entity.CrawlState) error {
+	var windowStart, windowEnd interface{}
+	if state.IsStarted() {
+		windowStart = state.WindowStart
+		windowEnd = state.WindowEnd
+	}
+
+	query := `
+		INSERT INTO crawl_states (
+			source, query, window_start, window_end, window_days, page, completed_at,
+			last_new_fetched_at, runs, fetched_count, new_count, last_new_count
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		ON CONFLICT (source, query) DO UPDATE SET
+			window_start = EXCLUDED.window_start,
+			window_end = EXCLUDED.window_end,
+			window_days = EXCLUDED.window_days,
+			page = EXCLUDED.page,
+			completed_at = EXCLUDED.completed_at,
+			last_new_fetched_at = EXCLUDED.last_new_fetched_at,
+			runs = EXCLUDED.runs,
+			fetched_count = EXCLUDED.fetched_count,
+			new_count = EXCLUDED.new_count,
+			last_new_count = EXCLUDED.last_new_count
+	`
+	_, err := r.db.ExecContext(ctx, query,
+		state.Source,
+		state.Query,
+		windowStart,
+		windowEnd,
+		state.WindowDays,
+		state.Page,
+		state.CompletedAt,
+		state.LastNewFetchedAt,
+		state.Runs,
+		state.FetchedCount,
+		state.NewCount,
+		state.LastNewCount,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save crawl state: %w", err)
+	}
+	return nil
+}
+
+// GetSearchQueries 検索クエリを優先度の高い順に取得
+func (r *BatchRepositoryImpl) GetSearchQueries(ctx context.Context, source string, enabledOnly bool) ([]*entity.SearchQuery, error) {
+	query := `
+		SELECT id, source, query, enabled, priority, created_at, updated_at
+		FROM search_queries
+		WHERE ($1 = '' OR source = $1)
+		  AND (NOT $2 OR enabled)
+		ORDER BY source, priority DESC, id
+	`
+	rows, err := r.db.QueryContext(ctx, query, source, enabledOnly)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get search queries: %w", err)
+	}
+	defer rows.Close()
+
+	var queries []*entity.SearchQuery
+	for rows.Next() {
+		var q entity.SearchQuery
+		if err := rows.Scan(&q.ID, &q.Source, &q.Query, &q.Enabled, &q.Priority, &q.CreatedAt, &q.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan search query: %w", err)
+		}
+		queries = append(queries, &q)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate search queries: %w", err)
+	}
+
+	return queries, nil
+}
+
+// AddSearchQuery 検索クエリを登録（登録済みの場合は有効化して優先度を更新）
+func (r *BatchRepositoryImpl) AddSearchQuery(ctx context.Context, q *entity.SearchQuery) error {
+	query := `
+		INSERT INTO search_queries (source, query, enabled, priority)
+		VALUES ($1, $2, TRUE, $3)
+		ON CONFLICT (source, query) DO UPDATE SET
+			enabled = TRUE,
+			priority = EXCLUDED.priority
+		RETURNING id, enabled, created_at, updated_at
+	`
+	err := r.db.QueryRowContext(ctx, query, q.Source, q.Query, q.Priority).
+		Scan(&q.ID, &q.Enabled, &q.CreatedAt, &q.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to add search query: %w", err)
+	}
+	return nil
+}
+
+// SetSearchQueryEnabled 検索クエリの有効・無効を切り替える
+func (r *BatchRepositoryImpl) SetSearchQueryEnabled(ctx context.Context, id int64, enabled bool) error {
+	return r.updateSearchQuery(ctx, `UPDATE search_queries SET enabled = $2 WHERE id = $1`, id, enabled)
+}
+
+// SetSearchQueryPriority 検索クエリの優先度を変更
+func (r *BatchRepositoryImpl) SetSearchQueryPriority(ctx context.Context, id int64, priority int) error {
+	return r.updateSearchQuery(ctx, `UPDATE search_queries SET priority = $2 WHERE id = $1`, id, priority)
+}
+
+// updateSearchQuery 検索クエリを1件更新（該当するクエリがない場合はエラー）
+func (r *BatchRepositoryImpl) updateSearchQuery(ctx context.Context, query string, id int64, value interface{}) error {
+	res, err := r.db.ExecContext(ctx, query, id, value)
+	if err != nil {
+		return fmt.Errorf("failed to update search query: %w", err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to update search query: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("search query not found: %d", id)
+	}
+	return nil
+}
+
+// SaveSearchQueryRun 検索クエリの1回の取得実績を保存
+func (r *BatchRepositoryImpl) SaveSearchQueryRun(ctx context.Context, run *entity.SearchQueryRun) error {
+	query := `
+		INSERT INTO search_query_runs (
+			source, query, fetch_mode, fetched, new_count, duplicates,
+			articles_processed, articles_with_books, books_linked, run_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`
+	_, err := r.db.ExecContext(ctx, query,
+		run.Source,
+		run.Query,
+		run.FetchMode,
+		run.Fetched,
+		run.New,
+		run.Duplicates,
+		run.ArticlesProcessed,
+		run.ArticlesWithBooks,
+		run.BooksLinked,
+		run.RunAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save search query run: %w", err)
+	}
+	return nil
+}
+
+// GetSearchQueryStats 検索クエリごとの取得実績の累計を取得（未実行のクエリも含む）
+func (r *BatchRepositoryImpl) GetSearchQueryStats(ctx context.Context, source string) ([]*repository.SearchQueryStats, error) {
+	query := `
+		SELECT
+			q.id, q.source, q.query, q.enabled, q.priority, q.created_at, q.updated_at,
+			COUNT(r.id),
+			COALESCE(SUM(r.fetched), 0),
+			COALESCE(SUM(r.new_count), 0),
+			COALESCE(SUM(r.duplicates), 0),
+			COALESCE(SUM(r.articles_processed), 0),
+			COALESCE(SUM(r.articles_with_books), 0),
+			COALESCE(SUM(r.books_linked), 0),
+			MAX(r.run_at)
+		FROM search_queries q
+		LEFT JOIN search_query_runs r ON r.source = q.source AND r.query = q.query
+		WHERE ($1 = '' OR q.source = $1)
+		GROUP BY q.id
+		ORDER BY q.source, q.priority DESC, q.id
+	`
+	rows, err := r.db.QueryContext(ctx, query, source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get search query stats: %w", err)
+	}
+	defer rows.Close()
+
+	var stats []*repository.SearchQueryStats
+	for rows.Next() {
+		var q entity.SearchQuery
+		var s repository.SearchQueryStats
+		var lastRunAt sql.NullTime
+		if err := rows.Scan(
+			&q.ID, &q.Source, &q.Query, &q.Enabled, &q.Priority, &q.CreatedAt, &q.UpdatedAt,
+			&s.Runs,
+			&s.Total.Fetched,
+			&s.Total.New,
+			&s.Total.Duplicates,
+			&s.Total.ArticlesProcessed,
+			&s.Total.ArticlesWithBooks,
+			&s.Total.BooksLinked,
+			&lastRunAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan search query stats: %w", err)
+		}
+		s.Query = &q
+		s.Total.Source = q.Source
+		s.Total.Query = q.Query
+		if lastRunAt.Valid {
+			s.LastRunAt = &lastRunAt.Time
+		}
+		stats = append(stats, &s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate search query stats: %w", err)
+	}
+
+	return stats, nil
+}
+
+// EnqueueRetry 処理を延期した記事を登録（登録済みの場合は延期した回数を加算）
+func (r *BatchRepositoryImpl) EnqueueRetry(ctx context.Context, item *entity.RetryQueueItem) error {
+	query := `
+		INSERT INTO retry_queue (source, article_id, reason)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (source, article_id) DO UPDATE SET
+			reason = EXCLUDED.reason,
+			attempts = retry_queue.attempts + 1
+	`
+	_, err := r.db.ExecContext(ctx, query, item.Source, item.ArticleID, item.Reason)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue retry: %w", err)
+	}
+	return nil
+}
+
+// GetRetryQueue 処理を延期した記事を延期した日時の古い順に取得
+func (r *BatchRepositoryImpl) GetRetryQueue(ctx context.Context, limit int) ([]*entity.RetryQueueItem, error) {
+	query := `
+		SELECT source, article_id, reason, attempts, created_at, updated_at
+		FROM retry_queue
+		ORDER BY updated_at
+		LIMIT $1
+	`
+	rows, err := r.db.QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get retry queue: %w", err)
+	}
+	defer rows.Close()
+
+	var items []*entity.RetryQueueItem
+	for rows.Next() {
+		var item entity.RetryQueueItem
+		if err := rows.Scan(&item.Source, &item.ArticleID, &item.Reason, &item.Attempts, &item.CreatedAt, &item.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan retry queue item: %w", err)
+		}
+		items = append(items, &item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate retry queue: %w", err)
+	}
+
+	return items, nil
+}
+
+// DeleteRetry 処理を延期した記事を削除
+func (r *BatchRepositoryImpl) DeleteRetry(ctx context.Context, source, articleID string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM retry_queue WHERE source = $1 AND article_id = $2`, source, articleID)
+	if err != nil {
+		return fmt.Errorf("failed to delete retry: %w", err)
+	}
+	return nil
+}
+
+// AddAPIUsage 日付・プロバイダ・エンドポイントごとの呼び出し回数を加算
+func (r *BatchRepositoryImpl) AddAPIUsage(ctx context.Context, usage *entity.APIUsage) error {
+	query := `
+		INSERT INTO api_usage (usage_date, provider, endpoint, calls)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (usage_date, provider, endpoint) DO UPDATE SET
+			calls = api_usage.calls + EXCLUDED.calls,
+			updated_at = CURRENT_TIMESTAMP
+	`
+	_, err := r.db.ExecContext(ctx, query, usage.UsageDate, usage.Provider, usage.Endpoint, usage.Calls)
+	if err != nil {
+		return fmt.Errorf("failed to add api usage: %w", err)
+	}
+	return nil
+}
+
+// GetAPIUsage 指定日の呼び出し回数をプロバイダ・エンドポイントごとに取得
+func (r *BatchRepositoryImpl) GetAPIUsage(ctx context.Context, usageDate time.Time) ([]*entity.APIUsage, error) {
+	query := `
+		SELECT usage_date, provider, endpoint, calls
+		FROM api_usage
+		WHERE usage_date = $1
+		ORDER BY provider, endpoint
+	`
+	rows, err := r.db.QueryContext(ctx, query, usageDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get api usage: %w", err)
+	}
+	defer rows.Close()
+
+	var usages []*entity.APIUsage
+	for rows.Next() {
+		var usage entity.APIUsage
+		if err := rows.Scan(&usage.UsageDate, &usage.Provider, &usage.Endpoint, &usage.Calls); err != nil {
+			return nil, fmt.Errorf("failed to scan api usage: %w", err)
+		}
+		usages = append(usages, &usage)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate api usage: %w", err)
+	}
+
+	return usages, nil
+}
+
+// GetBookLookup 書籍メタデータの検索結果のキャッシュを取得（未登録の場合は nil）
+func (r *BatchRepositoryImpl) GetBookLookup(ctx context.Context, lookupType entity.BookLookupType, key string) (*entity.BookLookup, error) {
+	query := `
+		SELECT lookup_type, lookup_key, isbn, misses, checked_at, next_check_at
+		FROM book_lookups
+		WHERE lookup_type = $1 AND lookup_key = $2
+	`
+	var lookup entity.BookLookup
+	var isbn sql.NullString
+	var nextCheckAt sql.NullTime
+	err := r.db.QueryRowContext(ctx, query, lookupType, key).Scan(
+		&lookup.Type,
+		&lookup.Key,
+		&isbn,
+		&lookup.Misses,
+		&lookup.CheckedAt,
+		&nextCheckAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get book lookup: %w", err)
+	}
+
+	lookup.ISBN = isbn.String
+	if nextCheckAt.Valid {
+		lookup.NextCheckAt = nextCheckAt.Time
+	}
+	return &lookup, nil
+}
+
+// SaveBookLookup 書籍メタデータの検索結果のキャッシュを保存（登録済みの場合は上書き）
+func (r *BatchRepositoryImpl) SaveBookLookup(ctx context.Context, lookup *entity.BookLookup) error {
+	query := `
+		INSERT INTO book_lookups (lookup_type, lookup_key, isbn, misses, checked_at, next_check_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (lookup_type, lookup_key) DO UPDATE SET
+			isbn = EXCLUDED.isbn,
+			misses = EXCLUDED.misses,
+			checked_at = EXCLUDED.checked_at,
+			next_check_at = EXCLUDED.next_check_at
+	`
+	var nextCheckAt interface{}
+	if !lookup.NextCheckAt.IsZero() {
+		nextCheckAt = lookup.NextCheckAt
+	}
+	_, err := r.db.ExecContext(ctx, query,
+		lookup.Type,
+		lookup.Key,
+		nullIfEmpty(lookup.ISBN),
+		lookup.Misses,
+		lookup.CheckedAt,
+		nextCheckAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save book lookup: %w", err)
+	}
+	return nil
+}
+
+// CreateBatchCheckpoint 処理する記事とともに進捗を作成（checkpoint.ID・CreatedAt を設定する）
+func (r *BatchRepositoryImpl) CreateBatchCheckpoint(ctx context.Context, checkpoint *entity.BatchCheckpoint) error {
+	sources, err := json.Marshal(checkpoint.Sources)
+	if err != nil {
+		return fmt.Errorf("failed to marshal checkpoint sources: %w", err)
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO batch_checkpoints (status, fetch_mode, sources)
+		VALUES ($1, $2, $3)
+		RETURNING id, created_at
+	`
+	if err := tx.QueryRowContext(ctx, query, checkpoint.Status, checkpoint.FetchMode, sources).Scan(&checkpoint.ID, &checkpoint.CreatedAt); err != nil {
+		return fmt.Errorf("failed to insert batch checkpoint: %w", err)
+	}
+
+	articleQuery := `
+		INSERT INTO batch_checkpoint_articles (checkpoint_id, article_id, source, position, edited, retried, processed)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (checkpoint_id, article_id) DO NOTHING
+	`
+	for i, article := range checkpoint.Articles {
+		if _, err := tx.ExecContext(ctx, articleQuery, checkpoint.ID, article.ArticleID, article.Source, i, article.Edited, article.Retried, article.Processed); err != nil {
+			return fmt.Errorf("failed to insert batch checkpoint article: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// GetBatchCheckpoint 未完了の進捗を記事とともに取得（ない場合は nil）
+func (r *BatchRepositoryImpl) GetBatchCheckpoint(ctx context.Context) (*entity.BatchCheckpoint, error) {
+	query := `
+		SELECT id, status, fetch_mode, sources, scores, recompute_book_ids, created_at, updated_at
+		FROM batch_checkpoints
+		ORDER BY id DESC
+		LIMIT 1
+	`
+	var checkpoint entity.BatchCheckpoint
+	var sources, scores, recomputeBookIDs []byte
+	err := r.db.QueryRowContext(ctx, query).Scan(
+		&checkpoint.ID,
+		&checkpoint.Status,
+		&checkpoint.FetchMode,
+		&sources,
+		&scores,
+		&recomputeBookIDs,
+		&checkpoint.CreatedAt,
+		&checkpoint.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get batch checkpoint: %w", err)
+	}
+
+	if err := json.Unmarshal(sources, &checkpoint.Sources); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal checkpoint sources: %w", err)
+	}
+	if err := json.Unmarshal(scores, &checkpoint.Scores); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal checkpoint scores: %w", err)
+	}
+	if err := json.Unmarshal(recomputeBookIDs, &checkpoint.RecomputeBookIDs); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal checkpoint recompute book ids: %w", err)
+	}
+
+	articleQuery := `
+		SELECT article_id, source, edited, retried, processed
+		FROM batch_checkpoint_articles
+		WHERE checkpoint_id = $1
+		ORDER BY position
+	`
+	rows, err := r.db.QueryContext(ctx, articleQuery, checkpoint.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get batch checkpoint articles: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var article entity.CheckpointArticle
+		if err := rows.Scan(&article.ArticleID, &article.Source, &article.Edited, &article.Retried, &article.Processed); err != nil {
+			return nil, fmt.Errorf("failed to scan batch checkpoint article: %w", err)
+		}
+		checkpoint.Articles = append(checkpoint.Articles, &article)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate batch checkpoint articles: %w", err)
+	}
+
+	return &checkpoint, nil
+}
+
+// SaveBatchCheckpointProgress 処理済みの記事と集計途中のスコア・再計算する書籍を1つのトランザクションで保存
+func (r *BatchRepositoryImpl) SaveBatchCheckpointProgress(ctx context.Context, checkpoint *entity.BatchCheckpoint, processedArticleIDs []string) error {
+	scores, err := json.Marshal(checkpoint.Scores)
+	if err != nil {
+		return fmt.Errorf("failed to marshal checkpoint scores: %w", err)
+	}
+	recomputeBookIDs, err := json.Marshal(checkpoint.RecomputeBookIDs)
+	if err != nil {
+		return fmt.Errorf("failed to marshal checkpoint recompute book ids: %w", err)
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `UPDATE batch_checkpoint_articles SET processed = TRUE WHERE checkpoint_id = $1 AND article_id = $2`
+	for _, articleID := range processedArticleIDs {
+		if _, err := tx.ExecContext(ctx, query, checkpoint.ID, articleID); err != nil {
+			return fmt.Errorf("failed to mark checkpoint article processed: %w", err)
+		}
+	}
+
+	query = `UPDATE batch_checkpoints SET scores = $2, recompute_book_ids = $3 WHERE id = $1`
+	if _, err := tx.ExecContext(ctx, query, checkpoint.ID, scores, recomputeBookIDs); err != nil {
+		return fmt.Errorf("failed to update batch checkpoint: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// SaveBatchCheckpointScores 集計したスコアを book_scores_daily に加算し、進捗をスコア保存済みにする（1つのトランザクション）
+func (r *BatchRepositoryImpl) SaveBatchCheckpointScores(ctx context.Context, checkpoint *entity.BatchCheckpoint) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, score := range checkpoint.Scores {
+		// 日付は紐づく記事の最新投稿日（日付のみ）
+		date := score.LatestArticleDate.Truncate(24 * time.Hour)
+		if _, err := tx.ExecContext(ctx, addBookScoreDailyQuery, score.BookID, date, score.Score, score.ArticleCount); err != nil {
+			return fmt.Errorf("failed to save book score daily: %w", err)
+		}
+	}
+
+	query := `UPDATE batch_checkpoints SET status = $2 WHERE id = $1`
+	if _, err := tx.ExecContext(ctx, query, checkpoint.ID, entity.BatchCheckpointScoresSaved); err != nil {
+		return fmt.Errorf("failed to update batch checkpoint status: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	checkpoint.Status = entity.BatchCheckpointScoresSaved
+	return nil
+}
+
+// DeleteBatchCheckpoint 完了したバッチの進捗を削除
+func (r *BatchRepositoryImpl) DeleteBatchCheckpoint(ctx context.Context, id int64) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM batch_checkpoints WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete batch checkpoint: %w", err)
+	}
+	return nil
+}
+
+// CreateBatchRun 実行の開始を記録（run.ID を設定する）
+func (r *BatchRepositoryImpl) CreateBatchRun(ctx context.Context, run *entity.BatchRun) error {
+	params, err := json.Marshal(run.Params)
+	if err != nil {
+		return fmt.Errorf("failed to marshal batch run params: %w", err)
+	}
+
+	query := `
+		INSERT INTO batch_runs (batch_type, mode, params, outcome, started_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`
+	if err := r.db.QueryRowContext(ctx, query, run.BatchType, run.Mode, params, run.Outcome, run.StartedAt).Scan(&run.ID); err != nil {
+		return fmt.Errorf("failed to insert batch run: %w", err)
+	}
+	return nil
+}
+
+// FinishBatchRun 実行結果（件数・検索クエリごとの取得実績・結果・エラー）を記録
+func (r *BatchRepositoryImpl) FinishBatchRun(ctx context.Context, run *entity.BatchRun) error {
+	counters, err := json.Marshal(run.Counters)
+	if err != nil {
+		return fmt.Errorf("failed to marshal batch run counters: %w", err)
+	}
+	queryStats, err := json.Marshal(run.QueryStats)
+	if err != nil {
+		return fmt.Errorf("failed to marshal batch run query stats: %w", err)
+	}
+
+	query := `
+		UPDATE batch_runs
+		SET mode = $2, counters = $3, query_stats = $4, outcome = $5, error_summary = $6,
+			finished_at = $7, duration_ms = $8
+		WHERE id = $1
+	`
+	_, err = r.db.ExecContext(ctx, query,
+		run.ID, run.Mode, counters, queryStats, run.Outcome, run.ErrorSummary, run.FinishedAt, run.DurationMs)
+	if err != nil {
+		return fmt.Errorf("failed to update batch run: %w", err)
+	}
+	return nil
+}
+
+// ListBatchRuns 実行履歴を新しい順に取得（batchType が空の場合はすべての種類）
+func (r *BatchRepositoryImpl) ListBatchRuns(ctx context.Context, batchType string, limit int) ([]*entity.BatchRun, error) {
+	query := `
+		SELECT id, batch_type, mode, params, counters, query_stats, outcome, error_summary, started_at, finished_at, duration_ms
+		FROM batch_runs
+		WHERE $1 = '' OR batch_type = $1
+		ORDER BY started_at DESC, id DESC
+		LIMIT $2
+	`
+	rows, err := r.db.QueryContext(ctx, query, batchType, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query batch runs: %w", err)
+	}
+	defer rows.Close()
+
+	var runs []*entity.BatchRun
+	for rows.Next() {
+		run, err := scanBatchRun(rows)
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, run)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate batch runs: %w", err)
+	}
+	return runs, nil
+}
+
+// GetBatchRun 実行履歴を取得（ない場合は nil）
+func (r *BatchRepositoryImpl) GetBatchRun(ctx context.Context, id int64) (*entity.BatchRun, error) {
+	query := `
+		SELECT id, batch_type, mode, params, counters, query_stats, outcome, error_summary, started_at, finished_at, duration_ms
+		FROM batch_runs
+		WHERE id = $1
+	`
+	run, err := scanBatchRun(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return run, err
+}
+
+// scanBatchRun batch_runs の1行を読み込む
+func scanBatchRun(row interface{ Scan(dest ...any) error }) (*entity.BatchRun, error) {
+	var run entity.BatchRun
+	var params, counters, queryStats []byte
+	var finishedAt sql.NullTime
+	err := row.Scan(
+		&run.ID,
+		&run.BatchType,
+		&run.Mode,
+		&params,
+		&counters,
+		&queryStats,
+		&run.Outcome,
+		&run.ErrorSummary,
+		&run.StartedAt,
+		&finishedAt,
+		&run.DurationMs,
+	)
+	if err == sql.ErrNoRows {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan batch run: %w", err)
+	}
+	if finishedAt.Valid {
+		run.FinishedAt = &finishedAt.Time
+	}
+
+	if err := json.Unmarshal(params, &run.Params); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal batch run params: %w", err)
+	}
+	if err := json.Unmarshal(counters, &run.Counters); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal batch run counters: %w", err)
+	}
+	if err := json.Unmarshal(queryStats, &run.QueryStats); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal batch run query stats: %w", err)
+	}
+	return &run, nil
+}
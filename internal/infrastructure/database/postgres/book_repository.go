@@ -29,7 +29,7 @@ func (r *BookRepositoryImpl) GetTopBooksByCategory(ctx context.Context, category
 
 // GetRankings 総合ランキングを取得
 // book_scores_dailyテーブルからスコアが高い順に書籍を取得
-func (r *BookRepositoryImpl) GetRankings(ctx context.Context, rangeType string, limit int, offset int, categoryID string) ([]*entity.Book, error) {
+func (r *BookRepositoryImpl) GetRankings(ctx context.Context, rangeType string, limit int, offset int, categoryID string, source string) ([]*entity.Book, error) {
 	// 日付範囲を決定
 	var dateCondition string
 	args := []interface{}{}
@@ -56,6 +56,18 @@ func (r *BookRepositoryImpl) GetRankings(ctx context.Context, rangeType string,
 		argIndex++
 	}
 
+	// 取得元フィルタ（その取得元の削除されていない記事で言及されている書籍のみ）
+	var sourceCondition string
+	if source != "" {
+		sourceCondition = fmt.Sprintf(`AND EXISTS (
+			SELECT 1 FROM article_books ab
+			INNER JOIN articles a ON ab.article_id = a.id
+			WHERE ab.book_id = b.id AND a.source = $%d AND a.deleted_at IS NULL
+		)`, argIndex)
+		args = append(args, source)
+		argIndex++
+	}
+
 	// 集計クエリを構築
 	query := fmt.Sprintf(`
 		SELECT
@@ -73,10 +85,10 @@ func (r *BookRepositoryImpl) GetRankings(ctx context.Context, rangeType string,
 		FROM books b
 		INNER JOIN book_scores_daily bsd ON b.id = bsd.book_id
 		%s
-		WHERE 1=1 %s
+		WHERE 1=1 %s %s
 		GROUP BY b.id, b.title, b.author, b.rakuten_average_rating, b.rakuten_review_count, b.published_date, b.thumbnail_url, b.amazon_url, b.rakuten_url
 		ORDER BY total_score DESC, total_article_count DESC, b.id
-	`, categoryJoin, dateCondition)
+	`, categoryJoin, dateCondition, sourceCondition)
 
 	// limitとoffsetを追加（limit=0は全件取得）
 	if limit > 0 {
@@ -145,13 +157,14 @@ func (r *BookRepositoryImpl) GetRankings(ctx context.Context, rangeType string,
 	return books, nil
 }
 
-// getBookTags 書籍に紐づくタグを取得（article_tagsから集計）
+// getBookTags 書籍に紐づくタグを取得（削除されていない記事のarticle_tagsから集計）
 func (r *BookRepositoryImpl) getBookTags(ctx context.Context, bookID string) ([]string, error) {
 	query := `
 		SELECT DISTINCT at.tag_name
 		FROM article_tags at
 		INNER JOIN article_books ab ON at.article_id = ab.article_id
-		WHERE ab.book_id = $1
+		INNER JOIN articles a ON ab.article_id = a.id
+		WHERE ab.book_id = $1 AND a.deleted_at IS NULL
 		LIMIT 5
 	`
 	rows, err := r.db.QueryContext(ctx, query, bookID)
@@ -241,22 +254,69 @@ func (r *BookRepositoryImpl) GetBookByID(ctx context.Context, bookID string) (*e
 		bookDetail.QiitaArticles = []entity.QiitaArticle{}
 	}
 
+	// 言及の極性集計を取得
+	sentimentSummary, err := r.getSentimentSummary(ctx, bookID)
+	if err == nil {
+		bookDetail.MentionSentiment = *sentimentSummary
+	}
+
 	return &bookDetail, nil
 }
 
-// getQiitaArticles 書籍に紐づくQiita記事を取得
+// getSentimentSummary 書籍への言及の極性を集計（削除された記事の言及は除く）
+func (r *BookRepositoryImpl) getSentimentSummary(ctx context.Context, bookID string) (*entity.SentimentSummary, error) {
+	query := `
+		SELECT ab.sentiment, COUNT(*)
+		FROM article_books ab
+		INNER JOIN articles a ON ab.article_id = a.id
+		WHERE ab.book_id = $1 AND ab.sentiment IS NOT NULL AND a.deleted_at IS NULL
+		GROUP BY ab.sentiment
+	`
+	rows, err := r.db.QueryContext(ctx, query, bookID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sentiment summary: %w", err)
+	}
+	defer rows.Close()
+
+	var summary entity.SentimentSummary
+	for rows.Next() {
+		var label entity.Sentiment
+		var count int
+		if err := rows.Scan(&label, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan sentiment: %w", err)
+		}
+		switch label {
+		case entity.SentimentPositive:
+			summary.Positive = count
+		case entity.SentimentNeutral:
+			summary.Neutral = count
+		case entity.SentimentNegative:
+			summary.Negative = count
+		}
+	}
+
+	return &summary, nil
+}
+
+// getQiitaArticles 書籍に紐づく記事を取得（削除された記事は除く）
 func (r *BookRepositoryImpl) getQiitaArticles(ctx context.Context, bookID string) ([]entity.QiitaArticle, error) {
+	// 本文とコメントの両方で言及されている記事は本文の抜粋を優先して1件にまとめる
 	query := `
-		SELECT
-			a.title,
-			a.url,
-			a.likes,
-			a.stocks,
-			a.comments
-		FROM articles a
-		INNER JOIN article_books ab ON a.id = ab.article_id
-		WHERE ab.book_id = $1
-		ORDER BY a.likes DESC
+		SELECT title, url, likes, stocks, comments, excerpt
+		FROM (
+			SELECT DISTINCT ON (a.id)
+				a.title,
+				a.url,
+				a.likes,
+				a.stocks,
+				a.comments,
+				COALESCE(ab.excerpt, '') as excerpt
+			FROM articles a
+			INNER JOIN article_books ab ON a.id = ab.article_id
+			WHERE ab.book_id = $1 AND a.deleted_at IS NULL
+			ORDER BY a.id, (ab.origin = 'body') DESC
+		) mentioned
+		ORDER BY likes DESC
 		LIMIT 10
 	`
 	rows, err := r.db.QueryContext(ctx, query, bookID)
@@ -268,7 +328,7 @@ func (r *BookRepositoryImpl) getQiitaArticles(ctx context.Context, bookID string
 	var articles []entity.QiitaArticle
 	for rows.Next() {
 		var article entity.QiitaArticle
-		if err := rows.Scan(&article.Title, &article.URL, &article.Likes, &article.Stocks, &article.Comments); err != nil {
+		if err := rows.Scan(&article.Title, &article.URL, &article.Likes, &article.Stocks, &article.Comments, &article.Excerpt); err != nil {
 			return nil, fmt.Errorf("failed to scan article: %w", err)
 		}
 		articles = append(articles, article)
@@ -0,0 +1,141 @@
+package recording
+
+import (
+	"math"
+	"sort"
+
+	"teckbook-compass-backend/internal/domain/entity"
+)
+
+// Diff ドライランで記録した書き込みを、実際に書き込んだ場合の差分としてまとめたもの
+type Diff struct {
+	NewBooks            []*NewBook            `json:"new_books"`            // 新しく保存する書籍
+	NewLinks            []*NewLink            `json:"new_links"`            // 新しく保存する記事-書籍の紐付け
+	RemovedLinks        []*RemovedLinks       `json:"removed_links"`        // 作り直すために削除する記事-書籍の紐付け
+	ScoreDeltas         []*ScoreDelta         `json:"score_deltas"`         // 書籍の累計スコアの変化（変化の大きい順）
+	CategoryAssignments []*CategoryAssignment `json:"category_assignments"` // 書籍へのカテゴリの振り分け（振り分け済みのカテゴリを含む）
+	Writes              map[string]int        `json:"writes"`               // リポジトリのメソッドごとの書き込み回数
+}
+
+// NewBook 新しく保存する書籍
+type NewBook struct {
+	ID        string `json:"id"` // ISBN-13
+	Title     string `json:"title"`
+	Author    string `json:"author"`
+	Publisher string `json:"publisher"`
+}
+
+// NewLink 新しく保存する記事-書籍の紐付け
+type NewLink struct {
+	ArticleID   string               `json:"article_id"`
+	BookID      string               `json:"book_id"`
+	Origin      entity.MentionOrigin `json:"origin"`
+	SourceType  string               `json:"source_type"`
+	Confidence  float64              `json:"confidence"`
+	MatchedText string               `json:"matched_text"`
+	Sentiment   entity.Sentiment     `json:"sentiment"`
+}
+
+// RemovedLinks 紐付けを作り直すために削除する記事の、削除前に紐付いていた書籍
+type RemovedLinks struct {
+	ArticleID string   `json:"article_id"`
+	BookIDs   []string `json:"book_ids"`
+}
+
+// ScoreDelta 書籍の累計スコア（book_scores_daily の合計）の変化
+type ScoreDelta struct {
+	BookID string  `json:"book_id"`
+	Title  string  `json:"title,omitempty"` // この実行で新しく保存する書籍のみ
+	Before float64 `json:"before"`
+	After  float64 `json:"after"`
+	Delta  float64 `json:"delta"`
+}
+
+// CategoryAssignment 書籍へのカテゴリの振り分け
+type CategoryAssignment struct {
+	BookID      string   `json:"book_id"`
+	CategoryIDs []string `json:"category_ids"`
+}
+
+// Diff 記録した書き込みから差分を作成
+func (r *BatchRepository) Diff() *Diff {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	diff := &Diff{
+		NewBooks:            []*NewBook{},
+		NewLinks:            []*NewLink{},
+		RemovedLinks:        []*RemovedLinks{},
+		ScoreDeltas:         []*ScoreDelta{},
+		CategoryAssignments: []*CategoryAssignment{},
+		Writes:              make(map[string]int, len(r.writes)),
+	}
+
+	for _, bookID := range r.bookOrder {
+		book := r.books[bookID]
+		diff.NewBooks = append(diff.NewBooks, &NewBook{
+			ID:        book.ISBN,
+			Title:     book.Title,
+			Author:    book.Author,
+			Publisher: book.Publisher,
+		})
+	}
+
+	for _, link := range r.links {
+		diff.NewLinks = append(diff.NewLinks, &NewLink{
+			ArticleID:   link.ArticleID,
+			BookID:      link.BookID,
+			Origin:      link.Origin,
+			SourceType:  link.SourceType,
+			Confidence:  link.Confidence,
+			MatchedText: link.MatchedText,
+			Sentiment:   link.Sentiment,
+		})
+	}
+
+	for articleID, bookIDs := range r.clearedLinks {
+		if len(bookIDs) == 0 {
+			continue
+		}
+		diff.RemovedLinks = append(diff.RemovedLinks, &RemovedLinks{ArticleID: articleID, BookIDs: bookIDs})
+	}
+	sort.Slice(diff.RemovedLinks, func(i, j int) bool {
+		return diff.RemovedLinks[i].ArticleID < diff.RemovedLinks[j].ArticleID
+	})
+
+	for bookID, change := range r.scores {
+		delta := change.after - change.before
+		if delta == 0 {
+			continue
+		}
+		scoreDelta := &ScoreDelta{
+			BookID: bookID,
+			Before: change.before,
+			After:  change.after,
+			Delta:  delta,
+		}
+		if book, ok := r.books[bookID]; ok {
+			scoreDelta.Title = book.Title
+		}
+		diff.ScoreDeltas = append(diff.ScoreDeltas, scoreDelta)
+	}
+	sort.Slice(diff.ScoreDeltas, func(i, j int) bool {
+		a, b := math.Abs(diff.ScoreDeltas[i].Delta), math.Abs(diff.ScoreDeltas[j].Delta)
+		if a != b {
+			return a > b
+		}
+		return diff.ScoreDeltas[i].BookID < diff.ScoreDeltas[j].BookID
+	})
+
+	for bookID, categoryIDs := range r.categories {
+		diff.CategoryAssignments = append(diff.CategoryAssignments, &CategoryAssignment{BookID: bookID, CategoryIDs: categoryIDs})
+	}
+	sort.Slice(diff.CategoryAssignments, func(i, j int) bool {
+		return diff.CategoryAssignments[i].BookID < diff.CategoryAssignments[j].BookID
+	})
+
+	for method, count := range r.writes {
+		diff.Writes[method] = count
+	}
+	return diff
+}
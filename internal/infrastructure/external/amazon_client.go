@@ -17,6 +17,9 @@ import (
 type AmazonClient struct {
 	client  paapi5.Client
 	enabled bool
+	limiter *RateLimiter    // リクエスト数の制限
+	breaker *CircuitBreaker // 障害時の遮断（nil の場合は遮断しない）
+	ledger  *UsageLedger    // 使用量の記録と1日の上限（PA-APIは売上に応じて上限が変わる）
 }
 
 // AmazonBook Amazon APIから取得した書籍情報
@@ -34,7 +37,7 @@ var ErrAmazonAPIError = errors.New("amazon api error")
 var ErrAmazonNotFound = errors.New("amazon product not found")
 
 // NewAmazonClient AmazonClientを生成
-func NewAmazonClient(cfg config.AmazonConfig) *AmazonClient {
+func NewAmazonClient(cfg config.AmazonConfig, ledger *UsageLedger) *AmazonClient {
 	if !cfg.Enabled {
 		log.Println("Amazon API: 無効")
 		return &AmazonClient{enabled: false}
@@ -52,9 +55,25 @@ func NewAmazonClient(cfg config.AmazonConfig) *AmazonClient {
 	return &AmazonClient{
 		client:  client,
 		enabled: true,
+		limiter: NewRateLimiter(cfg.RateLimit, 1),
+		breaker: NewCircuitBreaker(amazonProvider, cfg.Circuit),
+		ledger:  ledger,
 	}
 }
 
+// amazonProvider 使用量を記録するプロバイダ名
+const amazonProvider = "amazon"
+
+// Usage 当日の使用量と1日の上限
+func (c *AmazonClient) Usage() ProviderUsage {
+	return c.ledger.Usage(amazonProvider)
+}
+
+// CircuitState サーキットブレーカーの状態
+func (c *AmazonClient) CircuitState() CircuitState {
+	return c.breaker.State()
+}
+
 // IsEnabled Amazon APIが有効かどうか
 func (c *AmazonClient) IsEnabled() bool {
 	return c.enabled
@@ -73,9 +92,13 @@ func (c *AmazonClient) SearchByISBN(ctx context.Context, isbn string) (*AmazonBo
 		c.client.PartnerType(),
 	).ASINs([]string{isbn}).EnableItemInfo().EnableOffers()
 
-	// リクエスト実行
-	body, err := c.client.RequestContext(ctx, q)
+	// リクエスト実行（レート制限・1日の上限・サーキットブレーカーに従う）
+	body, err := c.request(ctx, q, "GetItems")
 	if err != nil {
+		// 遮断中・使用量の上限・キャンセルはAPIエラーとして扱わずにそのまま返す
+		if errors.Is(err, ErrCircuitOpen) || errors.Is(err, ErrBudgetExceeded) || ctx.Err() != nil {
+			return nil, err
+		}
 		log.Printf("Amazon API error: %v", err)
 		return nil, fmt.Errorf("%w: %v", ErrAmazonAPIError, err)
 	}
@@ -124,9 +147,13 @@ func (c *AmazonClient) SearchByTitle(ctx context.Context, title string) (*Amazon
 		EnableItemInfo().
 		EnableOffers()
 
-	// リクエスト実行
-	body, err := c.client.RequestContext(ctx, q)
+	// リクエスト実行（レート制限・1日の上限・サーキットブレーカーに従う）
+	body, err := c.request(ctx, q, "SearchItems")
 	if err != nil {
+		// 遮断中・使用量の上限・キャンセルはAPIエラーとして扱わずにそのまま返す
+		if errors.Is(err, ErrCircuitOpen) || errors.Is(err, ErrBudgetExceeded) || ctx.Err() != nil {
+			return nil, err
+		}
 		log.Printf("Amazon API error: %v", err)
 		return nil, fmt.Errorf("%w: %v", ErrAmazonAPIError, err)
 	}
@@ -158,3 +185,34 @@ func (c *AmazonClient) SearchByTitle(ctx context.Context, title string) (*Amazon
 		DetailURL: item.DetailPageURL,
 	}, nil
 }
+
+// request PA-APIにリクエストを送信する（レート制限・1日の上限に従い、結果をサーキットブレーカーに記録する）
+// 遮断中は送信せずに CircuitOpenError を返す
+func (c *AmazonClient) request(ctx context.Context, q paapi5.Query, endpoint string) ([]byte, error) {
+	if err := c.breaker.Allow(); err != nil {
+		return nil, err
+	}
+
+	body, err := c.send(ctx, q, endpoint)
+	switch {
+	case ctx.Err() != nil, errors.Is(err, ErrBudgetExceeded):
+		// キャンセル・タイムアウト・使用量の上限はPA-APIの障害ではないため記録しない
+		c.breaker.Release()
+	case err != nil:
+		c.breaker.Record(false)
+	default:
+		c.breaker.Record(true)
+	}
+	return body, err
+}
+
+// send レート制限・1日の上限に従ってリクエストを1回送信
+func (c *AmazonClient) send(ctx context.Context, q paapi5.Query, endpoint string) ([]byte, error) {
+	if err := c.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+	if err := c.ledger.Reserve(ctx, amazonProvider, endpoint); err != nil {
+		return nil, err
+	}
+	return c.client.RequestContext(ctx, q)
+}
@@ -0,0 +1,148 @@
+package external
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"teckbook-compass-backend/internal/infrastructure/config"
+)
+
+// ErrCircuitOpen サーキットブレーカーが遮断中のためリクエストを送信しなかった（errors.Is で判定）
+var ErrCircuitOpen = errors.New("circuit open")
+
+// CircuitOpenError 遮断中のクライアントとその解除予定時刻
+type CircuitOpenError struct {
+	Name  string    // クライアント名（qiita, rakuten など）
+	Until time.Time // 試行を再開する時刻
+}
+
+// Error エラーメッセージ
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("%s: circuit open until %s", e.Name, e.Until.Format(time.RFC3339))
+}
+
+// Is ErrCircuitOpen と一致する
+func (e *CircuitOpenError) Is(target error) bool {
+	return target == ErrCircuitOpen
+}
+
+// CircuitState サーキットブレーカーの状態
+type CircuitState string
+
+const (
+	CircuitClosed   CircuitState = "closed"    // 通常（リクエストを送信する）
+	CircuitOpen     CircuitState = "open"      // 遮断中（リクエストを送信せずにエラーを返す）
+	CircuitHalfOpen CircuitState = "half_open" // 試行中（1件だけ送信し、結果で closed / open に戻す）
+)
+
+// CircuitBreaker 外部APIクライアントごとのサーキットブレーカー
+// 連続して失敗回数の閾値に達すると遮断し、クールダウン後に1件だけ試行して復旧を確認する
+// 複数のゴルーチンから同時に使用できる。nil の場合は遮断しない
+type CircuitBreaker struct {
+	name             string
+	failureThreshold int
+	coolDown         time.Duration
+
+	mu       sync.Mutex
+	state    CircuitState
+	failures int       // 連続した失敗回数
+	openedAt time.Time // 遮断した時刻
+	probing  bool      // 試行中のリクエストがあるか
+}
+
+// NewCircuitBreaker CircuitBreakerを生成（失敗回数の閾値が0以下の場合は遮断しないため nil を返す）
+func NewCircuitBreaker(name string, cfg config.CircuitConfig) *CircuitBreaker {
+	if cfg.FailureThreshold <= 0 {
+		return nil
+	}
+	return &CircuitBreaker{
+		name:             name,
+		failureThreshold: cfg.FailureThreshold,
+		coolDown:         cfg.CoolDown,
+		state:            CircuitClosed,
+	}
+}
+
+// Allow リクエストを送信してよいか判定する（遮断中は CircuitOpenError を返す）
+// クールダウンが過ぎていれば試行中に移行し、最初の1件のみ送信を許可する
+func (b *CircuitBreaker) Allow() error {
+	if b == nil {
+		return nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case CircuitOpen:
+		until := b.openedAt.Add(b.coolDown)
+		if time.Now().Before(until) {
+			return &CircuitOpenError{Name: b.name, Until: until}
+		}
+		b.state = CircuitHalfOpen
+		b.probing = true
+		log.Printf("[%s] サーキットブレーカー: 試行を再開します\n", b.name)
+		return nil
+	case CircuitHalfOpen:
+		if b.probing {
+			return &CircuitOpenError{Name: b.name, Until: time.Now().Add(b.coolDown)}
+		}
+		b.probing = true
+	}
+	return nil
+}
+
+// Record リクエストの結果を記録する（試行中の成功で復旧、失敗で再び遮断）
+func (b *CircuitBreaker) Record(success bool) {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if success {
+		if b.state != CircuitClosed {
+			log.Printf("[%s] サーキットブレーカー: 復旧しました\n", b.name)
+		}
+		b.state = CircuitClosed
+		b.failures = 0
+		b.probing = false
+		return
+	}
+
+	b.failures++
+	if b.state == CircuitHalfOpen || b.failures >= b.failureThreshold {
+		if b.state != CircuitOpen {
+			log.Printf("Warning: [%s] サーキットブレーカー: %d回連続で失敗したため%v間リクエストを停止します\n", b.name, b.failures, b.coolDown)
+		}
+		b.state = CircuitOpen
+		b.openedAt = time.Now()
+		b.probing = false
+	}
+}
+
+// Release 結果を判定できなかった試行（キャンセル等）の枠を解放する
+func (b *CircuitBreaker) Release() {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+}
+
+// State 現在の状態
+func (b *CircuitBreaker) State() CircuitState {
+	if b == nil {
+		return CircuitClosed
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
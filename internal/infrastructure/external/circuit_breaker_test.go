@@ -0,0 +1,101 @@
+package external
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"teckbook-compass-backend/internal/infrastructure/config"
+)
+
+func TestCircuitBreakerOpensAndRecovers(t *testing.T) {
+	breaker := NewCircuitBreaker("test", config.CircuitConfig{FailureThreshold: 2, CoolDown: 50 * time.Millisecond})
+
+	// closed: 閾値未満の失敗では遮断しない
+	breaker.Record(false)
+	if got := breaker.State(); got != CircuitClosed {
+		t.Fatalf("State() after 1 failure = %s, want %s", got, CircuitClosed)
+	}
+
+	// open: 連続して閾値に達すると遮断する
+	breaker.Record(false)
+	if got := breaker.State(); got != CircuitOpen {
+		t.Fatalf("State() after 2 failures = %s, want %s", got, CircuitOpen)
+	}
+	if err := breaker.Allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("Allow() while open = %v, want ErrCircuitOpen", err)
+	}
+
+	// half-open: クールダウン後は1件だけ試行を許可する
+	time.Sleep(60 * time.Millisecond)
+	if err := breaker.Allow(); err != nil {
+		t.Fatalf("Allow() after cool-down = %v, want nil", err)
+	}
+	if got := breaker.State(); got != CircuitHalfOpen {
+		t.Fatalf("State() after cool-down = %s, want %s", got, CircuitHalfOpen)
+	}
+	if err := breaker.Allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("second Allow() while probing = %v, want ErrCircuitOpen", err)
+	}
+
+	// closed: 試行が成功すると復旧する
+	breaker.Record(true)
+	if got := breaker.State(); got != CircuitClosed {
+		t.Fatalf("State() after successful probe = %s, want %s", got, CircuitClosed)
+	}
+	if err := breaker.Allow(); err != nil {
+		t.Fatalf("Allow() after recovery = %v, want nil", err)
+	}
+}
+
+func TestCircuitBreakerFailedProbeReopens(t *testing.T) {
+	breaker := NewCircuitBreaker("test", config.CircuitConfig{FailureThreshold: 1, CoolDown: 50 * time.Millisecond})
+
+	breaker.Record(false)
+	time.Sleep(60 * time.Millisecond)
+	if err := breaker.Allow(); err != nil {
+		t.Fatalf("Allow() after cool-down = %v, want nil", err)
+	}
+
+	breaker.Record(false)
+	if got := breaker.State(); got != CircuitOpen {
+		t.Fatalf("State() after failed probe = %s, want %s", got, CircuitOpen)
+	}
+	if err := breaker.Allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("Allow() after failed probe = %v, want ErrCircuitOpen", err)
+	}
+}
+
+func TestCircuitBreakerReleaseFreesProbe(t *testing.T) {
+	breaker := NewCircuitBreaker("test", config.CircuitConfig{FailureThreshold: 1, CoolDown: 50 * time.Millisecond})
+
+	breaker.Record(false)
+	time.Sleep(60 * time.Millisecond)
+	if err := breaker.Allow(); err != nil {
+		t.Fatalf("Allow() after cool-down = %v, want nil", err)
+	}
+
+	// 結果を判定できなかった試行は状態を変えずに枠だけ解放し、次のリクエストで試行し直す
+	breaker.Release()
+	if got := breaker.State(); got != CircuitHalfOpen {
+		t.Fatalf("State() after Release() = %s, want %s", got, CircuitHalfOpen)
+	}
+	if err := breaker.Allow(); err != nil {
+		t.Fatalf("Allow() after Release() = %v, want nil", err)
+	}
+}
+
+func TestCircuitBreakerNilNeverOpens(t *testing.T) {
+	breaker := NewCircuitBreaker("test", config.CircuitConfig{FailureThreshold: 0})
+	if breaker != nil {
+		t.Fatalf("NewCircuitBreaker() with threshold 0 = %v, want nil", breaker)
+	}
+
+	breaker.Record(false)
+	if err := breaker.Allow(); err != nil {
+		t.Errorf("Allow() on nil breaker = %v, want nil", err)
+	}
+	if got := breaker.State(); got != CircuitClosed {
+		t.Errorf("State() on nil breaker = %s, want %s", got, CircuitClosed)
+	}
+}
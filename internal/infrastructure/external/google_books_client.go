@@ -0,0 +1,190 @@
+package external
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"teckbook-compass-backend/internal/domain/entity"
+	"teckbook-compass-backend/internal/infrastructure/config"
+)
+
+// GoogleBooksClient Google Books APIクライアント
+// 洋書や表紙画像の補完に使う
+type GoogleBooksClient struct {
+	baseURL    string
+	apiKey     string
+	httpClient *http.Client
+}
+
+// NewGoogleBooksClient GoogleBooksClientを生成
+func NewGoogleBooksClient(cfg config.MetadataConfig, ledger *UsageLedger) *GoogleBooksClient {
+	return &GoogleBooksClient{
+		baseURL: cfg.GoogleBooksBaseURL,
+		apiKey:  cfg.GoogleBooksAPIKey,
+		httpClient: newHTTPClient(transportOptions{
+			provider: "google_books",
+			timeout:  30 * time.Second,
+			breaker:  NewCircuitBreaker("google_books", cfg.Circuit),
+			ledger:   ledger,
+			policy:   defaultRetryPolicy,
+		}),
+	}
+}
+
+// Retries 実行したリトライ回数
+func (c *GoogleBooksClient) Retries() int {
+	return retryCount(c.httpClient)
+}
+
+// CircuitState サーキットブレーカーの状態
+func (c *GoogleBooksClient) CircuitState() CircuitState {
+	return circuitState(c.httpClient)
+}
+
+// googleBooksResponse Google Books APIのレスポンス
+type googleBooksResponse struct {
+	TotalItems int `json:"totalItems"`
+	Items      []struct {
+		VolumeInfo googleBooksVolume `json:"volumeInfo"`
+	} `json:"items"`
+}
+
+// googleBooksVolume Google Books APIの書籍情報
+type googleBooksVolume struct {
+	Title               string   `json:"title"`
+	Subtitle            string   `json:"subtitle"`
+	Authors             []string `json:"authors"`
+	Publisher           string   `json:"publisher"`
+	PublishedDate       string   `json:"publishedDate"`
+	Description         string   `json:"description"`
+	IndustryIdentifiers []struct {
+		Type       string `json:"type"`
+		Identifier string `json:"identifier"`
+	} `json:"industryIdentifiers"`
+	ImageLinks struct {
+		SmallThumbnail string `json:"smallThumbnail"`
+		Thumbnail      string `json:"thumbnail"`
+	} `json:"imageLinks"`
+}
+
+// Name プロバイダ名
+func (c *GoogleBooksClient) Name() string {
+	return "google_books"
+}
+
+// LookupByISBN ISBNで書籍メタデータを取得
+func (c *GoogleBooksClient) LookupByISBN(ctx context.Context, isbn string) (*entity.BookMetadata, error) {
+	volumes, err := c.search(ctx, "isbn:"+isbn, 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(volumes) == 0 {
+		return nil, fmt.Errorf("%w: ISBN %s", ErrBookMetadataNotFound, isbn)
+	}
+
+	metadata := volumes[0].toMetadata()
+	metadata.ISBN = entity.ToISBN13(isbn)
+	return metadata, nil
+}
+
+// SearchMetadataByTitle タイトルで書籍メタデータを検索（ISBNを持つ書籍のみ）
+func (c *GoogleBooksClient) SearchMetadataByTitle(ctx context.Context, title string) ([]*entity.BookMetadata, error) {
+	volumes, err := c.search(ctx, "intitle:"+title, 10)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*entity.BookMetadata, 0, len(volumes))
+	for _, volume := range volumes {
+		metadata := volume.toMetadata()
+		if metadata.ISBN != "" {
+			results = append(results, metadata)
+		}
+	}
+	return results, nil
+}
+
+// search 検索クエリで書籍を取得
+func (c *GoogleBooksClient) search(ctx context.Context, query string, maxResults int) ([]googleBooksVolume, error) {
+	params := url.Values{}
+	params.Set("q", query)
+	params.Set("maxResults", fmt.Sprintf("%d", maxResults))
+	if c.apiKey != "" {
+		params.Set("key", c.apiKey)
+	}
+	reqURL := fmt.Sprintf("%s/volumes?%s", c.baseURL, params.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API returned non-200 status: %d, body: %s", resp.StatusCode, string(body))
+	}
+
+	var response googleBooksResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	volumes := make([]googleBooksVolume, 0, len(response.Items))
+	for _, item := range response.Items {
+		volumes = append(volumes, item.VolumeInfo)
+	}
+	return volumes, nil
+}
+
+// toMetadata Google Booksの書籍情報を書籍メタデータに変換
+func (v *googleBooksVolume) toMetadata() *entity.BookMetadata {
+	title := v.Title
+	if v.Subtitle != "" {
+		title = v.Title + " " + v.Subtitle
+	}
+
+	// 表紙画像はhttpで返るためhttpsに置き換える
+	thumbnail := v.ImageLinks.Thumbnail
+	if thumbnail == "" {
+		thumbnail = v.ImageLinks.SmallThumbnail
+	}
+	thumbnail = strings.Replace(thumbnail, "http://", "https://", 1)
+
+	metadata := &entity.BookMetadata{
+		Title:       title,
+		Author:      strings.Join(v.Authors, ", "),
+		Publisher:   v.Publisher,
+		PublishedAt: parseMetadataDate(v.PublishedDate),
+		Overview:    v.Description,
+		Thumbnail:   thumbnail,
+	}
+
+	for _, id := range v.IndustryIdentifiers {
+		switch id.Type {
+		case "ISBN_13":
+			metadata.ISBN = id.Identifier
+		case "ISBN_10":
+			if metadata.ISBN == "" {
+				metadata.ISBN = entity.ToISBN13(id.Identifier)
+			}
+		}
+	}
+
+	return metadata
+}
@@ -0,0 +1,114 @@
+package external
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"teckbook-compass-backend/internal/infrastructure/config"
+)
+
+// hatenaMaxURLsPerRequest 件数APIに一度に渡せるURLの最大数
+const hatenaMaxURLsPerRequest = 50
+
+// HatenaClient はてなブックマーク件数APIクライアント
+type HatenaClient struct {
+	config     config.HatenaConfig
+	httpClient *http.Client
+}
+
+// NewHatenaClient HatenaClientを生成
+func NewHatenaClient(cfg config.HatenaConfig, ledger *UsageLedger) *HatenaClient {
+	return &HatenaClient{
+		config: cfg,
+		httpClient: newHTTPClient(transportOptions{
+			provider: "hatena",
+			timeout:  30 * time.Second,
+			limiter:  NewRateLimiter(cfg.RateLimit, 1), // 分割したリクエストの間隔もこのリミッターで空ける
+			breaker:  NewCircuitBreaker("hatena", cfg.Circuit),
+			ledger:   ledger,
+			policy:   defaultRetryPolicy,
+		}),
+	}
+}
+
+// Retries 実行したリトライ回数
+func (c *HatenaClient) Retries() int {
+	return retryCount(c.httpClient)
+}
+
+// CircuitState サーキットブレーカーの状態
+func (c *HatenaClient) CircuitState() CircuitState {
+	return circuitState(c.httpClient)
+}
+
+// IsEnabled はてなブックマーク数の取得が有効かどうか
+func (c *HatenaClient) IsEnabled() bool {
+	return c.config.Enabled && c.config.BaseURL != ""
+}
+
+// GetBookmarkCounts URLごとのはてなブックマーク数を一括取得
+// 50件ずつに分けてリクエストし、ブックマークされていないURLは0件として返す
+// 途中のリクエストが失敗した場合は、それまでに取得できたURLの件数とエラーを返す（取得できなかったURLは含まない）
+func (c *HatenaClient) GetBookmarkCounts(ctx context.Context, urls []string) (map[string]int, error) {
+	counts := make(map[string]int, len(urls))
+
+	for start := 0; start < len(urls); start += hatenaMaxURLsPerRequest {
+		end := start + hatenaMaxURLsPerRequest
+		if end > len(urls) {
+			end = len(urls)
+		}
+		chunk := urls[start:end]
+
+		chunkCounts, err := c.getCounts(ctx, chunk)
+		if err != nil {
+			return counts, err
+		}
+		for _, u := range chunk {
+			counts[u] = chunkCounts[u]
+		}
+	}
+
+	return counts, nil
+}
+
+// getCounts 件数APIを1回呼び出す（最大50件）
+func (c *HatenaClient) getCounts(ctx context.Context, urls []string) (map[string]int, error) {
+	params := url.Values{}
+	for _, u := range urls {
+		params.Add("url", u)
+	}
+	reqURL := fmt.Sprintf("%s/count/entries?%s", c.config.BaseURL, params.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API returned non-200 status: %d, body: %s", resp.StatusCode, string(body))
+	}
+
+	// レスポンスは {"<url>": <件数>, ...} の形式
+	var counts map[string]int
+	if err := json.Unmarshal(body, &counts); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return counts, nil
+}
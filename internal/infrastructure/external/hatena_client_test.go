@@ -0,0 +1,138 @@
+package external
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"teckbook-compass-backend/internal/infrastructure/config"
+)
+
+// hatenaStub はてなブックマーク件数APIのスタブ
+// bookmarks にないURLはレスポンスに含めない（実際のAPIと同じくブックマークされていないURLは省略される）
+type hatenaStub struct {
+	mu        sync.Mutex
+	bookmarks map[string]int
+	failAt    int   // この回数目のリクエストで400を返す（0の場合は失敗しない）
+	requests  []int // リクエストごとのURL数
+}
+
+func (s *hatenaStub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	urls := r.URL.Query()["url"]
+	s.requests = append(s.requests, len(urls))
+	if s.failAt > 0 && len(s.requests) == s.failAt {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+
+	counts := make(map[string]int)
+	for _, u := range urls {
+		if count, ok := s.bookmarks[u]; ok {
+			counts[u] = count
+		}
+	}
+	_ = json.NewEncoder(w).Encode(counts)
+}
+
+func newTestHatenaClient(t *testing.T, stub *hatenaStub) *HatenaClient {
+	t.Helper()
+	server := httptest.NewServer(stub)
+	t.Cleanup(server.Close)
+	return NewHatenaClient(config.HatenaConfig{BaseURL: server.URL, Enabled: true}, nil)
+}
+
+func testArticleURLs(n int) []string {
+	urls := make([]string, n)
+	for i := range urls {
+		urls[i] = fmt.Sprintf("https://qiita.com/user/items/%03d", i)
+	}
+	return urls
+}
+
+func TestHatenaClientGetBookmarkCountsBatchesRequests(t *testing.T) {
+	urls := testArticleURLs(120)
+	stub := &hatenaStub{bookmarks: map[string]int{urls[0]: 3, urls[119]: 42}}
+	client := newTestHatenaClient(t, stub)
+
+	counts, err := client.GetBookmarkCounts(context.Background(), urls)
+	if err != nil {
+		t.Fatalf("GetBookmarkCounts() error = %v", err)
+	}
+
+	want := []int{50, 50, 20}
+	if fmt.Sprint(stub.requests) != fmt.Sprint(want) {
+		t.Errorf("URLs per request = %v, want %v", stub.requests, want)
+	}
+	if len(counts) != len(urls) {
+		t.Errorf("len(counts) = %d, want %d", len(counts), len(urls))
+	}
+	if counts[urls[0]] != 3 || counts[urls[119]] != 42 {
+		t.Errorf("counts = %d, %d, want 3, 42", counts[urls[0]], counts[urls[119]])
+	}
+}
+
+func TestHatenaClientGetBookmarkCountsMissingURLIsZero(t *testing.T) {
+	urls := testArticleURLs(2)
+	stub := &hatenaStub{bookmarks: map[string]int{urls[0]: 5}}
+	client := newTestHatenaClient(t, stub)
+
+	counts, err := client.GetBookmarkCounts(context.Background(), urls)
+	if err != nil {
+		t.Fatalf("GetBookmarkCounts() error = %v", err)
+	}
+
+	count, ok := counts[urls[1]]
+	if !ok || count != 0 {
+		t.Errorf("counts[missing] = %d, %t, want 0, true", count, ok)
+	}
+}
+
+func TestHatenaClientGetBookmarkCountsErrorReturnsFetchedOnly(t *testing.T) {
+	urls := testArticleURLs(70)
+	stub := &hatenaStub{bookmarks: map[string]int{urls[0]: 1, urls[60]: 2}, failAt: 2}
+	client := newTestHatenaClient(t, stub)
+
+	counts, err := client.GetBookmarkCounts(context.Background(), urls)
+	if err == nil {
+		t.Fatal("GetBookmarkCounts() error = nil, want error")
+	}
+	if len(counts) != 50 {
+		t.Errorf("len(counts) = %d, want 50 (first batch only)", len(counts))
+	}
+	if _, ok := counts[urls[60]]; ok {
+		t.Errorf("counts contains %s from the failed batch", urls[60])
+	}
+}
+
+func TestHatenaClientGetBookmarkCountsStopsWaitingOnDeadline(t *testing.T) {
+	urls := testArticleURLs(120)
+	stub := &hatenaStub{bookmarks: map[string]int{}}
+	server := httptest.NewServer(stub)
+	t.Cleanup(server.Close)
+	// 1リクエスト/秒のため、2回目のリクエストは1秒待つ必要がある
+	client := NewHatenaClient(config.HatenaConfig{BaseURL: server.URL, Enabled: true, RateLimit: 1}, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	counts, err := client.GetBookmarkCounts(ctx, urls)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("GetBookmarkCounts() error = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("GetBookmarkCounts() took %v, want to return at the deadline", elapsed)
+	}
+	if len(counts) != 50 || len(stub.requests) != 1 {
+		t.Errorf("len(counts) = %d, requests = %v, want 50 counts from 1 request", len(counts), stub.requests)
+	}
+}
@@ -0,0 +1,373 @@
+package external
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ErrRateLimited レート制限の残りが0のため、リセットまでリクエストを送信しなかった（errors.Is で判定）
+var ErrRateLimited = errors.New("rate limit exhausted")
+
+// RateLimitedError レート制限に達したプロバイダとリクエストを再開できる時刻
+type RateLimitedError struct {
+	Provider string
+	ResumeAt time.Time
+}
+
+// Error エラーメッセージ
+func (e *RateLimitedError) Error() string {
+	return fmt.Sprintf("%s: rate limit exhausted until %s", e.Provider, e.ResumeAt.Format(time.RFC3339))
+}
+
+// Is ErrRateLimited と一致する
+func (e *RateLimitedError) Is(target error) bool {
+	return target == ErrRateLimited
+}
+
+// retryPolicy 外部APIのリトライ設定（クライアントごと）
+type retryPolicy struct {
+	maxRetries int           // 1リクエストあたりのリトライ回数の上限
+	baseDelay  time.Duration // 初回リトライまでの待機時間（以後2倍ずつ増やし、ジッターを加える）
+	maxDelay   time.Duration // 1回の待機時間の上限（Retry-After等がこれを超える場合はリトライしない）
+	budget     int           // クライアント全体でのリトライ回数の上限（使い切ると以後リトライしない）
+}
+
+// defaultRetryPolicy 既定のリトライ設定
+var defaultRetryPolicy = retryPolicy{
+	maxRetries: 3,
+	baseDelay:  time.Second,
+	maxDelay:   30 * time.Second,
+	budget:     30,
+}
+
+// transportOptions 外部APIクライアントごとのトランスポート設定
+type transportOptions struct {
+	provider string          // プロバイダ名（使用量の記録に使う）
+	timeout  time.Duration   // 1回のリクエストのタイムアウト（リトライの待機は含まない）
+	limiter  *RateLimiter    // リクエスト数の制限（リトライも含めて消費する）
+	breaker  *CircuitBreaker // 障害時の遮断（nil の場合は遮断しない）
+	ledger   *UsageLedger    // 使用量の記録と1日の上限（nil の場合は記録しない）
+	policy   retryPolicy
+}
+
+// retryTransport レート制限・リトライ・サーキットブレーカー付きのhttp.RoundTripper（外部APIクライアント共通）
+// 429・5xx・通信エラーを指数バックオフ（ジッター付き）でリトライし、Retry-After と
+// Qiitaの Rate-Remaining / Rate-Reset ヘッダに従って次のリクエストまで待機する
+// リトライしても失敗したリクエストはサーキットブレーカーに記録し、遮断中は送信せずに ErrCircuitOpen を返す
+// 送信したリクエスト（リトライを含む）は使用量の台帳に記録し、1日の上限に達した場合は ErrBudgetExceeded を返す
+// レート制限のリセット・Retry-After までの時間が待機時間の上限を超える場合は、待たずに ErrRateLimited を返す
+type retryTransport struct {
+	transportOptions
+	base http.RoundTripper
+
+	mu       sync.Mutex
+	retries  int       // 実行したリトライ回数
+	resumeAt time.Time // レート制限の残りが0になったため、この時刻までリクエストを控える
+}
+
+// newHTTPClient レート制限・リトライ・サーキットブレーカー・使用量の記録付きのHTTPクライアントを生成
+func newHTTPClient(opts transportOptions) *http.Client {
+	return &http.Client{
+		Transport: &retryTransport{
+			transportOptions: opts,
+			base:             http.DefaultTransport,
+		},
+	}
+}
+
+// retryCount HTTPクライアントが実行したリトライ回数
+func retryCount(client *http.Client) int {
+	t, ok := client.Transport.(*retryTransport)
+	if !ok {
+		return 0
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.retries
+}
+
+// circuitState HTTPクライアントのサーキットブレーカーの状態
+func circuitState(client *http.Client) CircuitState {
+	t, ok := client.Transport.(*retryTransport)
+	if !ok {
+		return CircuitClosed
+	}
+	return t.breaker.State()
+}
+
+// RoundTrip リクエストを送信し、結果をサーキットブレーカーに記録する
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.breaker.Allow(); err != nil {
+		return nil, err
+	}
+
+	resp, err := t.send(req)
+	switch {
+	case req.Context().Err() != nil, errors.Is(err, ErrBudgetExceeded), errors.Is(err, ErrRateLimited):
+		// キャンセル・タイムアウト・使用量の上限・レート制限は取得先の障害ではないため記録しない
+		t.breaker.Release()
+	case err != nil || retryableResponse(resp):
+		t.breaker.Record(false)
+	default:
+		t.breaker.Record(true)
+	}
+	return resp, err
+}
+
+// send リクエストを送信（リトライ可能なエラーは待機してから再送）
+func (t *retryTransport) send(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	for attempt := 0; ; attempt++ {
+		if err := t.waitForReset(ctx); err != nil {
+			return nil, err
+		}
+		if err := t.limiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+		if err := t.ledger.Reserve(ctx, t.provider, endpointOf(req)); err != nil {
+			return nil, err
+		}
+
+		attemptReq, err := rewindRequest(req, attempt)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := t.roundTripOnce(attemptReq)
+		if resp != nil {
+			t.observeRateHeaders(resp)
+			if limitErr := t.rateLimited(resp); limitErr != nil {
+				_, _ = io.Copy(io.Discard, resp.Body)
+				resp.Body.Close()
+				return nil, limitErr
+			}
+		}
+
+		delay, retry := t.retryDelay(req, resp, err, attempt)
+		if !retry || !t.takeBudget() {
+			return resp, err
+		}
+
+		reason := "通信エラー"
+		if resp != nil {
+			reason = resp.Status
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		log.Printf("Warning: %s %s をリトライします（%d回目、%v後）: %s\n", req.Method, req.URL.Host, attempt+1, delay.Round(time.Millisecond), reason)
+
+		if err := sleepContext(ctx, delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// roundTripOnce タイムアウト付きでリクエストを1回送信
+// タイムアウトはレスポンスボディを読み終えて閉じるまで有効
+func (t *retryTransport) roundTripOnce(req *http.Request) (*http.Response, error) {
+	if t.timeout <= 0 {
+		return t.base.RoundTrip(req)
+	}
+
+	ctx, cancel := context.WithTimeout(req.Context(), t.timeout)
+	resp, err := t.base.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// retryDelay リトライするかどうかと、リトライまでの待機時間を判定
+func (t *retryTransport) retryDelay(req *http.Request, resp *http.Response, err error, attempt int) (time.Duration, bool) {
+	if attempt >= t.policy.maxRetries || req.Context().Err() != nil {
+		return 0, false
+	}
+	// ボディを再送できないリクエストはリトライしない
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return 0, false
+	}
+
+	if err == nil && !retryableResponse(resp) {
+		return 0, false
+	}
+
+	delay := t.backoff(attempt)
+	if resp != nil {
+		if wait, ok := serverWait(resp); ok {
+			delay = max(wait, 0)
+		}
+	}
+	if delay > t.policy.maxDelay {
+		return 0, false
+	}
+	return delay, true
+}
+
+// backoff 指数バックオフの待機時間（半分を固定、残りをランダムにしたジッター付き）
+func (t *retryTransport) backoff(attempt int) time.Duration {
+	delay := t.policy.baseDelay << attempt
+	if delay <= 0 || delay > t.policy.maxDelay {
+		delay = t.policy.maxDelay
+	}
+	half := delay / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// takeBudget リトライ回数の上限に達していなければ1回分を消費する
+func (t *retryTransport) takeBudget() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.retries >= t.policy.budget {
+		return false
+	}
+	t.retries++
+	return true
+}
+
+// observeRateHeaders レート制限の残りが0になった場合、リセット時刻までリクエストを控える
+func (t *retryTransport) observeRateHeaders(resp *http.Response) {
+	remaining, ok := parseIntHeader(resp.Header, "Rate-Remaining")
+	if !ok || remaining > 0 {
+		return
+	}
+	reset, ok := parseIntHeader(resp.Header, "Rate-Reset")
+	if !ok {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if resetAt := time.Unix(int64(reset), 0); resetAt.After(t.resumeAt) {
+		t.resumeAt = resetAt
+	}
+}
+
+// rateLimited レート制限を超えたレスポンスで、再開までの時間が待機時間の上限を超える場合は RateLimitedError を返す
+// 以後のリクエストも再開時刻まで送信せずに同じエラーを返す
+func (t *retryTransport) rateLimited(resp *http.Response) error {
+	if !retryableResponse(resp) {
+		return nil
+	}
+	wait, ok := serverWait(resp)
+	if !ok || wait <= t.policy.maxDelay {
+		return nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if resumeAt := time.Now().Add(wait); resumeAt.After(t.resumeAt) {
+		t.resumeAt = resumeAt
+	}
+	return &RateLimitedError{Provider: t.provider, ResumeAt: t.resumeAt}
+}
+
+// waitForReset レート制限のリセット時刻まで待機する
+// リセットまでの時間が待機時間の上限を超える場合は待たずに RateLimitedError を返す
+func (t *retryTransport) waitForReset(ctx context.Context) error {
+	t.mu.Lock()
+	resumeAt := t.resumeAt
+	t.mu.Unlock()
+
+	wait := time.Until(resumeAt)
+	if wait <= 0 {
+		return nil
+	}
+	if wait > t.policy.maxDelay {
+		return &RateLimitedError{Provider: t.provider, ResumeAt: resumeAt}
+	}
+	return sleepContext(ctx, wait)
+}
+
+// retryableResponse リトライで回復しうるレスポンスかどうか
+// 429・5xx に加え、Qiitaがレート制限超過時に返す 403（Rate-Remaining: 0）もリトライする
+func retryableResponse(resp *http.Response) bool {
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	case http.StatusForbidden:
+		remaining, ok := parseIntHeader(resp.Header, "Rate-Remaining")
+		return ok && remaining == 0
+	}
+	return false
+}
+
+// serverWait レスポンスヘッダで指定された待機時間（Retry-After、なければ Rate-Reset）
+func serverWait(resp *http.Response) (time.Duration, bool) {
+	if value := resp.Header.Get("Retry-After"); value != "" {
+		if seconds, err := strconv.Atoi(value); err == nil {
+			return time.Duration(seconds) * time.Second, true
+		}
+		if at, err := http.ParseTime(value); err == nil {
+			return time.Until(at), true
+		}
+	}
+
+	if remaining, ok := parseIntHeader(resp.Header, "Rate-Remaining"); ok && remaining == 0 {
+		if reset, ok := parseIntHeader(resp.Header, "Rate-Reset"); ok {
+			return time.Until(time.Unix(int64(reset), 0)), true
+		}
+	}
+	return 0, false
+}
+
+// rewindRequest リトライ用にボディを読み直せるリクエストを作成（初回はそのまま）
+func rewindRequest(req *http.Request, attempt int) (*http.Request, error) {
+	if attempt == 0 || req.Body == nil || req.Body == http.NoBody {
+		return req, nil
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, fmt.Errorf("failed to rewind request body: %w", err)
+	}
+	clone := req.Clone(req.Context())
+	clone.Body = body
+	return clone, nil
+}
+
+// parseIntHeader 整数のレスポンスヘッダを取得
+func parseIntHeader(header http.Header, key string) (int, bool) {
+	value, err := strconv.Atoi(header.Get(key))
+	if err != nil {
+		return 0, false
+	}
+	return value, true
+}
+
+// sleepContext 指定時間待機する（ctx がキャンセルされた場合はエラーを返す）
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// cancelOnClose レスポンスボディを閉じたときにリクエストのコンテキストを解放する
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+// Close ボディを閉じてコンテキストを解放
+func (b *cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
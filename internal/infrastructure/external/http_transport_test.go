@@ -0,0 +1,258 @@
+package external
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"teckbook-compass-backend/internal/infrastructure/config"
+)
+
+// testRetryPolicy テスト用のリトライ設定（バックオフは短く、サーバー指定の待機は数秒まで従う）
+var testRetryPolicy = retryPolicy{
+	maxRetries: 2,
+	baseDelay:  time.Millisecond,
+	maxDelay:   5 * time.Second,
+	budget:     10,
+}
+
+// transportStub リクエストの回数ごとにレスポンスを返すスタブ（回数を超えた分は最後のレスポンスを返す）
+type transportStub struct {
+	mu        sync.Mutex
+	responses []func(w http.ResponseWriter)
+	hits      int
+}
+
+func (s *transportStub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	respond := s.responses[min(s.hits, len(s.responses)-1)]
+	s.hits++
+	s.mu.Unlock()
+	respond(w)
+}
+
+func (s *transportStub) hitCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.hits
+}
+
+func respondStatus(status int, header map[string]string) func(w http.ResponseWriter) {
+	return func(w http.ResponseWriter) {
+		for key, value := range header {
+			w.Header().Set(key, value)
+		}
+		w.WriteHeader(status)
+	}
+}
+
+func newTestTransportServer(t *testing.T, stub *transportStub) string {
+	t.Helper()
+	server := httptest.NewServer(stub)
+	t.Cleanup(server.Close)
+	return server.URL
+}
+
+func newTestTransportClient(breaker *CircuitBreaker) *http.Client {
+	return newHTTPClient(transportOptions{
+		provider: "test",
+		timeout:  5 * time.Second,
+		breaker:  breaker,
+		policy:   testRetryPolicy,
+	})
+}
+
+func doTestRequest(ctx context.Context, client *http.Client, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	return resp, nil
+}
+
+func TestRetryTransportOpensCircuitAndRecovers(t *testing.T) {
+	stub := &transportStub{responses: []func(w http.ResponseWriter){respondStatus(http.StatusServiceUnavailable, nil)}}
+	url := newTestTransportServer(t, stub)
+	breaker := NewCircuitBreaker("test", config.CircuitConfig{FailureThreshold: 1, CoolDown: 50 * time.Millisecond})
+	client := newTestTransportClient(breaker)
+
+	// closed → open: リトライしても失敗したリクエストで遮断する
+	resp, err := doTestRequest(context.Background(), client, url)
+	if err != nil || resp.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("first request = %v, %v, want 503", resp, err)
+	}
+	if got := stub.hitCount(); got != 1+testRetryPolicy.maxRetries {
+		t.Errorf("hits = %d, want %d (1 + retries)", got, 1+testRetryPolicy.maxRetries)
+	}
+	if got := breaker.State(); got != CircuitOpen {
+		t.Fatalf("State() = %s, want %s", got, CircuitOpen)
+	}
+
+	// open: 遮断中は送信しない
+	if _, err := doTestRequest(context.Background(), client, url); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("request while open = %v, want ErrCircuitOpen", err)
+	}
+	hits := stub.hitCount()
+
+	// half-open → closed: クールダウン後の試行が成功すると復旧する
+	time.Sleep(60 * time.Millisecond)
+	stub.mu.Lock()
+	stub.responses = []func(w http.ResponseWriter){respondStatus(http.StatusOK, nil)}
+	stub.mu.Unlock()
+
+	resp, err = doTestRequest(context.Background(), client, url)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("probe request = %v, %v, want 200", resp, err)
+	}
+	if got := stub.hitCount(); got != hits+1 {
+		t.Errorf("hits after probe = %d, want %d", got, hits+1)
+	}
+	if got := breaker.State(); got != CircuitClosed {
+		t.Errorf("State() after probe = %s, want %s", got, CircuitClosed)
+	}
+}
+
+func TestRetryTransportReleasesProbeOnCancel(t *testing.T) {
+	stub := &transportStub{responses: []func(w http.ResponseWriter){respondStatus(http.StatusOK, nil)}}
+	url := newTestTransportServer(t, stub)
+	breaker := NewCircuitBreaker("test", config.CircuitConfig{FailureThreshold: 1, CoolDown: 50 * time.Millisecond})
+	client := newTestTransportClient(breaker)
+
+	breaker.Record(false)
+	time.Sleep(60 * time.Millisecond)
+
+	// キャンセルされた試行は失敗として記録せず、枠だけ解放する
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := doTestRequest(ctx, client, url); !errors.Is(err, context.Canceled) {
+		t.Fatalf("canceled request = %v, want context.Canceled", err)
+	}
+	if got := breaker.State(); got != CircuitHalfOpen {
+		t.Fatalf("State() after canceled probe = %s, want %s", got, CircuitHalfOpen)
+	}
+
+	// 解放された枠で次のリクエストを試行し、成功すれば復旧する
+	resp, err := doTestRequest(context.Background(), client, url)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("request after cancel = %v, %v, want 200", resp, err)
+	}
+	if got := breaker.State(); got != CircuitClosed {
+		t.Errorf("State() after successful probe = %s, want %s", got, CircuitClosed)
+	}
+}
+
+func TestRetryTransportHonoursRetryAfter(t *testing.T) {
+	stub := &transportStub{responses: []func(w http.ResponseWriter){
+		respondStatus(http.StatusTooManyRequests, map[string]string{"Retry-After": "1"}),
+		respondStatus(http.StatusOK, nil),
+	}}
+	url := newTestTransportServer(t, stub)
+	client := newTestTransportClient(nil)
+
+	start := time.Now()
+	resp, err := doTestRequest(context.Background(), client, url)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("request = %v, %v, want 200 after retry", resp, err)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("elapsed = %v, want at least the Retry-After of 1s", elapsed)
+	}
+	if got := stub.hitCount(); got != 2 {
+		t.Errorf("hits = %d, want 2", got)
+	}
+}
+
+func TestRetryTransportReturnsRateLimitedBeyondMaxDelay(t *testing.T) {
+	stub := &transportStub{responses: []func(w http.ResponseWriter){
+		respondStatus(http.StatusTooManyRequests, map[string]string{"Retry-After": "60"}),
+	}}
+	url := newTestTransportServer(t, stub)
+	breaker := NewCircuitBreaker("test", config.CircuitConfig{FailureThreshold: 1, CoolDown: time.Minute})
+	client := newTestTransportClient(breaker)
+
+	// 待機時間の上限を超える場合はリトライせず、障害として記録しない
+	if _, err := doTestRequest(context.Background(), client, url); !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("request = %v, want ErrRateLimited", err)
+	}
+	if got := stub.hitCount(); got != 1 {
+		t.Errorf("hits = %d, want 1", got)
+	}
+	if got := breaker.State(); got != CircuitClosed {
+		t.Errorf("State() = %s, want %s", got, CircuitClosed)
+	}
+
+	// 再開時刻までは送信せずに同じエラーを返す
+	if _, err := doTestRequest(context.Background(), client, url); !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("second request = %v, want ErrRateLimited", err)
+	}
+	if got := stub.hitCount(); got != 1 {
+		t.Errorf("hits after second request = %d, want 1", got)
+	}
+}
+
+func TestRetryTransportReturnsRateLimitedUntilHourlyReset(t *testing.T) {
+	resetAt := time.Unix(time.Now().Unix()+3600, 0)
+	stub := &transportStub{responses: []func(w http.ResponseWriter){
+		respondStatus(http.StatusForbidden, map[string]string{
+			"Rate-Remaining": "0",
+			"Rate-Reset":     strconv.FormatInt(resetAt.Unix(), 10),
+		}),
+	}}
+	url := newTestTransportServer(t, stub)
+	breaker := NewCircuitBreaker("test", config.CircuitConfig{FailureThreshold: 1, CoolDown: time.Minute})
+	client := newTestTransportClient(breaker)
+
+	for i := 0; i < 2; i++ {
+		_, err := doTestRequest(context.Background(), client, url)
+		var limitErr *RateLimitedError
+		if !errors.As(err, &limitErr) {
+			t.Fatalf("request %d = %v, want RateLimitedError", i+1, err)
+		}
+		if limitErr.Provider != "test" || limitErr.ResumeAt.Sub(resetAt).Abs() > time.Second {
+			t.Errorf("request %d = %+v, want provider test resuming at %v", i+1, limitErr, resetAt)
+		}
+	}
+	if got := stub.hitCount(); got != 1 {
+		t.Errorf("hits = %d, want 1", got)
+	}
+	if got := breaker.State(); got != CircuitClosed {
+		t.Errorf("State() = %s, want %s", got, CircuitClosed)
+	}
+}
+
+func TestRetryTransportHonoursRateReset(t *testing.T) {
+	resetAt := time.Unix(time.Now().Unix()+2, 0)
+	stub := &transportStub{responses: []func(w http.ResponseWriter){
+		// Qiitaはレート制限を超えると 403 と Rate-Remaining: 0 を返す
+		respondStatus(http.StatusForbidden, map[string]string{
+			"Rate-Remaining": "0",
+			"Rate-Reset":     strconv.FormatInt(resetAt.Unix(), 10),
+		}),
+		respondStatus(http.StatusOK, nil),
+	}}
+	url := newTestTransportServer(t, stub)
+	client := newTestTransportClient(nil)
+
+	resp, err := doTestRequest(context.Background(), client, url)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("request = %v, %v, want 200 after reset", resp, err)
+	}
+	if now := time.Now(); now.Before(resetAt) {
+		t.Errorf("request finished at %v, want after Rate-Reset %v", now, resetAt)
+	}
+	if got := stub.hitCount(); got != 2 {
+		t.Errorf("hits = %d, want 2", got)
+	}
+}
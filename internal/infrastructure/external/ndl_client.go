@@ -0,0 +1,111 @@
+package external
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"teckbook-compass-backend/internal/domain/entity"
+	"teckbook-compass-backend/internal/infrastructure/config"
+)
+
+// NDLClient 国立国会図書館サーチ（OpenSearch）クライアント
+// 絶版書や自費出版の書籍など、他のプロバイダにない書籍の最後の補完に使う
+type NDLClient struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewNDLClient NDLClientを生成
+func NewNDLClient(cfg config.MetadataConfig, ledger *UsageLedger) *NDLClient {
+	return &NDLClient{
+		baseURL: cfg.NDLBaseURL,
+		httpClient: newHTTPClient(transportOptions{
+			provider: "ndl",
+			timeout:  30 * time.Second,
+			breaker:  NewCircuitBreaker("ndl", cfg.Circuit),
+			ledger:   ledger,
+			policy:   defaultRetryPolicy,
+		}),
+	}
+}
+
+// Retries 実行したリトライ回数
+func (c *NDLClient) Retries() int {
+	return retryCount(c.httpClient)
+}
+
+// CircuitState サーキットブレーカーの状態
+func (c *NDLClient) CircuitState() CircuitState {
+	return circuitState(c.httpClient)
+}
+
+// ndlRSS OpenSearchのレスポンス（RSS 2.0）
+type ndlRSS struct {
+	Channel struct {
+		Items []ndlItem `xml:"item"`
+	} `xml:"channel"`
+}
+
+// ndlItem OpenSearchの書誌1件（dc, dcterms 名前空間の要素はローカル名で取得）
+type ndlItem struct {
+	Title     string `xml:"title"`
+	Author    string `xml:"author"`
+	Publisher string `xml:"publisher"`
+	Issued    string `xml:"issued"`
+}
+
+// Name プロバイダ名
+func (c *NDLClient) Name() string {
+	return "ndl"
+}
+
+// LookupByISBN ISBNで書籍メタデータを取得
+func (c *NDLClient) LookupByISBN(ctx context.Context, isbn string) (*entity.BookMetadata, error) {
+	params := url.Values{}
+	params.Set("isbn", isbn)
+	params.Set("cnt", "1")
+	reqURL := fmt.Sprintf("%s/opensearch?%s", c.baseURL, params.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API returned non-200 status: %d, body: %s", resp.StatusCode, string(body))
+	}
+
+	var rss ndlRSS
+	if err := xml.Unmarshal(body, &rss); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	if len(rss.Channel.Items) == 0 {
+		return nil, fmt.Errorf("%w: ISBN %s", ErrBookMetadataNotFound, isbn)
+	}
+
+	item := rss.Channel.Items[0]
+	return &entity.BookMetadata{
+		ISBN:        entity.ToISBN13(isbn),
+		Title:       strings.TrimSpace(item.Title),
+		Author:      strings.TrimSpace(item.Author),
+		Publisher:   strings.TrimSpace(item.Publisher),
+		PublishedAt: parseMetadataDate(item.Issued),
+	}, nil
+}
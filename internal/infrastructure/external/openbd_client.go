@@ -0,0 +1,185 @@
+package external
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"teckbook-compass-backend/internal/domain/entity"
+	"teckbook-compass-backend/internal/infrastructure/config"
+)
+
+// OpenBDClient openBD APIクライアント
+// 版元ドットコムの書誌情報を持つため、楽天にない書籍や長い内容紹介の補完に使う
+type OpenBDClient struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewOpenBDClient OpenBDClientを生成
+func NewOpenBDClient(cfg config.MetadataConfig, ledger *UsageLedger) *OpenBDClient {
+	return &OpenBDClient{
+		baseURL: cfg.OpenBDBaseURL,
+		httpClient: newHTTPClient(transportOptions{
+			provider: "openbd",
+			timeout:  30 * time.Second,
+			breaker:  NewCircuitBreaker("openbd", cfg.Circuit),
+			ledger:   ledger,
+			policy:   defaultRetryPolicy,
+		}),
+	}
+}
+
+// Retries 実行したリトライ回数
+func (c *OpenBDClient) Retries() int {
+	return retryCount(c.httpClient)
+}
+
+// CircuitState サーキットブレーカーの状態
+func (c *OpenBDClient) CircuitState() CircuitState {
+	return circuitState(c.httpClient)
+}
+
+// openBDRecord openBD APIのレスポンス（1冊分）
+type openBDRecord struct {
+	Summary struct {
+		ISBN      string `json:"isbn"`
+		Title     string `json:"title"`
+		Publisher string `json:"publisher"`
+		Pubdate   string `json:"pubdate"`
+		Cover     string `json:"cover"`
+		Author    string `json:"author"`
+	} `json:"summary"`
+	Onix struct {
+		CollateralDetail struct {
+			TextContent []struct {
+				TextType string `json:"TextType"`
+				Text     string `json:"Text"`
+			} `json:"TextContent"`
+		} `json:"CollateralDetail"`
+		ProductSupply struct {
+			SupplyDetail struct {
+				Price []struct {
+					PriceAmount string `json:"PriceAmount"`
+				} `json:"Price"`
+			} `json:"SupplyDetail"`
+		} `json:"ProductSupply"`
+	} `json:"onix"`
+}
+
+// Name プロバイダ名
+func (c *OpenBDClient) Name() string {
+	return "openbd"
+}
+
+// LookupByISBN ISBNで書籍メタデータを取得
+func (c *OpenBDClient) LookupByISBN(ctx context.Context, isbn string) (*entity.BookMetadata, error) {
+	params := url.Values{}
+	params.Set("isbn", isbn)
+	reqURL := fmt.Sprintf("%s/get?%s", c.baseURL, params.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API returned non-200 status: %d, body: %s", resp.StatusCode, string(body))
+	}
+
+	// 見つからない場合は [null] が返る
+	var records []*openBDRecord
+	if err := json.Unmarshal(body, &records); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	if len(records) == 0 || records[0] == nil {
+		return nil, fmt.Errorf("%w: ISBN %s", ErrBookMetadataNotFound, isbn)
+	}
+
+	return records[0].toMetadata(isbn), nil
+}
+
+// toMetadata openBDのレコードを書籍メタデータに変換
+func (r *openBDRecord) toMetadata(isbn string) *entity.BookMetadata {
+	metadata := &entity.BookMetadata{
+		ISBN:        entity.ToISBN13(isbn),
+		Title:       r.Summary.Title,
+		Author:      cleanOpenBDAuthor(r.Summary.Author),
+		Publisher:   r.Summary.Publisher,
+		PublishedAt: parseMetadataDate(r.Summary.Pubdate),
+		Thumbnail:   r.Summary.Cover,
+	}
+
+	// 内容紹介（02: 短い紹介, 03: 詳細な紹介）のうち長い方を概要にする
+	for _, text := range r.Onix.CollateralDetail.TextContent {
+		if text.TextType != "02" && text.TextType != "03" {
+			continue
+		}
+		if len([]rune(text.Text)) > len([]rune(metadata.Overview)) {
+			metadata.Overview = text.Text
+		}
+	}
+
+	if prices := r.Onix.ProductSupply.SupplyDetail.Price; len(prices) > 0 {
+		if price, err := strconv.Atoi(prices[0].PriceAmount); err == nil {
+			metadata.Price = price
+		}
+	}
+
+	return metadata
+}
+
+// cleanOpenBDAuthor 著者表記から役割（「／著」など）を取り除く
+// 例: "山田太郎／著 鈴木花子／訳" → "山田太郎, 鈴木花子"
+func cleanOpenBDAuthor(author string) string {
+	fields := strings.Fields(author)
+	names := make([]string, 0, len(fields))
+	for _, field := range fields {
+		name, _, _ := strings.Cut(field, "／")
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+// metadataDateLayouts 書誌情報APIで使われる出版日の形式
+var metadataDateLayouts = []string{
+	"20060102",
+	"200601",
+	"2006-01-02",
+	"2006-01",
+	"2006.1",
+	"2006",
+}
+
+// parseMetadataDate 出版日をパース（解釈できない場合はnil）
+func parseMetadataDate(s string) *time.Time {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil
+	}
+	for _, layout := range metadataDateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return &t
+		}
+	}
+	return nil
+}
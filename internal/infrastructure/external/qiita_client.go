@@ -3,16 +3,21 @@ package external
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
 	"time"
 
 	"teckbook-compass-backend/internal/domain/entity"
 	"teckbook-compass-backend/internal/infrastructure/config"
 )
 
+// ErrArticleNotFound 記事が削除または非公開化されている（取得元共通）
+var ErrArticleNotFound = errors.New("article not found")
+
 // QiitaClient Qiita APIクライアント
 type QiitaClient struct {
 	config     config.QiitaConfig
@@ -20,16 +25,41 @@ type QiitaClient struct {
 }
 
 // NewQiitaClient QiitaClientを生成
-func NewQiitaClient(cfg config.QiitaConfig) *QiitaClient {
+func NewQiitaClient(cfg config.QiitaConfig, ledger *UsageLedger) *QiitaClient {
 	return &QiitaClient{
 		config: cfg,
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+		httpClient: newHTTPClient(transportOptions{
+			provider: entity.SourceQiita,
+			timeout:  30 * time.Second,
+			limiter:  NewRateLimiter(cfg.RateLimit, 1), // 並行処理のワーカー間で共有する
+			breaker:  NewCircuitBreaker(entity.SourceQiita, cfg.Circuit),
+			ledger:   ledger,
+			policy:   defaultRetryPolicy,
+		}),
 	}
 }
 
-// SearchQueries 技術書関連の検索クエリ
+// Retries 実行したリトライ回数
+func (c *QiitaClient) Retries() int {
+	return retryCount(c.httpClient)
+}
+
+// CircuitState サーキットブレーカーの状態
+func (c *QiitaClient) CircuitState() CircuitState {
+	return circuitState(c.httpClient)
+}
+
+// Name 取得元の識別子
+func (c *QiitaClient) Name() string {
+	return entity.SourceQiita
+}
+
+// DefaultQueries 既定の検索クエリ
+func (c *QiitaClient) DefaultQueries() []string {
+	return SearchQueries
+}
+
+// SearchQueries 技術書関連の検索クエリ（search_queries が未登録の場合のみ使用）
 var SearchQueries = []string{
 	"技術書",
 	"書籍",
@@ -44,6 +74,12 @@ var SearchQueries = []string{
 
 // SearchArticles 検索クエリで記事を取得
 func (c *QiitaClient) SearchArticles(ctx context.Context, query string, page int, perPage int) ([]*entity.QiitaAPIArticle, error) {
+	articles, _, err := c.searchArticlesWithTotal(ctx, query, page, perPage)
+	return articles, err
+}
+
+// searchArticlesWithTotal 検索クエリで記事を取得し、検索結果の総件数（Total-Count）も返す
+func (c *QiitaClient) searchArticlesWithTotal(ctx context.Context, query string, page int, perPage int) ([]*entity.QiitaAPIArticle, int, error) {
 	// URLを構築
 	baseURL := fmt.Sprintf("%s/items", c.config.BaseURL)
 	params := url.Values{}
@@ -54,9 +90,9 @@ func (c *QiitaClient) SearchArticles(ctx context.Context, query string, page int
 	reqURL := fmt.Sprintf("%s?%s", baseURL, params.Encode())
 
 	// リクエストを作成
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	req, err := http.NewRequestWithContext(withEndpoint(ctx, "items.search"), http.MethodGet, reqURL, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, 0, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	// ヘッダーを設定
@@ -66,28 +102,31 @@ func (c *QiitaClient) SearchArticles(ctx context.Context, query string, page int
 	// リクエストを実行
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute request: %w", err)
+		return nil, 0, fmt.Errorf("failed to execute request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	// レスポンスボディを読み取り
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+		return nil, 0, fmt.Errorf("failed to read response body: %w", err)
 	}
 
 	// ステータスコードを確認
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API returned non-200 status: %d, body: %s", resp.StatusCode, string(body))
+		return nil, 0, fmt.Errorf("API returned non-200 status: %d, body: %s", resp.StatusCode, string(body))
 	}
 
 	// JSONをパース
 	var articles []*entity.QiitaAPIArticle
 	if err := json.Unmarshal(body, &articles); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+		return nil, 0, fmt.Errorf("failed to unmarshal response: %w", err)
 	}
 
-	return articles, nil
+	// 総件数はヘッダーから取得（取得できない場合は0）
+	total, _ := strconv.Atoi(resp.Header.Get("Total-Count"))
+
+	return articles, total, nil
 }
 
 // FetchAllArticlesForQuery 特定のクエリですべてのページの記事を取得（最大ページ数制限付き）
@@ -107,9 +146,6 @@ func (c *QiitaClient) FetchAllArticlesForQuery(ctx context.Context, query string
 		if len(articles) < perPage {
 			break
 		}
-
-		// レートリミット対策：1秒待機
-		time.Sleep(1 * time.Second)
 	}
 
 	return allArticles, nil
@@ -146,9 +182,6 @@ func (c *QiitaClient) FetchArticlesByQueries(ctx context.Context, queries []stri
 
 		fmt.Printf("  [%s] 取得: %d件, 新規: %d件, 重複: %d件, 累計: %d件\n",
 			query, len(articles), newCount, duplicateCount, len(allArticles))
-
-		// レートリミット対策：クエリ間で待機
-		time.Sleep(2 * time.Second)
 	}
 
 	fmt.Printf("\n=== 合計: %d件（重複排除後） ===\n\n", len(allArticles))
@@ -157,10 +190,10 @@ func (c *QiitaClient) FetchArticlesByQueries(ctx context.Context, queries []stri
 }
 
 // GetArticle 記事IDで記事詳細を取得
-func (c *QiitaClient) GetArticle(ctx context.Context, articleID string) (*entity.QiitaAPIArticle, error) {
+func (c *QiitaClient) GetArticle(ctx context.Context, articleID string) (*entity.SourceArticle, error) {
 	reqURL := fmt.Sprintf("%s/items/%s", c.config.BaseURL, articleID)
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	req, err := http.NewRequestWithContext(withEndpoint(ctx, "items.get"), http.MethodGet, reqURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -179,6 +212,10 @@ func (c *QiitaClient) GetArticle(ctx context.Context, articleID string) (*entity
 		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
 
+	if isArticleGone(resp) {
+		return nil, fmt.Errorf("%w: %s (status %d)", ErrArticleNotFound, articleID, resp.StatusCode)
+	}
+
 	if resp.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("API returned non-200 status: %d, body: %s", resp.StatusCode, string(body))
 	}
@@ -188,7 +225,46 @@ func (c *QiitaClient) GetArticle(ctx context.Context, articleID string) (*entity
 		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
 	}
 
-	return &article, nil
+	return article.ToSourceArticle(), nil
+}
+
+// GetComments 記事のコメント一覧を取得
+func (c *QiitaClient) GetComments(ctx context.Context, articleID string) ([]*entity.ArticleComment, error) {
+	reqURL := fmt.Sprintf("%s/items/%s/comments", c.config.BaseURL, articleID)
+
+	req, err := http.NewRequestWithContext(withEndpoint(ctx, "comments.list"), http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.config.AccessToken))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API returned non-200 status: %d, body: %s", resp.StatusCode, string(body))
+	}
+
+	var comments []*entity.QiitaComment
+	if err := json.Unmarshal(body, &comments); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	result := make([]*entity.ArticleComment, 0, len(comments))
+	for _, comment := range comments {
+		result = append(result, comment.ToArticleComment())
+	}
+	return result, nil
 }
 
 // GetArticleStocksCount 記事のストック数を取得
@@ -199,7 +275,7 @@ func (c *QiitaClient) GetArticleStocksCount(ctx context.Context, articleID strin
 	params.Set("per_page", "1")
 	reqURL = fmt.Sprintf("%s?%s", reqURL, params.Encode())
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	req, err := http.NewRequestWithContext(withEndpoint(ctx, "stockers.list"), http.MethodGet, reqURL, nil)
 	if err != nil {
 		return 0, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -213,6 +289,15 @@ func (c *QiitaClient) GetArticleStocksCount(ctx context.Context, articleID strin
 	}
 	defer resp.Body.Close()
 
+	if isArticleGone(resp) {
+		return 0, fmt.Errorf("%w: %s (status %d)", ErrArticleNotFound, articleID, resp.StatusCode)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return 0, fmt.Errorf("API returned non-200 status: %d, body: %s", resp.StatusCode, string(body))
+	}
+
 	// Total-Count ヘッダーからストック数を取得
 	totalCount := resp.Header.Get("Total-Count")
 	if totalCount == "" {
@@ -228,60 +313,53 @@ func (c *QiitaClient) GetArticleStocksCount(ctx context.Context, articleID strin
 	return count, nil
 }
 
-// QueryStats クエリごとの取得統計
-type QueryStats struct {
-	Query      string // クエリ文字列
-	Fetched    int    // 取得件数
-	New        int    // 新規件数（重複排除後）
-	Duplicates int    // 重複件数
-}
-
-// FetchStats 取得統計
-type FetchStats struct {
-	QueryStats []QueryStats // クエリごとの統計
-	Total      int          // 合計件数
-}
-
-// FetchNewArticles 最新記事を取得（指定日時以降の記事）
-func (c *QiitaClient) FetchNewArticles(ctx context.Context, queries []string, since *time.Time, maxPagesPerQuery int) ([]*entity.QiitaAPIArticle, *FetchStats, error) {
-	var allArticles []*entity.QiitaAPIArticle
+// FetchNewArticles 最新記事を取得（クエリごとの前回取得日時から overlap だけ遡った日以降に作成・更新された記事）
+// 取得に成功したクエリは LastNewFetchedAt を更新する
+func (c *QiitaClient) FetchNewArticles(ctx context.Context, states []*entity.CrawlState, overlap time.Duration, maxPagesPerQuery int) ([]*entity.SourceArticle, *entity.FetchStats, error) {
+	var allArticles []*entity.SourceArticle
 	seen := make(map[string]bool)
-	stats := &FetchStats{QueryStats: make([]QueryStats, 0, len(queries))}
+	stats := &entity.FetchStats{QueryStats: make([]entity.QueryStats, 0, len(states))}
 
 	fmt.Printf("\n=== 最新記事取得モード ===\n")
-	if since != nil {
-		fmt.Printf("取得対象: %s 以降の記事\n", since.Format("2006-01-02 15:04:05"))
-	}
-	fmt.Printf("検索クエリ数: %d, 各クエリ最大ページ数: %d\n\n", len(queries), maxPagesPerQuery)
+	fmt.Printf("検索クエリ数: %d, 各クエリ最大ページ数: %d\n\n", len(states), maxPagesPerQuery)
 
-	for _, query := range queries {
-		// 検索クエリに日時フィルタを追加
+	for _, state := range states {
+		query := state.Query
+		since := state.NewFetchSince(overlap)
+		startedAt := time.Now()
+
+		// 検索クエリに日時フィルタを追加（クエリごとの前回取得日時から overlap だけ遡った日以降）
 		searchQuery := query
 		if since != nil {
-			// Qiita APIの検索では created:>YYYY-MM-DD 形式でフィルタ可能
-			searchQuery = fmt.Sprintf("%s created:>%s", query, since.Format("2006-01-02"))
+			// Qiita APIの検索は日付単位のため、境界の日を含めて updated:>=YYYY-MM-DD で絞り込む
+			// 更新日時で絞り込むことで、作成後に編集された記事も取得する
+			searchQuery = fmt.Sprintf("%s updated:>=%s", query, since.Format("2006-01-02"))
 		}
 
 		articles, err := c.FetchAllArticlesForQuery(ctx, searchQuery, maxPagesPerQuery)
 		if err != nil {
 			fmt.Printf("  [%s] エラー: %v\n", query, err)
-			stats.QueryStats = append(stats.QueryStats, QueryStats{Query: query, Fetched: 0, New: 0, Duplicates: 0})
+			stats.QueryStats = append(stats.QueryStats, entity.QueryStats{Source: entity.SourceQiita, Query: query})
 			continue
 		}
+		state.LastNewFetchedAt = &startedAt
 
 		newCount := 0
 		dupCount := 0
 		for _, article := range articles {
 			if !seen[article.ID] {
 				seen[article.ID] = true
-				allArticles = append(allArticles, article)
+				sourceArticle := article.ToSourceArticle()
+				sourceArticle.Query = query
+				allArticles = append(allArticles, sourceArticle)
 				newCount++
 			} else {
 				dupCount++
 			}
 		}
 
-		stats.QueryStats = append(stats.QueryStats, QueryStats{
+		stats.QueryStats = append(stats.QueryStats, entity.QueryStats{
+			Source:     entity.SourceQiita,
 			Query:      query,
 			Fetched:    len(articles),
 			New:        newCount,
@@ -290,8 +368,6 @@ func (c *QiitaClient) FetchNewArticles(ctx context.Context, queries []string, si
 
 		fmt.Printf("  [%s] 取得: %d件, 新規: %d件, 累計: %d件\n",
 			query, len(articles), newCount, len(allArticles))
-
-		time.Sleep(2 * time.Second)
 	}
 
 	stats.Total = len(allArticles)
@@ -299,74 +375,102 @@ func (c *QiitaClient) FetchNewArticles(ctx context.Context, queries []string, si
 	return allArticles, stats, nil
 }
 
-// FetchHistoricalArticles 過去記事を取得（指定ページから）
-func (c *QiitaClient) FetchHistoricalArticles(ctx context.Context, queries []string, startPage int, pagesPerQuery int) ([]*entity.QiitaAPIArticle, int, *FetchStats, error) {
-	var allArticles []*entity.QiitaAPIArticle
+// Qiita検索のページング上限（page は100まで、per_page は100まで）
+const (
+	qiitaMaxPage          = 100
+	qiitaPerPage          = 100
+	qiitaMaxSearchResults = qiitaMaxPage * qiitaPerPage
+
+	// 件数が少ない期間の次は期間を延ばすが、この日数を上限とする
+	qiitaMaxWindowDays = 365
+)
+
+// FetchHistoricalArticles 過去記事を取得（クエリごとの期間を新しい方から遡って各 pagesPerQuery ページまで）
+// 期間の件数が検索の上限を超える場合は期間を分割して同じ回で取り直し、下限日まで遡ったクエリは完了とする
+// 分割の判定に使ったリクエストは pagesPerQuery に数えない（1日の期間は分割できないため、取り直す回数には上限がある）
+func (c *QiitaClient) FetchHistoricalArticles(ctx context.Context, states []*entity.CrawlState, pagesPerQuery int) ([]*entity.SourceArticle, *entity.FetchStats, error) {
+	var allArticles []*entity.SourceArticle
 	seen := make(map[string]bool)
-	perPage := 100
-	stats := &FetchStats{QueryStats: make([]QueryStats, 0, len(queries))}
+	stats := &entity.FetchStats{QueryStats: make([]entity.QueryStats, 0, len(states))}
 
 	fmt.Printf("\n=== 過去記事取得モード ===\n")
-	fmt.Printf("開始ページ: %d, 各クエリ取得ページ数: %d\n\n", startPage, pagesPerQuery)
+	fmt.Printf("クエリ数: %d, 各クエリ取得ページ数: %d\n\n", len(states), pagesPerQuery)
 
-	endPage := startPage + pagesPerQuery - 1
-	hasMorePages := false
+	for _, state := range states {
+		if state.IsCompleted() {
+			continue
+		}
+		if !state.IsStarted() {
+			state.Start(time.Now().AddDate(0, 0, 1), c.config.HistoricalWindowDays)
+		}
 
-	for _, query := range queries {
-		queryFetched := 0
-		queryNew := 0
-		queryDup := 0
+		queryStats := entity.QueryStats{Source: entity.SourceQiita, Query: state.Query}
 
-		for page := startPage; page <= endPage; page++ {
-			articles, err := c.SearchArticles(ctx, query, page, perPage)
+		for pages := 0; pages < pagesPerQuery && !state.IsCompleted(); {
+			windowQuery := fmt.Sprintf("%s created:>=%s created:<%s",
+				state.Query, state.WindowStart.Format("2006-01-02"), state.WindowEnd.Format("2006-01-02"))
+
+			articles, total, err := c.searchArticlesWithTotal(ctx, windowQuery, state.Page, qiitaPerPage)
 			if err != nil {
-				fmt.Printf("  [%s] ページ%d エラー: %v\n", query, page, err)
+				fmt.Printf("  [%s] %s エラー: %v\n", state.Query, state, err)
 				break
 			}
 
-			queryFetched += len(articles)
+			// 期間の件数が上限を超える場合は期間を分割して取り直す（このリクエストはページ数に数えない）
+			if state.Page == 1 && total > qiitaMaxSearchResults && state.Subdivide() {
+				fmt.Printf("  [%s] %d件のため期間を分割: %s\n", state.Query, total, state)
+				continue
+			}
+			pages++
 
+			queryStats.Fetched += len(articles)
 			for _, article := range articles {
 				if !seen[article.ID] {
 					seen[article.ID] = true
-					allArticles = append(allArticles, article)
-					queryNew++
+					sourceArticle := article.ToSourceArticle()
+					sourceArticle.Query = state.Query
+					allArticles = append(allArticles, sourceArticle)
+					queryStats.New++
 				} else {
-					queryDup++
+					queryStats.Duplicates++
 				}
 			}
 
-			// まだ次のページがあるかチェック
-			if len(articles) == perPage {
-				hasMorePages = true
+			lastPage := (total + qiitaPerPage - 1) / qiitaPerPage
+			if lastPage > qiitaMaxPage {
+				lastPage = qiitaMaxPage
 			}
 
-			if len(articles) < perPage {
-				break
+			if len(articles) < qiitaPerPage || state.Page >= lastPage {
+				// 期間を取得し終えたら一つ前の期間へ（件数が少なければ期間を延ばす）
+				windowDays := state.WindowDays
+				if total < qiitaMaxSearchResults/4 {
+					windowDays = min(windowDays*2, qiitaMaxWindowDays)
+				}
+				state.Advance(windowDays, c.config.HistoricalFloor, time.Now())
+			} else {
+				state.Page++
 			}
-
-			time.Sleep(1 * time.Second)
 		}
 
-		stats.QueryStats = append(stats.QueryStats, QueryStats{
-			Query:      query,
-			Fetched:    queryFetched,
-			New:        queryNew,
-			Duplicates: queryDup,
-		})
-
-		fmt.Printf("  [%s] ページ%d-%d: 取得%d件, 新規%d件, 累計: %d件\n",
-			query, startPage, endPage, queryFetched, queryNew, len(allArticles))
-
-		time.Sleep(2 * time.Second)
-	}
-
-	nextPage := startPage
-	if hasMorePages {
-		nextPage = endPage + 1
+		stats.QueryStats = append(stats.QueryStats, queryStats)
+		fmt.Printf("  %s: 取得%d件, 新規%d件, 累計: %d件\n", state, queryStats.Fetched, queryStats.New, len(allArticles))
 	}
 
 	stats.Total = len(allArticles)
-	fmt.Printf("\n=== 過去記事合計: %d件, 次回開始ページ: %d ===\n\n", len(allArticles), nextPage)
-	return allArticles, nextPage, stats, nil
+	fmt.Printf("\n=== 過去記事合計: %d件 ===\n\n", len(allArticles))
+	return allArticles, stats, nil
+}
+
+// isArticleGone 記事が削除（404）または非公開化（403）されたレスポンスか
+// Qiitaはレートリミット超過時も403を返すため、残りリクエスト数が0の場合は除外する
+func isArticleGone(resp *http.Response) bool {
+	switch resp.StatusCode {
+	case http.StatusNotFound:
+		return true
+	case http.StatusForbidden:
+		return resp.Header.Get("Rate-Remaining") != "0"
+	default:
+		return false
+	}
 }
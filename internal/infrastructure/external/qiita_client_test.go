@@ -0,0 +1,81 @@
+package external
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"teckbook-compass-backend/internal/domain/entity"
+	"teckbook-compass-backend/internal/infrastructure/config"
+)
+
+// windowSearchStub 検索期間の日数に比例した件数を返すQiita検索APIのスタブ（1日あたり1000件）
+type windowSearchStub struct {
+	mu       sync.Mutex
+	requests []string
+}
+
+func (s *windowSearchStub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query().Get("query")
+	s.mu.Lock()
+	s.requests = append(s.requests, query)
+	s.mu.Unlock()
+
+	var start, end time.Time
+	for _, field := range strings.Fields(query) {
+		if value, ok := strings.CutPrefix(field, "created:>="); ok {
+			start, _ = time.Parse("2006-01-02", value)
+		} else if value, ok := strings.CutPrefix(field, "created:<"); ok {
+			end, _ = time.Parse("2006-01-02", value)
+		}
+	}
+	days := int(end.Sub(start).Hours() / 24)
+
+	w.Header().Set("Total-Count", strconv.Itoa(days*1000))
+	_ = json.NewEncoder(w).Encode([]*entity.QiitaAPIArticle{
+		{ID: "a-" + start.Format("20060102")},
+		{ID: "b-" + start.Format("20060102")},
+	})
+}
+
+func TestQiitaClientFetchHistoricalArticlesRefetchesSubdividedWindow(t *testing.T) {
+	stub := &windowSearchStub{}
+	server := httptest.NewServer(stub)
+	t.Cleanup(server.Close)
+
+	client := NewQiitaClient(config.QiitaConfig{
+		BaseURL:              server.URL,
+		HistoricalWindowDays: 30,
+		HistoricalFloor:      time.Date(2011, 1, 1, 0, 0, 0, 0, time.UTC),
+	}, nil)
+
+	state := entity.NewCrawlState(entity.SourceQiita, "Go")
+	end := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	state.Start(end, 30)
+
+	// 30日（30000件）→ 15日（15000件）→ 7日（7000件）と分割し、分割後の期間をページ数1件の中で取得する
+	articles, stats, err := client.FetchHistoricalArticles(context.Background(), []*entity.CrawlState{state}, 1)
+	if err != nil {
+		t.Fatalf("FetchHistoricalArticles() error = %v", err)
+	}
+	if got := len(stub.requests); got != 3 {
+		t.Fatalf("requests = %d (%v), want 3", got, stub.requests)
+	}
+	if len(articles) != 2 {
+		t.Errorf("articles = %d, want 2 from the narrowed window", len(articles))
+	}
+	if got := stats.QueryStats[0].Fetched; got != 2 {
+		t.Errorf("Fetched = %d, want 2", got)
+	}
+
+	// 取得し終えた期間の一つ前の期間へ進んでいる
+	if !state.WindowEnd.Equal(end.AddDate(0, 0, -7)) {
+		t.Errorf("WindowEnd = %v, want %v", state.WindowEnd, end.AddDate(0, 0, -7))
+	}
+}
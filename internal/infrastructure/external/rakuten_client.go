@@ -3,6 +3,7 @@ package external
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -17,16 +18,78 @@ import (
 type RakutenClient struct {
 	config     config.RakutenConfig
 	httpClient *http.Client
+	ledger     *UsageLedger
+}
+
+// rakutenRetryPolicy 楽天ブックスAPIのリトライ設定
+// 1回のバッチで問い合わせる書籍数が多いため、リトライ回数の上限を多めにする
+var rakutenRetryPolicy = retryPolicy{
+	maxRetries: 3,
+	baseDelay:  time.Second,
+	maxDelay:   30 * time.Second,
+	budget:     100,
 }
 
 // NewRakutenClient RakutenClientを生成
-func NewRakutenClient(cfg config.RakutenConfig) *RakutenClient {
+func NewRakutenClient(cfg config.RakutenConfig, ledger *UsageLedger) *RakutenClient {
 	return &RakutenClient{
 		config: cfg,
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+		ledger: ledger,
+		httpClient: newHTTPClient(transportOptions{
+			provider: "rakuten",
+			timeout:  30 * time.Second,
+			limiter:  NewRateLimiter(cfg.RateLimit, 1), // 並行処理のワーカー間で共有する
+			breaker:  NewCircuitBreaker("rakuten", cfg.Circuit),
+			ledger:   ledger,
+			policy:   rakutenRetryPolicy,
+		}),
+	}
+}
+
+// Retries 実行したリトライ回数
+func (c *RakutenClient) Retries() int {
+	return retryCount(c.httpClient)
+}
+
+// Usage 当日の使用量と1日の上限
+func (c *RakutenClient) Usage() ProviderUsage {
+	return c.ledger.Usage(c.Name())
+}
+
+// CircuitState サーキットブレーカーの状態
+func (c *RakutenClient) CircuitState() CircuitState {
+	return circuitState(c.httpClient)
+}
+
+// ErrBookMetadataNotFound 書籍メタデータが見つからない
+var ErrBookMetadataNotFound = errors.New("book metadata not found")
+
+// Name プロバイダ名
+func (c *RakutenClient) Name() string {
+	return "rakuten"
+}
+
+// LookupByISBN ISBNで書籍メタデータを取得
+func (c *RakutenClient) LookupByISBN(ctx context.Context, isbn string) (*entity.BookMetadata, error) {
+	book, err := c.SearchByISBN(ctx, isbn)
+	if err != nil {
+		return nil, err
+	}
+	return book.ToMetadata(), nil
+}
+
+// SearchMetadataByTitle タイトルで書籍メタデータを検索
+func (c *RakutenClient) SearchMetadataByTitle(ctx context.Context, title string) ([]*entity.BookMetadata, error) {
+	books, err := c.SearchByTitle(ctx, title)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*entity.BookMetadata, 0, len(books))
+	for _, book := range books {
+		results = append(results, book.ToMetadata())
 	}
+	return results, nil
 }
 
 // SearchByISBN ISBNで書籍を検索
@@ -39,7 +102,7 @@ func (c *RakutenClient) SearchByISBN(ctx context.Context, isbn string) (*entity.
 
 	reqURL := fmt.Sprintf("%s?%s", c.config.BaseURL, params.Encode())
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	req, err := http.NewRequestWithContext(withEndpoint(ctx, "search.isbn"), http.MethodGet, reqURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -65,7 +128,7 @@ func (c *RakutenClient) SearchByISBN(ctx context.Context, isbn string) (*entity.
 	}
 
 	if len(response.Items) == 0 {
-		return nil, fmt.Errorf("no book found for ISBN: %s", isbn)
+		return nil, fmt.Errorf("%w: ISBN %s", ErrBookMetadataNotFound, isbn)
 	}
 
 	return &response.Items[0].Item, nil
@@ -82,7 +145,7 @@ func (c *RakutenClient) SearchByTitle(ctx context.Context, title string) ([]*ent
 
 	reqURL := fmt.Sprintf("%s?%s", c.config.BaseURL, params.Encode())
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	req, err := http.NewRequestWithContext(withEndpoint(ctx, "search.title"), http.MethodGet, reqURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -127,7 +190,7 @@ func (c *RakutenClient) SearchByKeyword(ctx context.Context, keyword string) ([]
 
 	reqURL := fmt.Sprintf("%s?%s", c.config.BaseURL, params.Encode())
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	req, err := http.NewRequestWithContext(withEndpoint(ctx, "search.keyword"), http.MethodGet, reqURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
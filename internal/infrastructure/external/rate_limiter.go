@@ -0,0 +1,75 @@
+package external
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RateLimiter 外部APIへのリクエスト数を制限するトークンバケット
+// 1秒あたり perSecond 個のトークンを補充し、最大 burst 個まで貯める。リクエストの前に Wait でトークンを1個消費する
+// 複数のゴルーチンから同時に使用できる。nil の場合は制限しない
+type RateLimiter struct {
+	mu        sync.Mutex
+	perSecond float64
+	burst     float64
+	tokens    float64
+	last      time.Time
+}
+
+// NewRateLimiter RateLimiterを生成（perSecond が0以下の場合は制限しないため nil を返す）
+func NewRateLimiter(perSecond float64, burst int) *RateLimiter {
+	if perSecond <= 0 {
+		return nil
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	return &RateLimiter{
+		perSecond: perSecond,
+		burst:     float64(burst),
+		tokens:    float64(burst),
+		last:      time.Now(),
+	}
+}
+
+// Wait トークンを1個消費する（トークンがなければ補充されるまで待つ）
+// 待機中に ctx がキャンセルされた場合はエラーを返す
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	if l == nil {
+		return ctx.Err()
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.perSecond
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+
+	// 先にトークンを予約し（負になる場合は不足分を待つ）、待機の順番を呼び出し順にする
+	l.tokens--
+	wait := time.Duration(0)
+	if l.tokens < 0 {
+		wait = time.Duration(-l.tokens / l.perSecond * float64(time.Second))
+	}
+	l.mu.Unlock()
+
+	if wait == 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		// 予約したトークンを返す
+		l.mu.Lock()
+		l.tokens++
+		l.mu.Unlock()
+		return ctx.Err()
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"time"
 
+	"teckbook-compass-backend/internal/domain/entity"
 	"teckbook-compass-backend/internal/infrastructure/config"
 )
 
@@ -17,16 +18,30 @@ type SlackClient struct {
 	httpClient *http.Client
 }
 
+// slackRetryPolicy Slack通知のリトライ設定（通知でバッチを長く止めないよう控えめにする）
+var slackRetryPolicy = retryPolicy{
+	maxRetries: 2,
+	baseDelay:  time.Second,
+	maxDelay:   10 * time.Second,
+	budget:     5,
+}
+
 // NewSlackClient SlackClientを生成
 func NewSlackClient(cfg config.SlackConfig) *SlackClient {
 	return &SlackClient{
 		config: cfg,
-		httpClient: &http.Client{
-			Timeout: 10 * time.Second,
-		},
+		httpClient: newHTTPClient(transportOptions{
+			timeout: 10 * time.Second,
+			policy:  slackRetryPolicy,
+		}),
 	}
 }
 
+// Retries 実行したリトライ回数
+func (c *SlackClient) Retries() int {
+	return retryCount(c.httpClient)
+}
+
 // SlackMessage Slackメッセージ構造体
 type SlackMessage struct {
 	Text        string            `json:"text"`
@@ -64,7 +79,8 @@ func (c *SlackClient) SendStartMessage(fetchMode string) error {
 }
 
 // SendResultMessage バッチ結果メッセージを送信
-func (c *SlackClient) SendResultMessage(fetchMode string, processedArticles, newArticles, processedBooks, errors int, nextPage int, duration time.Duration, fetchStats *FetchStats) error {
+// apiUsage は DescribeUsages で整形した外部APIの当日の使用量（空の場合は表示しない）
+func (c *SlackClient) SendResultMessage(fetchMode string, processedArticles, newArticles, processedBooks, errors int, crawlProgress, apiUsage string, duration time.Duration, fetchStats *entity.FetchStats) error {
 	if !c.IsEnabled() {
 		return nil
 	}
@@ -84,8 +100,12 @@ func (c *SlackClient) SendResultMessage(fetchMode string, processedArticles, new
 		fetchMode, processedArticles, newArticles, processedBooks, errors, duration.Round(time.Second),
 	)
 
-	if nextPage > 0 {
-		resultText += fmt.Sprintf("\n• 次回開始ページ: %d", nextPage)
+	if crawlProgress != "" {
+		resultText += fmt.Sprintf("\n• 過去記事取得の進捗:\n%s", crawlProgress)
+	}
+
+	if apiUsage != "" {
+		resultText += fmt.Sprintf("\n• 外部APIの本日の使用量:\n%s", apiUsage)
 	}
 
 	attachments := []SlackAttachment{
@@ -97,17 +117,17 @@ func (c *SlackClient) SendResultMessage(fetchMode string, processedArticles, new
 		},
 	}
 
-	// Qiita取得統計を追加
+	// 記事取得統計を追加
 	if fetchStats != nil && len(fetchStats.QueryStats) > 0 {
 		statsText := ""
 		for _, qs := range fetchStats.QueryStats {
-			statsText += fmt.Sprintf("• %s: 取得%d件, 新規%d件, 重複%d件\n", qs.Query, qs.Fetched, qs.New, qs.Duplicates)
+			statsText += fmt.Sprintf("• [%s] %s: 取得%d件, 新規%d件, 重複%d件\n", qs.Source, qs.Query, qs.Fetched, qs.New, qs.Duplicates)
 		}
 		statsText += fmt.Sprintf("─────────────────\n*合計: %d件*", fetchStats.Total)
 
 		attachments = append(attachments, SlackAttachment{
 			Color: "#36a64f",
-			Title: "📊 Qiita記事取得詳細",
+			Title: "📊 記事取得詳細",
 			Text:  statsText,
 		})
 	}
@@ -164,7 +184,8 @@ func (c *SlackClient) SendAmazonBatchStartMessage(targetCount int) error {
 }
 
 // SendAmazonBatchResultMessage Amazon URL取得バッチ結果メッセージを送信
-func (c *SlackClient) SendAmazonBatchResultMessage(processedBooks, updatedBooks, notFoundBooks, errors int, duration time.Duration) error {
+// apiUsage は DescribeUsages で整形したPA-APIの当日の使用量（空の場合は表示しない）
+func (c *SlackClient) SendAmazonBatchResultMessage(processedBooks, updatedBooks, notFoundBooks, errors int, apiUsage string, duration time.Duration) error {
 	if !c.IsEnabled() {
 		return nil
 	}
@@ -184,6 +205,10 @@ func (c *SlackClient) SendAmazonBatchResultMessage(processedBooks, updatedBooks,
 		processedBooks, updatedBooks, notFoundBooks, errors, duration.Round(time.Second),
 	)
 
+	if apiUsage != "" {
+		resultText += fmt.Sprintf("\n• PA-APIの本日の使用量:\n%s", apiUsage)
+	}
+
 	attachments := []SlackAttachment{
 		{
 			Color:  color,
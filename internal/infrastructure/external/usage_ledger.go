@@ -0,0 +1,194 @@
+package external
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"teckbook-compass-backend/internal/domain/entity"
+	"teckbook-compass-backend/internal/domain/repository"
+)
+
+// ErrBudgetExceeded 1日の使用量の上限に達したためリクエストを送信しなかった（errors.Is で判定）
+var ErrBudgetExceeded = errors.New("daily api budget exceeded")
+
+// BudgetExceededError 上限に達したプロバイダとその上限
+type BudgetExceededError struct {
+	Provider string
+	Budget   int
+}
+
+// Error エラーメッセージ
+func (e *BudgetExceededError) Error() string {
+	return fmt.Sprintf("%s: daily api budget exceeded (%d calls)", e.Provider, e.Budget)
+}
+
+// Is ErrBudgetExceeded と一致する
+func (e *BudgetExceededError) Is(target error) bool {
+	return target == ErrBudgetExceeded
+}
+
+// jst 使用量を集計する日の区切り（日本時間）
+var jst = time.FixedZone("JST", 9*60*60)
+
+// usageDate 使用量を集計する日付（日本時間の日付を UTC の0時で表す）
+func usageDate(t time.Time) time.Time {
+	y, m, d := t.In(jst).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// ProviderUsage プロバイダの当日の使用量
+type ProviderUsage struct {
+	Provider string
+	Calls    int // 当日の呼び出し回数（他のバッチの分も含む）
+	Budget   int // 1日の上限（0は無制限）
+}
+
+// Remaining 当日の残りの呼び出し回数（上限がない場合は ok=false）
+func (u ProviderUsage) Remaining() (int, bool) {
+	if u.Budget <= 0 {
+		return 0, false
+	}
+	return max(u.Budget-u.Calls, 0), true
+}
+
+// UsageLedger 外部APIの使用量の台帳
+// プロバイダ・エンドポイントごとの呼び出し回数を api_usage に記録し、プロバイダごとの1日の上限を超える
+// リクエストは送信せずに ErrBudgetExceeded を返す。複数のゴルーチンから同時に使用できる。nil の場合は記録しない
+type UsageLedger struct {
+	repo    repository.APIUsageRepository
+	budgets map[string]int // プロバイダ → 1日の上限
+
+	mu    sync.Mutex
+	date  time.Time      // calls の集計日
+	calls map[string]int // プロバイダ → 当日の呼び出し回数
+}
+
+// NewUsageLedger UsageLedgerを生成
+func NewUsageLedger(repo repository.APIUsageRepository, budgets map[string]int) *UsageLedger {
+	return &UsageLedger{
+		repo:    repo,
+		budgets: budgets,
+		calls:   make(map[string]int),
+	}
+}
+
+// Load 当日の使用量を読み込む（他のバッチが使用した分も上限の判定に含める）
+func (l *UsageLedger) Load(ctx context.Context) error {
+	date := usageDate(time.Now())
+	usages, err := l.repo.GetAPIUsage(ctx, date)
+	if err != nil {
+		return fmt.Errorf("failed to load api usage: %w", err)
+	}
+
+	calls := make(map[string]int)
+	for _, usage := range usages {
+		calls[usage.Provider] += usage.Calls
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.date = date
+	l.calls = calls
+	return nil
+}
+
+// Reserve リクエスト1回分の使用量を記録する（上限に達している場合は記録せずに BudgetExceededError を返す）
+func (l *UsageLedger) Reserve(ctx context.Context, provider, endpoint string) error {
+	if l == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	date := usageDate(time.Now())
+	if !date.Equal(l.date) {
+		// 日付が変わったら集計し直す
+		l.date = date
+		l.calls = make(map[string]int)
+	}
+	if budget := l.budgets[provider]; budget > 0 && l.calls[provider] >= budget {
+		l.mu.Unlock()
+		return &BudgetExceededError{Provider: provider, Budget: budget}
+	}
+	l.calls[provider]++
+	l.mu.Unlock()
+
+	// キャンセルされても送信したリクエストは記録する
+	usage := &entity.APIUsage{UsageDate: date, Provider: provider, Endpoint: endpoint, Calls: 1}
+	if err := l.repo.AddAPIUsage(context.WithoutCancel(ctx), usage); err != nil {
+		log.Printf("Warning: APIの使用量の記録エラー (%s %s): %v\n", provider, endpoint, err)
+	}
+	return nil
+}
+
+// Usage プロバイダの当日の使用量
+func (l *UsageLedger) Usage(provider string) ProviderUsage {
+	if l == nil {
+		return ProviderUsage{Provider: provider}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return ProviderUsage{Provider: provider, Calls: l.calls[provider], Budget: l.budgets[provider]}
+}
+
+// Usages 当日に呼び出したか上限が設定されているプロバイダの使用量（プロバイダ名順）
+func (l *UsageLedger) Usages() []ProviderUsage {
+	if l == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	providers := make(map[string]bool)
+	for provider := range l.calls {
+		providers[provider] = true
+	}
+	for provider, budget := range l.budgets {
+		if budget > 0 {
+			providers[provider] = true
+		}
+	}
+	l.mu.Unlock()
+
+	usages := make([]ProviderUsage, 0, len(providers))
+	for provider := range providers {
+		usages = append(usages, l.Usage(provider))
+	}
+	sort.Slice(usages, func(i, j int) bool { return usages[i].Provider < usages[j].Provider })
+	return usages
+}
+
+// DescribeUsages 使用量を1プロバイダ1行で表す（Slack通知・ログ出力用）
+func DescribeUsages(usages []ProviderUsage) string {
+	lines := make([]string, 0, len(usages))
+	for _, usage := range usages {
+		if remaining, ok := usage.Remaining(); ok {
+			lines = append(lines, fmt.Sprintf("  %s: %d回（残り %d / 上限 %d）", usage.Provider, usage.Calls, remaining, usage.Budget))
+		} else {
+			lines = append(lines, fmt.Sprintf("  %s: %d回（上限なし）", usage.Provider, usage.Calls))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// endpointKey リクエストのコンテキストに設定するエンドポイント名のキー
+type endpointKey struct{}
+
+// withEndpoint 使用量を記録するエンドポイント名をコンテキストに設定
+func withEndpoint(ctx context.Context, endpoint string) context.Context {
+	return context.WithValue(ctx, endpointKey{}, endpoint)
+}
+
+// endpointOf リクエストのエンドポイント名（未設定の場合はURLのパス）
+func endpointOf(req *http.Request) string {
+	if endpoint, ok := req.Context().Value(endpointKey{}).(string); ok {
+		return endpoint
+	}
+	return req.URL.Path
+}
@@ -0,0 +1,324 @@
+package external
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"teckbook-compass-backend/internal/domain/entity"
+	"teckbook-compass-backend/internal/infrastructure/config"
+)
+
+// ZennClient Zenn APIクライアント
+// Zennの記事一覧APIは本文を含まないため、一覧で見つけた記事ごとに詳細APIで本文を取得する
+type ZennClient struct {
+	config     config.ZennConfig
+	httpClient *http.Client
+}
+
+// NewZennClient ZennClientを生成
+func NewZennClient(cfg config.ZennConfig, ledger *UsageLedger) *ZennClient {
+	return &ZennClient{
+		config: cfg,
+		httpClient: newHTTPClient(transportOptions{
+			provider: entity.SourceZenn,
+			timeout:  30 * time.Second,
+			limiter:  NewRateLimiter(cfg.RateLimit, 1), // 並行処理のワーカー間で共有する
+			breaker:  NewCircuitBreaker(entity.SourceZenn, cfg.Circuit),
+			ledger:   ledger,
+			policy:   defaultRetryPolicy,
+		}),
+	}
+}
+
+// Retries 実行したリトライ回数
+func (c *ZennClient) Retries() int {
+	return retryCount(c.httpClient)
+}
+
+// CircuitState サーキットブレーカーの状態
+func (c *ZennClient) CircuitState() CircuitState {
+	return circuitState(c.httpClient)
+}
+
+// ZennTopics 技術書関連のトピック（Zennはトピック単位で記事一覧を取得する、search_queries が未登録の場合のみ使用）
+var ZennTopics = []string{
+	"book",
+	"技術書",
+	"読書",
+	"書評",
+	"読書メモ",
+}
+
+// zennArticleIDPrefix Zennの記事IDの接頭辞
+// articles.id は取得元をまたいで一意である必要があるため、slug（QiitaのIDや他の記事と重なりうる）に付けて保存する
+const zennArticleIDPrefix = entity.SourceZenn + ":"
+
+// zennArticleID slugから記事IDを作る
+func zennArticleID(slug string) string {
+	return zennArticleIDPrefix + slug
+}
+
+// zennSlug 記事ID（接頭辞なしのslugも可）からslugを取り出す
+func zennSlug(articleID string) string {
+	return strings.TrimPrefix(articleID, zennArticleIDPrefix)
+}
+
+// zennArticle Zenn APIから取得した記事
+type zennArticle struct {
+	ID              int64       `json:"id"`
+	Slug            string      `json:"slug"`
+	Title           string      `json:"title"`
+	Path            string      `json:"path"`
+	LikedCount      int         `json:"liked_count"`
+	BookmarkedCount int         `json:"bookmarked_count"`
+	CommentsCount   int         `json:"comments_count"`
+	BodyHTML        string      `json:"body_html"`
+	Topics          []zennTopic `json:"topics"`
+	PublishedAt     time.Time   `json:"published_at"`
+	BodyUpdatedAt   *time.Time  `json:"body_updated_at"`
+}
+
+// zennTopic Zenn APIから取得したトピック
+type zennTopic struct {
+	Name string `json:"name"`
+}
+
+// zennArticleList 記事一覧APIのレスポンス
+type zennArticleList struct {
+	Articles []*zennArticle `json:"articles"`
+	NextPage *int           `json:"next_page"`
+}
+
+// zennArticleDetail 記事詳細APIのレスポンス
+type zennArticleDetail struct {
+	Article *zennArticle `json:"article"`
+}
+
+// toSourceArticle Zennの記事を取得元に依存しない記事に変換
+func (a *zennArticle) toSourceArticle() *entity.SourceArticle {
+	tags := make([]string, len(a.Topics))
+	for i, t := range a.Topics {
+		tags[i] = t.Name
+	}
+
+	updatedAt := a.PublishedAt
+	if a.BodyUpdatedAt != nil {
+		updatedAt = *a.BodyUpdatedAt
+	}
+
+	return &entity.SourceArticle{
+		Source:       entity.SourceZenn,
+		ID:           zennArticleID(a.Slug),
+		Title:        a.Title,
+		URL:          "https://zenn.dev" + a.Path,
+		RenderedBody: a.BodyHTML,
+		Likes:        a.LikedCount,
+		Stocks:       a.BookmarkedCount,
+		Comments:     a.CommentsCount,
+		Tags:         tags,
+		CreatedAt:    a.PublishedAt,
+		UpdatedAt:    updatedAt,
+	}
+}
+
+// Name 取得元の識別子
+func (c *ZennClient) Name() string {
+	return entity.SourceZenn
+}
+
+// DefaultQueries 既定の検索クエリ（トピック名）
+func (c *ZennClient) DefaultQueries() []string {
+	return ZennTopics
+}
+
+// listArticles トピックの記事一覧を新しい順に取得（本文は含まない）
+func (c *ZennClient) listArticles(ctx context.Context, topic string, page int) ([]*zennArticle, bool, error) {
+	params := url.Values{}
+	params.Set("topicname", topic)
+	params.Set("order", "latest")
+	params.Set("page", fmt.Sprintf("%d", page))
+
+	reqURL := fmt.Sprintf("%s/articles?%s", c.config.BaseURL, params.Encode())
+
+	var list zennArticleList
+	if err := c.getJSON(withEndpoint(ctx, "articles.list"), reqURL, &list); err != nil {
+		return nil, false, err
+	}
+
+	return list.Articles, list.NextPage != nil, nil
+}
+
+// GetArticle 記事ID（"zenn:<slug>"）またはslugで記事詳細（本文を含む）を取得
+func (c *ZennClient) GetArticle(ctx context.Context, articleID string) (*entity.SourceArticle, error) {
+	reqURL := fmt.Sprintf("%s/articles/%s", c.config.BaseURL, url.PathEscape(zennSlug(articleID)))
+
+	var detail zennArticleDetail
+	if err := c.getJSON(withEndpoint(ctx, "articles.get"), reqURL, &detail); err != nil {
+		return nil, err
+	}
+	if detail.Article == nil {
+		return nil, fmt.Errorf("%w: %s", ErrArticleNotFound, articleID)
+	}
+
+	return detail.Article.toSourceArticle(), nil
+}
+
+// FetchNewArticles 最新記事を取得（クエリごとの前回取得日時から overlap だけ遡った日時以降に公開された記事）
+// Zenn のトピック一覧は公開日時順のため、編集された記事は overlap の範囲内のもののみ取得される
+// 取得に成功したクエリは LastNewFetchedAt を更新する
+func (c *ZennClient) FetchNewArticles(ctx context.Context, states []*entity.CrawlState, overlap time.Duration, maxPagesPerQuery int) ([]*entity.SourceArticle, *entity.FetchStats, error) {
+	var allArticles []*entity.SourceArticle
+	seen := make(map[string]bool)
+	stats := &entity.FetchStats{QueryStats: make([]entity.QueryStats, 0, len(states))}
+
+	fmt.Printf("\n=== Zenn 最新記事取得モード ===\n")
+	fmt.Printf("トピック数: %d, 各トピック最大ページ数: %d\n\n", len(states), maxPagesPerQuery)
+
+	for _, state := range states {
+		topic := state.Query
+		since := state.NewFetchSince(overlap)
+		startedAt := time.Now()
+		queryStats := entity.QueryStats{Source: entity.SourceZenn, Query: topic}
+		failed := false
+
+	pages:
+		for page := 1; page <= maxPagesPerQuery; page++ {
+			listed, hasNext, err := c.listArticles(ctx, topic, page)
+			if err != nil {
+				fmt.Printf("  [%s] ページ%d エラー: %v\n", topic, page, err)
+				failed = true
+				break
+			}
+
+			for _, listedArticle := range listed {
+				// 新しい順に並んでいるため、基準日時より前の記事が出たら打ち切る
+				if since != nil && !listedArticle.PublishedAt.After(*since) {
+					break pages
+				}
+				c.collect(ctx, listedArticle.Slug, seen, &allArticles, &queryStats)
+			}
+
+			if !hasNext {
+				break
+			}
+		}
+
+		// 取得に失敗したトピックは次回も同じ基準日時から取得する
+		if !failed {
+			state.LastNewFetchedAt = &startedAt
+		}
+
+		stats.QueryStats = append(stats.QueryStats, queryStats)
+		fmt.Printf("  [%s] 取得: %d件, 新規: %d件, 累計: %d件\n",
+			topic, queryStats.Fetched, queryStats.New, len(allArticles))
+	}
+
+	stats.Total = len(allArticles)
+	fmt.Printf("\n=== Zenn 最新記事合計: %d件 ===\n\n", len(allArticles))
+	return allArticles, stats, nil
+}
+
+// FetchHistoricalArticles 過去記事を取得（トピックごとの進捗ページから各 pagesPerQuery ページまで）
+// Zenn のトピック一覧は期間指定ができないため、ページを最後まで辿ったトピックを完了とする
+func (c *ZennClient) FetchHistoricalArticles(ctx context.Context, states []*entity.CrawlState, pagesPerQuery int) ([]*entity.SourceArticle, *entity.FetchStats, error) {
+	var allArticles []*entity.SourceArticle
+	seen := make(map[string]bool)
+	stats := &entity.FetchStats{QueryStats: make([]entity.QueryStats, 0, len(states))}
+
+	fmt.Printf("\n=== Zenn 過去記事取得モード ===\n")
+	fmt.Printf("トピック数: %d, 各トピック取得ページ数: %d\n\n", len(states), pagesPerQuery)
+
+	for _, state := range states {
+		if state.IsCompleted() {
+			continue
+		}
+
+		queryStats := entity.QueryStats{Source: entity.SourceZenn, Query: state.Query}
+		startPage := state.Page
+
+		for requests := 0; requests < pagesPerQuery; requests++ {
+			listed, hasNext, err := c.listArticles(ctx, state.Query, state.Page)
+			if err != nil {
+				fmt.Printf("  [%s] ページ%d エラー: %v\n", state.Query, state.Page, err)
+				break
+			}
+
+			for _, listedArticle := range listed {
+				c.collect(ctx, listedArticle.Slug, seen, &allArticles, &queryStats)
+			}
+
+			if !hasNext {
+				state.Complete(time.Now())
+				break
+			}
+			state.Page++
+		}
+
+		stats.QueryStats = append(stats.QueryStats, queryStats)
+		fmt.Printf("  [%s] ページ%d-: 取得%d件, 新規%d件, 累計: %d件 (%s)\n",
+			state.Query, startPage, queryStats.Fetched, queryStats.New, len(allArticles), state)
+	}
+
+	stats.Total = len(allArticles)
+	fmt.Printf("\n=== Zenn 過去記事合計: %d件 ===\n\n", len(allArticles))
+	return allArticles, stats, nil
+}
+
+// collect 一覧で見つけた記事の詳細を取得して追加（重複はカウントのみ）
+func (c *ZennClient) collect(ctx context.Context, slug string, seen map[string]bool, articles *[]*entity.SourceArticle, stats *entity.QueryStats) {
+	stats.Fetched++
+	if seen[slug] {
+		stats.Duplicates++
+		return
+	}
+	seen[slug] = true
+
+	article, err := c.GetArticle(ctx, slug)
+	if err != nil {
+		fmt.Printf("  [%s] 記事詳細の取得エラー: %v\n", slug, err)
+		return
+	}
+	article.Query = stats.Query
+	*articles = append(*articles, article)
+	stats.New++
+}
+
+// getJSON GETリクエストを実行してJSONをデコード
+func (c *ZennClient) getJSON(ctx context.Context, reqURL string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	// 記事詳細の404は削除・非公開化として扱う
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("%w: %s", ErrArticleNotFound, reqURL)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("API returned non-200 status: %d, body: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	return nil
+}
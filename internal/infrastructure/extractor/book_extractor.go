@@ -23,12 +23,39 @@ type BookExtractor struct {
 
 // ExtractedBook 抽出された書籍情報
 type ExtractedBook struct {
-	ISBN       string // ISBN（10桁または13桁）
-	ASIN       string // Amazon ASIN
-	Title      string // 抽出されたタイトル
-	SourceType string // 抽出元の種類（"isbn", "asin", "amazon_url", "rakuten_url", "title"）
+	ISBN        string  // ISBN（10桁または13桁）
+	ASIN        string  // Amazon ASIN
+	Title       string  // 抽出されたタイトル
+	SourceType  string  // 抽出元の種類（"isbn13", "isbn10", "asin", "amazon_url", "title"）
+	Confidence  float64 // 抽出の確からしさ（0.0-1.0）
+	MatchedText string  // マッチした生のトークン
+	Snippet     string  // マッチ箇所の前後テキスト
+	Excerpt     string  // マッチ箇所を含む数文の抜粋（表示用に整形済み）
 }
 
+// 抽出元の種類
+const (
+	SourceTypeAmazonURL = "amazon_url"
+	SourceTypeISBN13    = "isbn13"
+	SourceTypeISBN10    = "isbn10"
+	SourceTypeASIN      = "asin"
+	SourceTypeTitle     = "title"
+)
+
+// 抽出元ごとの確からしさ
+// チェックディジットで検証できるISBNが最も高く、タイトル推測が最も低い
+const (
+	ConfidenceAmazonURLISBN = 0.95
+	ConfidenceAmazonURLASIN = 0.8
+	ConfidenceISBN13        = 0.95
+	ConfidenceISBN10        = 0.9
+	ConfidenceASIN          = 0.6
+	ConfidenceTitle         = 0.4
+)
+
+// snippetRadius スニペットとして前後に含める文字数
+const snippetRadius = 40
+
 // NewBookExtractor BookExtractorを生成
 func NewBookExtractor() *BookExtractor {
 	return &BookExtractor{
@@ -53,15 +80,23 @@ func (e *BookExtractor) ExtractFromText(text string) []ExtractedBook {
 	seen := make(map[string]bool)
 
 	// 1. Amazon URL からASIN/ISBNを抽出
-	amazonMatches := e.amazonURLPattern.FindAllStringSubmatch(text, -1)
-	for _, match := range amazonMatches {
-		if len(match) > 1 && !seen[match[1]] {
-			seen[match[1]] = true
-			book := ExtractedBook{SourceType: "amazon_url"}
-			if strings.HasPrefix(match[1], "B") {
-				book.ASIN = match[1]
+	amazonMatches := e.amazonURLPattern.FindAllStringSubmatchIndex(text, -1)
+	for _, loc := range amazonMatches {
+		id := text[loc[2]:loc[3]]
+		if !seen[id] {
+			seen[id] = true
+			book := ExtractedBook{
+				SourceType:  SourceTypeAmazonURL,
+				MatchedText: text[loc[0]:loc[1]],
+				Snippet:     snippetAround(text, loc[0], loc[1]),
+				Excerpt:     excerptAround(text, loc[0], loc[1]),
+			}
+			if strings.HasPrefix(id, "B") {
+				book.ASIN = id
+				book.Confidence = ConfidenceAmazonURLASIN
 			} else {
-				book.ISBN = match[1]
+				book.ISBN = id
+				book.Confidence = ConfidenceAmazonURLISBN
 			}
 			results = append(results, book)
 		}
@@ -77,21 +112,27 @@ func (e *BookExtractor) ExtractFromText(text string) []ExtractedBook {
 	}
 
 	// 3. ISBN-13を抽出
-	isbn13Matches := e.isbn13Pattern.FindAllString(text, -1)
-	for _, isbn := range isbn13Matches {
+	isbn13Matches := e.isbn13Pattern.FindAllStringIndex(text, -1)
+	for _, loc := range isbn13Matches {
+		isbn := text[loc[0]:loc[1]]
 		cleanISBN := cleanISBN(isbn)
 		if !seen[cleanISBN] && isValidISBN13(cleanISBN) {
 			seen[cleanISBN] = true
 			results = append(results, ExtractedBook{
-				ISBN:       cleanISBN,
-				SourceType: "isbn13",
+				ISBN:        cleanISBN,
+				SourceType:  SourceTypeISBN13,
+				Confidence:  ConfidenceISBN13,
+				MatchedText: isbn,
+				Snippet:     snippetAround(text, loc[0], loc[1]),
+				Excerpt:     excerptAround(text, loc[0], loc[1]),
 			})
 		}
 	}
 
 	// 4. ISBN-10を抽出
-	isbn10Matches := e.isbn10Pattern.FindAllString(text, -1)
-	for _, isbn := range isbn10Matches {
+	isbn10Matches := e.isbn10Pattern.FindAllStringIndex(text, -1)
+	for _, loc := range isbn10Matches {
+		isbn := text[loc[0]:loc[1]]
 		cleanISBN := cleanISBN(isbn)
 		if !seen[cleanISBN] && isValidISBN10(cleanISBN) {
 			// ISBN-10をISBN-13に変換
@@ -99,38 +140,49 @@ func (e *BookExtractor) ExtractFromText(text string) []ExtractedBook {
 			if !seen[isbn13] {
 				seen[isbn13] = true
 				results = append(results, ExtractedBook{
-					ISBN:       isbn13,
-					SourceType: "isbn10",
+					ISBN:        isbn13,
+					SourceType:  SourceTypeISBN10,
+					Confidence:  ConfidenceISBN10,
+					MatchedText: isbn,
+					Snippet:     snippetAround(text, loc[0], loc[1]),
+					Excerpt:     excerptAround(text, loc[0], loc[1]),
 				})
 			}
 		}
 	}
 
 	// 5. ASINを抽出
-	asinMatches := e.asinPattern.FindAllString(text, -1)
-	for _, asin := range asinMatches {
+	asinMatches := e.asinPattern.FindAllStringIndex(text, -1)
+	for _, loc := range asinMatches {
+		asin := text[loc[0]:loc[1]]
 		if !seen[asin] {
 			seen[asin] = true
 			results = append(results, ExtractedBook{
-				ASIN:       asin,
-				SourceType: "asin",
+				ASIN:        asin,
+				SourceType:  SourceTypeASIN,
+				Confidence:  ConfidenceASIN,
+				MatchedText: asin,
+				Snippet:     snippetAround(text, loc[0], loc[1]),
+				Excerpt:     excerptAround(text, loc[0], loc[1]),
 			})
 		}
 	}
 
 	// 6. 書籍タイトルを抽出（ISBN/ASINが見つからない場合の補助）
-	titleMatches := e.titlePattern.FindAllStringSubmatch(text, -1)
-	for _, match := range titleMatches {
-		if len(match) > 1 {
-			title := strings.TrimSpace(match[1])
-			// 技術書らしいタイトルかどうかをフィルタリング
-			if isTechBookTitle(title) && !seen["title_"+title] {
-				seen["title_"+title] = true
-				results = append(results, ExtractedBook{
-					Title:      title,
-					SourceType: "title",
-				})
-			}
+	titleMatches := e.titlePattern.FindAllStringSubmatchIndex(text, -1)
+	for _, loc := range titleMatches {
+		title := strings.TrimSpace(text[loc[2]:loc[3]])
+		// 技術書らしいタイトルかどうかをフィルタリング
+		if isTechBookTitle(title) && !seen["title_"+title] {
+			seen["title_"+title] = true
+			results = append(results, ExtractedBook{
+				Title:       title,
+				SourceType:  SourceTypeTitle,
+				Confidence:  ConfidenceTitle,
+				MatchedText: text[loc[0]:loc[1]],
+				Snippet:     snippetAround(text, loc[0], loc[1]),
+				Excerpt:     excerptAround(text, loc[0], loc[1]),
+			})
 		}
 	}
 
@@ -224,6 +276,22 @@ func convertISBN10to13(isbn10 string) string {
 	return prefix + string(rune('0'+checkDigit))
 }
 
+// snippetAround マッチ箇所の前後snippetRadius文字を切り出す（改行は空白に置換）
+func snippetAround(text string, start, end int) string {
+	before := []rune(text[:start])
+	after := []rune(text[end:])
+
+	if len(before) > snippetRadius {
+		before = before[len(before)-snippetRadius:]
+	}
+	if len(after) > snippetRadius {
+		after = after[:snippetRadius]
+	}
+
+	snippet := string(before) + text[start:end] + string(after)
+	return strings.Join(strings.Fields(snippet), " ")
+}
+
 // stripHTMLTags HTMLタグを除去
 func stripHTMLTags(html string) string {
 	// HTMLタグを除去する簡易的な正規表現
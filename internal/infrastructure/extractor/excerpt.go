@@ -0,0 +1,124 @@
+package extractor
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+// excerptMaxRunes 抜粋の最大文字数
+const excerptMaxRunes = 200
+
+// excerptContextSentences マッチした文の前後に含める文の数
+const excerptContextSentences = 1
+
+var (
+	// コードブロック（```〜```）
+	codeBlockPattern = regexp.MustCompile("(?s)```.*?```")
+	// インラインコード
+	inlineCodePattern = regexp.MustCompile("`[^`]*`")
+	// 画像（![alt](url)）
+	markdownImagePattern = regexp.MustCompile(`!\[[^\]]*\]\([^)]*\)`)
+	// リンク（[text](url)）はテキストのみ残す
+	markdownLinkPattern = regexp.MustCompile(`\[([^\]]*)\]\([^)]*\)`)
+	// HTMLタグ
+	htmlTagPattern = regexp.MustCompile(`<[^>]*>`)
+	// 裸のURL
+	bareURLPattern = regexp.MustCompile(`https?://\S+`)
+	// 行頭の見出し・引用・リスト記号
+	lineMarkerPattern = regexp.MustCompile(`(?m)^\s*(?:#{1,6}|>+|[-*+]|\d+\.)\s+`)
+	// 強調記号
+	emphasisPattern = regexp.MustCompile(`[*_~]{1,3}`)
+)
+
+// sentenceTerminators 文の区切りとみなす文字
+const sentenceTerminators = "。！？!?\n"
+
+// excerptAround マッチ箇所を含む文とその前後の文を抜粋し、表示用に整形する
+// start, end は text 上のバイト位置
+func excerptAround(text string, start, end int) string {
+	// コードブロック内の改行で文が分断されないよう、同じ長さの空白で塗りつぶす
+	text = codeBlockPattern.ReplaceAllStringFunc(text, func(block string) string {
+		return strings.Repeat(" ", len(block))
+	})
+
+	// マッチを含む文の範囲を求める
+	from := sentenceStart(text, start)
+	to := sentenceEnd(text, end)
+
+	// 前後の文を広げる
+	for i := 0; i < excerptContextSentences && from > 0; i++ {
+		from = sentenceStart(text, from-1)
+	}
+	for i := 0; i < excerptContextSentences && to < len(text); i++ {
+		to = sentenceEnd(text, to+1)
+	}
+
+	excerpt := sanitizeExcerpt(text[from:to])
+	return truncateRunes(excerpt, excerptMaxRunes)
+}
+
+// sentenceStart pos を含む文の開始位置を返す
+func sentenceStart(text string, pos int) int {
+	if pos > len(text) {
+		pos = len(text)
+	}
+	i := strings.LastIndexAny(text[:pos], sentenceTerminators)
+	if i < 0 {
+		return 0
+	}
+	// 区切り文字の直後から
+	for j := i + 1; j <= len(text); j++ {
+		if j == len(text) || isRuneStart(text[j]) {
+			return j
+		}
+	}
+	return len(text)
+}
+
+// sentenceEnd pos を含む文の終了位置（区切り文字を含む）を返す
+func sentenceEnd(text string, pos int) int {
+	if pos >= len(text) {
+		return len(text)
+	}
+	i := strings.IndexAny(text[pos:], sentenceTerminators)
+	if i < 0 {
+		return len(text)
+	}
+	end := pos + i + 1
+	for end < len(text) && !isRuneStart(text[end]) {
+		end++
+	}
+	return end
+}
+
+// isRuneStart UTF-8の先頭バイトかどうか
+func isRuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
+
+// sanitizeExcerpt Markdown/HTMLの記法を取り除いてプレーンテキストにする
+func sanitizeExcerpt(s string) string {
+	s = codeBlockPattern.ReplaceAllString(s, " ")
+	s = inlineCodePattern.ReplaceAllString(s, " ")
+	s = markdownImagePattern.ReplaceAllString(s, " ")
+	s = markdownLinkPattern.ReplaceAllString(s, "$1")
+	s = htmlTagPattern.ReplaceAllString(s, " ")
+	s = bareURLPattern.ReplaceAllString(s, " ")
+	s = lineMarkerPattern.ReplaceAllString(s, "")
+	s = emphasisPattern.ReplaceAllString(s, "")
+	s = strings.ReplaceAll(s, "`", "")
+	// 実体参照を戻してから再度タグを取り除く（&lt;script&gt; がタグとして残らないように）
+	s = html.UnescapeString(s)
+	s = htmlTagPattern.ReplaceAllString(s, " ")
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// truncateRunes 最大文字数を超える場合は末尾を省略する
+func truncateRunes(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max-1]) + "…"
+}
@@ -0,0 +1,40 @@
+package extractor
+
+import "testing"
+
+func TestSanitizeExcerpt(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{
+			name: "HTMLタグを取り除く",
+			in:   "<p>この本は<strong>おすすめ</strong>です</p>",
+			want: "この本は おすすめ です",
+		},
+		{
+			name: "実体参照で書かれたタグも取り除く",
+			in:   "&lt;script&gt;alert(1)&lt;/script&gt;この本はおすすめです",
+			want: "alert(1) この本はおすすめです",
+		},
+		{
+			name: "タグでない実体参照は文字に戻す",
+			in:   "a &lt; b &amp; c",
+			want: "a < b & c",
+		},
+		{
+			name: "Markdownのリンクはテキストのみ残す",
+			in:   "[リーダブルコード](https://example.com/book) を読みました",
+			want: "リーダブルコード を読みました",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sanitizeExcerpt(tt.in); got != tt.want {
+				t.Errorf("sanitizeExcerpt(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package sentiment
+
+import (
+	"sort"
+	"strings"
+
+	"teckbook-compass-backend/internal/domain/entity"
+)
+
+// Classifier 極性辞書ベースの日本語センチメント分類器
+// 形態素解析を使わず、語幹の部分一致と直後の否定表現で極性を判定する
+type Classifier struct {
+	// 極性語（長い語から順にマッチさせる）
+	terms []polarTerm
+	// 否定表現
+	negators []string
+	// 逆接表現（これ以降の節を重視する）
+	contrastives []string
+	// 判定のしきい値
+	threshold float64
+}
+
+// polarTerm 極性語
+type polarTerm struct {
+	text   string
+	weight float64 // 正: ポジティブ, 負: ネガティブ
+}
+
+// Result 分類結果
+type Result struct {
+	Label entity.Sentiment // 判定ラベル
+	Score float64          // 極性スコア（正: ポジティブ, 負: ネガティブ）
+}
+
+// negationWindow 極性語の直後で否定表現を探す文字数
+const negationWindow = 6
+
+// contrastiveBoost 逆接表現より後ろの極性語に掛ける重み
+const contrastiveBoost = 1.5
+
+// clauseBreaks 否定のスコープを打ち切る文字
+const clauseBreaks = "、。，．！？!?\n"
+
+// NewClassifier 組み込みの極性辞書でClassifierを生成
+func NewClassifier() *Classifier {
+	terms := make([]polarTerm, 0, len(positiveLexicon)+len(negativeLexicon))
+	for text, weight := range positiveLexicon {
+		terms = append(terms, polarTerm{text: text, weight: weight})
+	}
+	for text, weight := range negativeLexicon {
+		terms = append(terms, polarTerm{text: text, weight: -weight})
+	}
+	// 長い語を優先（「おすすめしない」を「おすすめ」より先にマッチさせる）
+	sort.Slice(terms, func(i, j int) bool {
+		li, lj := len([]rune(terms[i].text)), len([]rune(terms[j].text))
+		if li != lj {
+			return li > lj
+		}
+		return terms[i].text < terms[j].text
+	})
+
+	return &Classifier{
+		terms:        terms,
+		negators:     negators,
+		contrastives: contrastives,
+		threshold:    0.5,
+	}
+}
+
+// Classify テキストの極性を判定
+func (c *Classifier) Classify(text string) Result {
+	runes := []rune(strings.ToLower(text))
+	if len(runes) == 0 {
+		return Result{Label: entity.SentimentNeutral}
+	}
+
+	covered := make([]bool, len(runes))
+	contrastAt := c.lastContrastive(runes)
+	score := 0.0
+
+	for _, term := range c.terms {
+		termRunes := []rune(term.text)
+		for start := 0; start+len(termRunes) <= len(runes); start++ {
+			end := start + len(termRunes)
+			if !hasPrefixAt(runes, start, termRunes) || isCovered(covered, start, end) {
+				continue
+			}
+			for i := start; i < end; i++ {
+				covered[i] = true
+			}
+
+			weight := term.weight
+			if c.isNegated(runes, end) {
+				weight = -weight
+			}
+			if contrastAt >= 0 && start >= contrastAt {
+				weight *= contrastiveBoost
+			}
+			score += weight
+			start = end - 1
+		}
+	}
+
+	label := entity.SentimentNeutral
+	switch {
+	case score >= c.threshold:
+		label = entity.SentimentPositive
+	case score <= -c.threshold:
+		label = entity.SentimentNegative
+	}
+
+	return Result{Label: label, Score: score}
+}
+
+// isNegated 極性語の直後（同じ節内）に否定表現があるか
+func (c *Classifier) isNegated(runes []rune, from int) bool {
+	to := from + negationWindow
+	if to > len(runes) {
+		to = len(runes)
+	}
+	window := runes[from:to]
+	for i, r := range window {
+		if strings.ContainsRune(clauseBreaks, r) {
+			window = window[:i]
+			break
+		}
+	}
+
+	tail := string(window)
+	for _, neg := range c.negators {
+		if strings.Contains(tail, neg) {
+			return true
+		}
+	}
+	return false
+}
+
+// lastContrastive 最後の逆接表現の終了位置（見つからない場合は-1）
+func (c *Classifier) lastContrastive(runes []rune) int {
+	text := string(runes)
+	last := -1
+	for _, word := range c.contrastives {
+		if i := strings.LastIndex(text, word); i >= 0 {
+			pos := len([]rune(text[:i])) + len([]rune(word))
+			if pos > last {
+				last = pos
+			}
+		}
+	}
+	return last
+}
+
+// hasPrefixAt runes[start:] が prefix で始まるか
+func hasPrefixAt(runes []rune, start int, prefix []rune) bool {
+	for i, r := range prefix {
+		if runes[start+i] != r {
+			return false
+		}
+	}
+	return true
+}
+
+// isCovered 範囲内にすでにマッチ済みの文字があるか
+func isCovered(covered []bool, start, end int) bool {
+	for i := start; i < end; i++ {
+		if covered[i] {
+			return true
+		}
+	}
+	return false
+}
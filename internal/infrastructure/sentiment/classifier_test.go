@@ -0,0 +1,32 @@
+package sentiment
+
+import (
+	"testing"
+
+	"teckbook-compass-backend/internal/domain/entity"
+)
+
+func TestClassifierClassify(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want entity.Sentiment
+	}{
+		{name: "必ずは否定ではない", text: "わかりやすいので必ず読んでください", want: entity.SentimentPositive},
+		{name: "思わずは否定ではない", text: "面白くて思わず一気に読みました", want: entity.SentimentPositive},
+		{name: "まずは否定ではない", text: "丁寧なのでまず最初に読む一冊です", want: entity.SentimentPositive},
+		{name: "ないで否定する", text: "わかりやすくないです", want: entity.SentimentNegative},
+		{name: "ずにで否定する", text: "役に立たずに終わりました", want: entity.SentimentNegative},
+		{name: "せずで否定する", text: "満足せず読み終えました", want: entity.SentimentNegative},
+		{name: "極性語がない", text: "この本を読みました", want: entity.SentimentNeutral},
+	}
+
+	classifier := NewClassifier()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := classifier.Classify(tt.text); got.Label != tt.want {
+				t.Errorf("Classify(%q) = %s (score %.2f), want %s", tt.text, got.Label, got.Score, tt.want)
+			}
+		})
+	}
+}
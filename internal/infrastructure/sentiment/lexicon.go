@@ -0,0 +1,118 @@
+package sentiment
+
+// 技術書の紹介文向けの極性辞書
+// 語幹で登録し、活用形（「わかりやすい」「わかりやすく」など）にまとめてマッチさせる
+// 値は極性の強さ（1.0が標準）
+
+// positiveLexicon ポジティブ語
+var positiveLexicon = map[string]float64{
+	"おすすめ":   1.0,
+	"オススメ":   1.0,
+	"お勧め":    1.0,
+	"お薦め":    1.0,
+	"推奨":     0.8,
+	"必読":     1.5,
+	"名著":     1.5,
+	"良書":     1.5,
+	"神本":     1.5,
+	"バイブル":   1.2,
+	"良い":     0.8,
+	"良かっ":    0.8,
+	"良く":     0.6,
+	"よかっ":    0.8,
+	"素晴らし":   1.2,
+	"すばらし":   1.2,
+	"最高":     1.2,
+	"面白":     0.8,
+	"おもしろ":   0.8,
+	"わかりやす":  1.0,
+	"分かりやす":  1.0,
+	"読みやす":   0.8,
+	"丁寧":     0.8,
+	"網羅":     0.6,
+	"役に立":    1.0,
+	"役立":     1.0,
+	"参考にな":   0.8,
+	"勉強にな":   0.8,
+	"ためにな":   0.8,
+	"為にな":    0.8,
+	"理解が深ま":  1.0,
+	"助かっ":    0.8,
+	"感動":     1.0,
+	"目から鱗":   1.0,
+	"目からウロコ": 1.0,
+	"買ってよかっ": 1.2,
+	"読んでよかっ": 1.2,
+	"一読の価値":  1.2,
+	"手元に置":   0.8,
+	"何度も読み返": 1.0,
+	"実践的":    0.6,
+	"入門に最適":  1.2,
+	"最適":     0.8,
+	"満足":     0.8,
+	"好き":     0.6,
+	"刺さ":     0.6,
+}
+
+// negativeLexicon ネガティブ語（値は強さ。分類時に符号を反転する）
+var negativeLexicon = map[string]float64{
+	"悪い":     0.8,
+	"悪く":     0.6,
+	"微妙":     1.0,
+	"イマイチ":   1.0,
+	"いまいち":   1.0,
+	"残念":     1.0,
+	"古い":     0.6,
+	"古く":     0.6,
+	"時代遅れ":   1.2,
+	"情報が古":   1.0,
+	"わかりにく":  1.0,
+	"分かりにく":  1.0,
+	"読みにく":   1.0,
+	"難解":     0.6,
+	"誤植":     0.8,
+	"誤り":     0.8,
+	"間違い":    0.6,
+	"つまらな":   1.0,
+	"退屈":     1.0,
+	"物足りな":   0.8,
+	"期待外れ":   1.2,
+	"期待はずれ":  1.2,
+	"買わなくてい": 1.2,
+	"読まなくてい": 1.2,
+	"不要":     0.8,
+	"無駄":     1.0,
+	"ひど":     1.0,
+	"酷い":     1.0,
+	"がっかり":   1.2,
+	"後悔":     1.0,
+	"薄い":     0.6,
+	"浅い":     0.6,
+	"非推奨":    1.2,
+	"避けた方が":  1.2,
+	"避けたほうが": 1.2,
+}
+
+// negators 否定表現（極性語の直後に現れた場合に極性を反転）
+// 「ず」単体は「必ず」「思わず」「まず」にも含まれるため、動詞に続く形のみ登録する
+var negators = []string{
+	"ない",
+	"なかっ",
+	"ません",
+	"ずに",
+	"ずとも",
+	"せず",
+	"ぬ",
+}
+
+// contrastives 逆接表現（これ以降の内容を書き手の結論として重視）
+var contrastives = []string{
+	"しかし",
+	"ただし",
+	"ただ、",
+	"でも、",
+	"けど",
+	"けれど",
+	"が、",
+	"一方で",
+}
@@ -2,6 +2,7 @@ package handler
 
 import (
 	"strconv"
+	"teckbook-compass-backend/internal/domain/entity"
 	"teckbook-compass-backend/internal/usecase"
 	"teckbook-compass-backend/pkg/response"
 
@@ -30,6 +31,7 @@ func NewRankingHandler(rankingUsecase *usecase.RankingUsecase) *RankingHandler {
 // @Param limit query int false "取得件数（指定なしは全件）" minimum(1) maximum(100)
 // @Param offset query int false "オフセット" default(0) minimum(0)
 // @Param category query string false "カテゴリID"
+// @Param source query string false "記事の取得元 (qiita, zenn)"
 // @Success 200 {object} dto.RankingResponse
 // @Failure 400 {object} map[string]string
 // @Failure 500 {object} map[string]string
@@ -40,6 +42,7 @@ func (h *RankingHandler) GetRankings(c *gin.Context) {
 	limitStr := c.Query("limit") // デフォルト値なし（指定なしは全件）
 	offsetStr := c.DefaultQuery("offset", "0")
 	categoryID := c.Query("category")
+	source := c.Query("source")
 
 	// バリデーション: range
 	if rangeType != "all" && rangeType != "monthly" && rangeType != "yearly" {
@@ -47,6 +50,12 @@ func (h *RankingHandler) GetRankings(c *gin.Context) {
 		return
 	}
 
+	// バリデーション: source
+	if source != "" && source != entity.SourceQiita && source != entity.SourceZenn {
+		response.Error(c, 400, "source パラメータは qiita, zenn のいずれかである必要があります")
+		return
+	}
+
 	// バリデーション: limit（指定がなければ0=全件取得）
 	var limit int
 	var err error
@@ -68,7 +77,7 @@ func (h *RankingHandler) GetRankings(c *gin.Context) {
 	}
 
 	// ユースケースを実行
-	result, err := h.rankingUsecase.GetRankings(c.Request.Context(), rangeType, limit, offset, categoryID)
+	result, err := h.rankingUsecase.GetRankings(c.Request.Context(), rangeType, limit, offset, categoryID, source)
 	if err != nil {
 		response.Error(c, 500, "ランキングの取得に失敗しました")
 		return
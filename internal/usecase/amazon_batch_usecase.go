@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"teckbook-compass-backend/internal/domain/repository"
+	"teckbook-compass-backend/internal/infrastructure/config"
 	"teckbook-compass-backend/internal/infrastructure/external"
 )
 
@@ -16,6 +17,7 @@ type AmazonBatchUsecase struct {
 	repo         repository.BatchRepository
 	amazonClient *external.AmazonClient
 	slackClient  *external.SlackClient
+	processing   config.ProcessingConfig
 }
 
 // NewAmazonBatchUsecase AmazonBatchUsecaseを生成
@@ -23,23 +25,40 @@ func NewAmazonBatchUsecase(
 	repo repository.BatchRepository,
 	amazonClient *external.AmazonClient,
 	slackClient *external.SlackClient,
+	processing config.ProcessingConfig,
 ) *AmazonBatchUsecase {
 	return &AmazonBatchUsecase{
 		repo:         repo,
 		amazonClient: amazonClient,
 		slackClient:  slackClient,
+		processing:   processing,
 	}
 }
 
 // AmazonBatchResult Amazon URL取得バッチ結果
 type AmazonBatchResult struct {
-	ProcessedBooks int
-	UpdatedBooks   int
-	NotFoundBooks  int
-	Errors         int
-	ErrorMessage   string // API エラー時のメッセージ
-	StartTime      time.Time
-	EndTime        time.Time
+	ProcessedBooks  int
+	UpdatedBooks    int
+	NotFoundBooks   int
+	Errors          int
+	ErrorMessage    string                 // API エラー時のメッセージ
+	SkippedBooks    int                    // 1日の使用量の上限・期限に達したため次回に持ち越した書籍数
+	APIUsage        external.ProviderUsage // PA-APIの当日の使用量と残り（他のバッチの分も含む）
+	DeadlineReached bool                   // 期限に近づいたため途中で終了した
+	CircuitOpen     bool                   // PA-APIへのリクエストを遮断したため途中で終了した
+	StartTime       time.Time
+	EndTime         time.Time
+}
+
+// Counters 実行履歴（batch_runs）に記録する件数
+func (r *AmazonBatchResult) Counters() map[string]int {
+	return map[string]int{
+		"processed_books": r.ProcessedBooks,
+		"updated_books":   r.UpdatedBooks,
+		"not_found_books": r.NotFoundBooks,
+		"skipped_books":   r.SkippedBooks,
+		"errors":          r.Errors,
+	}
 }
 
 // Run Amazon URL取得バッチを実行
@@ -56,6 +75,15 @@ func (u *AmazonBatchUsecase) Run(ctx context.Context, limit int) (*AmazonBatchRe
 		return nil, fmt.Errorf("amazon api is disabled")
 	}
 
+	// 1冊につき1リクエストのため、PA-APIの当日の残りを超える分は次回に回す
+	limit = limitByBudget(limit, u.amazonClient.Usage())
+	if limit == 0 {
+		log.Println("PA-APIの本日の使用量が上限に達しているため処理しません")
+		result.APIUsage = u.amazonClient.Usage()
+		result.EndTime = time.Now()
+		return result, nil
+	}
+
 	// スコアが高い順にamazon_urlがない書籍を取得
 	books, err := u.repo.GetBooksWithoutAmazonURLByScore(ctx, limit)
 	if err != nil {
@@ -77,6 +105,17 @@ func (u *AmazonBatchUsecase) Run(ctx context.Context, limit int) (*AmazonBatchRe
 
 	// 各書籍に対してAmazon APIを呼び出し
 	for i, book := range books {
+		if deadlineReached(ctx, u.processing.DeadlineMargin) {
+			log.Printf("Warning: 期限に近づいたため残り%d件を次回に持ち越します", len(books)-i)
+			result.SkippedBooks = len(books) - i
+			result.DeadlineReached = true
+			break
+		}
+		if remaining, ok := u.amazonClient.Usage().Remaining(); ok && remaining == 0 {
+			log.Printf("Warning: PA-APIの本日の使用量が上限に達したため残り%d件を次回に持ち越します", len(books)-i)
+			result.SkippedBooks = len(books) - i
+			break
+		}
 		result.ProcessedBooks++
 
 		if i > 0 && i%10 == 0 {
@@ -92,22 +131,24 @@ func (u *AmazonBatchUsecase) Run(ctx context.Context, limit int) (*AmazonBatchRe
 		// タイトルで検索
 		amazonBook, err := u.amazonClient.SearchByTitle(ctx, book.Title)
 		if err != nil {
-			// API エラーの場合は即座に終了
+			// 遮断中は残りの書籍を次回に持ち越し、まとめて1回だけ通知して終了する
+			var circuitErr *external.CircuitOpenError
+			if errors.As(err, &circuitErr) {
+				result.ProcessedBooks--
+				result.SkippedBooks = len(books) - i
+				result.CircuitOpen = true
+				result.ErrorMessage = circuitErr.Error()
+				u.notifyCircuitOpen(ctx, circuitErr, result)
+				break
+			}
+
+			// API エラーは記録して続行（連続して失敗した場合はサーキットブレーカーが遮断する）
 			if errors.Is(err, external.ErrAmazonAPIError) {
-				log.Printf("Amazon API エラーが発生したため終了します: %v", err)
+				log.Printf("Warning: Amazon API エラー (ISBN: %s): %v", searchISBN, err)
 				result.ErrorMessage = err.Error()
 				result.Errors++
-				result.EndTime = time.Now()
-
-				// Slack通知: エラーメッセージ
-				if u.slackClient != nil && u.slackClient.IsEnabled() {
-					_ = u.slackClient.SendError("Amazon URL取得バッチエラー", fmt.Sprintf("APIエラーで終了: %v", err))
-				}
-
-				// エラーログを保存
 				u.logError(ctx, "amazon_api_error", err, searchISBN)
-
-				return result, fmt.Errorf("amazon api error: %w", err)
+				continue
 			}
 
 			// 商品が見つからない場合はスキップして続行
@@ -132,11 +173,9 @@ func (u *AmazonBatchUsecase) Run(ctx context.Context, limit int) (*AmazonBatchRe
 
 		result.UpdatedBooks++
 		log.Printf("Amazon URL更新成功: %s -> %s", book.Title, amazonBook.URL)
-
-		// レートリミット対策（Amazon PA-APIは1秒あたり1リクエストの制限がある）
-		time.Sleep(1100 * time.Millisecond)
 	}
 
+	result.APIUsage = u.amazonClient.Usage()
 	result.EndTime = time.Now()
 
 	log.Printf("Amazon URL取得バッチ完了: 処理=%d, 更新=%d, 未発見=%d, エラー=%d",
@@ -149,6 +188,7 @@ func (u *AmazonBatchUsecase) Run(ctx context.Context, limit int) (*AmazonBatchRe
 			result.UpdatedBooks,
 			result.NotFoundBooks,
 			result.Errors,
+			external.DescribeUsages([]external.ProviderUsage{result.APIUsage}),
 			result.EndTime.Sub(result.StartTime),
 		)
 	}
@@ -156,6 +196,28 @@ func (u *AmazonBatchUsecase) Run(ctx context.Context, limit int) (*AmazonBatchRe
 	return result, nil
 }
 
+// notifyCircuitOpen PA-APIへのリクエストを遮断したため持ち越した書籍数を、ログ・Slack・エラーログにまとめて1回だけ記録する
+func (u *AmazonBatchUsecase) notifyCircuitOpen(ctx context.Context, circuitErr *external.CircuitOpenError, result *AmazonBatchResult) {
+	message := fmt.Sprintf("PA-APIへのリクエストが連続して失敗したため%sまで遮断しました（残り%d件は次回に持ち越します）",
+		circuitErr.Until.Format("15:04:05"), result.SkippedBooks)
+	log.Printf("Warning: %s", message)
+
+	if u.slackClient != nil && u.slackClient.IsEnabled() {
+		_ = u.slackClient.SendError("Amazon URL取得バッチを途中で終了しました", message)
+	}
+	u.logError(ctx, "amazon_circuit_open", circuitErr, "")
+}
+
+// limitByBudget 1件につき1リクエストを使う処理の上限を、外部APIの当日の残りに収める（上限がない場合はそのまま）
+func limitByBudget(limit int, usage external.ProviderUsage) int {
+	remaining, ok := usage.Remaining()
+	if !ok || remaining >= limit {
+		return limit
+	}
+	log.Printf("%sの本日の残り（%d回）に合わせて処理上限を%d件から減らします", usage.Provider, remaining, limit)
+	return remaining
+}
+
 // logError エラーをログに記録
 func (u *AmazonBatchUsecase) logError(ctx context.Context, errorType string, err error, relatedID string) {
 	errLog := &repository.ErrorLog{
@@ -0,0 +1,158 @@
+package usecase
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"teckbook-compass-backend/internal/domain/entity"
+)
+
+// BookScoreMap バッチ処理中のスコアを管理
+// ワーカーは記事ごとのマップに加算し、処理が終わった記事の分だけをバッチ全体のマップに Merge する
+// （進捗を保存する時点で、処理済みの記事のスコアのみが集計されているようにするため）
+// スコアはこの実行で加算する分のみを保持し、book_scores_daily に加算する（保存済みのスコアは含めない）
+type BookScoreMap struct {
+	mu     sync.Mutex
+	scores map[string]*entity.BookScore
+}
+
+// NewBookScoreMap BookScoreMapを生成
+func NewBookScoreMap() *BookScoreMap {
+	return &BookScoreMap{scores: make(map[string]*entity.BookScore)}
+}
+
+// Add 書籍のスコアを加算する
+func (m *BookScoreMap) Add(bookID string, popularity float64, publishedAt time.Time, weight float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.score(bookID).AddScore(popularity, publishedAt, weight)
+}
+
+// Merge 記事ごとのマップに加算したスコアを加算する（other は nil でもよい）
+func (m *BookScoreMap) Merge(other *BookScoreMap) {
+	if other == nil {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for bookID, added := range other.Scores() {
+		score := m.score(bookID)
+		score.Score += added.Score
+		score.ArticleCount += added.ArticleCount
+		if added.LatestArticleDate.After(score.LatestArticleDate) {
+			score.LatestArticleDate = added.LatestArticleDate
+		}
+	}
+}
+
+// Restore 進捗に保存したスコアを読み込む
+func (m *BookScoreMap) Restore(scores []*entity.BookScore) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, score := range scores {
+		restored := *score
+		m.scores[score.BookID] = &restored
+	}
+}
+
+// score 書籍のスコア（初めての書籍は0から始める、ロックして呼び出す）
+func (m *BookScoreMap) score(bookID string) *entity.BookScore {
+	score, ok := m.scores[bookID]
+	if !ok {
+		score = &entity.BookScore{BookID: bookID}
+		m.scores[bookID] = score
+	}
+	return score
+}
+
+// Scores 書籍ID → スコア
+func (m *BookScoreMap) Scores() map[string]*entity.BookScore {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	scores := make(map[string]*entity.BookScore, len(m.scores))
+	for bookID, score := range m.scores {
+		scores[bookID] = score
+	}
+	return scores
+}
+
+// List 書籍IDの順のスコア（進捗の保存用にコピーして返す）
+func (m *BookScoreMap) List() []*entity.BookScore {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	scores := make([]*entity.BookScore, 0, len(m.scores))
+	for _, score := range m.scores {
+		copied := *score
+		scores = append(scores, &copied)
+	}
+	sort.Slice(scores, func(i, j int) bool { return scores[i].BookID < scores[j].BookID })
+	return scores
+}
+
+// articleOutcome ワーカーが処理した記事1件の結果
+type articleOutcome struct {
+	article     *entity.SourceArticle
+	isNew       bool
+	edited      bool          // 取得元で編集されていたため抽出をやり直したか
+	booksLinked int           // 紐付けた書籍数
+	recompute   []string      // スコアを再計算する書籍（編集された記事の編集前後の紐付け先）
+	scores      *BookScoreMap // 記事から加算したスコア（編集された記事は後で再計算するため nil）
+	err         error
+}
+
+// processArticles 記事を workers 個のワーカーで並行して処理し、結果を処理が終わった順に返す
+// 外部APIの呼び出し間隔は取得元・プロバイダごとのレート制限で調整する
+// ctx がキャンセルされた場合・期限までの残りが余裕以下になった場合は未着手の記事を処理せずに終了する
+func (u *BatchUsecase) processArticles(ctx context.Context, articles []*entity.SourceArticle, edited map[string]bool, workers int) <-chan articleOutcome {
+	jobs := make(chan *entity.SourceArticle)
+	outcomes := make(chan articleOutcome)
+
+	// 期限に近づいたら新しい記事を渡さない（処理中の記事は最後まで処理する）
+	intake, cancel := intakeContext(ctx, u.processing.DeadlineMargin)
+	go func() {
+		defer cancel()
+		defer close(jobs)
+		for _, article := range articles {
+			select {
+			case jobs <- article:
+			case <-intake.Done():
+				return
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for article := range jobs {
+				outcomes <- u.processArticleJob(ctx, article, edited[article.ID])
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(outcomes)
+	}()
+
+	return outcomes
+}
+
+// processArticleJob 記事1件を処理（編集された記事は紐付けを作り直す）
+func (u *BatchUsecase) processArticleJob(ctx context.Context, article *entity.SourceArticle, edited bool) articleOutcome {
+	outcome := articleOutcome{article: article, edited: edited}
+	if edited {
+		outcome.booksLinked, outcome.recompute, outcome.err = u.reprocessEditedArticle(ctx, article)
+	} else {
+		outcome.scores = NewBookScoreMap()
+		outcome.isNew, outcome.booksLinked, outcome.err = u.processArticle(ctx, article, outcome.scores)
+	}
+	return outcome
+}
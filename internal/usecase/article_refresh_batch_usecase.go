@@ -0,0 +1,250 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"teckbook-compass-backend/internal/domain/repository"
+	"teckbook-compass-backend/internal/infrastructure/config"
+	"teckbook-compass-backend/internal/infrastructure/external"
+)
+
+// ArticleRefreshBatchUsecase 保存済み記事のいいね数・ストック数などの再取得バッチ処理ユースケース
+type ArticleRefreshBatchUsecase struct {
+	repo       repository.BatchRepository
+	sources    map[string]repository.ArticleSource
+	recomputer *ScoreRecomputer
+	refresh    config.RefreshConfig
+	processing config.ProcessingConfig
+}
+
+// NewArticleRefreshBatchUsecase ArticleRefreshBatchUsecaseを生成
+// sources: 再取得に使う記事の取得元（含まれない取得元の記事は対象外）
+func NewArticleRefreshBatchUsecase(
+	repo repository.BatchRepository,
+	sources []repository.ArticleSource,
+	scoring config.ScoringConfig,
+	refresh config.RefreshConfig,
+	processing config.ProcessingConfig,
+) *ArticleRefreshBatchUsecase {
+	sourceMap := make(map[string]repository.ArticleSource, len(sources))
+	for _, source := range sources {
+		sourceMap[source.Name()] = source
+	}
+
+	return &ArticleRefreshBatchUsecase{
+		repo:       repo,
+		sources:    sourceMap,
+		recomputer: NewScoreRecomputer(repo, scoring),
+		refresh:    refresh,
+		processing: processing,
+	}
+}
+
+// ArticleTombstone 取得元で削除・非公開化された記事の検出イベント
+type ArticleTombstone struct {
+	ArticleID string
+	Source    string
+	Reason    string   // 検出時のエラー（ステータスコードを含む）
+	BookIDs   []string // スコアを再計算した書籍
+}
+
+// ArticleRefreshBatchResult 記事情報再取得バッチ結果
+type ArticleRefreshBatchResult struct {
+	ProcessedArticles int
+	UpdatedArticles   int                // いいね数・ストック数・コメント数のいずれかが変わった記事数
+	UnchangedArticles int                // 変更がなかった記事数
+	Tombstones        []ArticleTombstone // 取得元で削除・非公開化されていた記事
+	RecomputedBooks   int                // 削除された記事の分を除いてスコアを再計算した書籍数
+	SkippedArticles   int                // APIの予算を使い切った・期限に達したため次回に持ち越した記事数
+	DeadlineReached   bool               // 期限に近づいたため途中で終了した
+	APICalls          int                // 取得元APIへのリクエスト数
+	Errors            int
+	StartTime         time.Time
+	EndTime           time.Time
+}
+
+// Counters 実行履歴（batch_runs）に記録する件数
+func (r *ArticleRefreshBatchResult) Counters() map[string]int {
+	return map[string]int{
+		"processed_articles":  r.ProcessedArticles,
+		"updated_articles":    r.UpdatedArticles,
+		"unchanged_articles":  r.UnchangedArticles,
+		"tombstoned_articles": len(r.Tombstones),
+		"recomputed_books":    r.RecomputedBooks,
+		"skipped_articles":    r.SkippedArticles,
+		"api_calls":           r.APICalls,
+		"errors":              r.Errors,
+	}
+}
+
+// Run 記事情報再取得バッチを実行
+// limit: 処理する記事の最大数（更新日時の古い記事から順に処理し、APIの予算に達したら打ち切る）
+func (u *ArticleRefreshBatchUsecase) Run(ctx context.Context, limit int) (*ArticleRefreshBatchResult, error) {
+	result := &ArticleRefreshBatchResult{
+		StartTime: time.Now(),
+	}
+
+	log.Println("記事情報再取得バッチを開始します...")
+
+	sourceNames := make([]string, 0, len(u.sources))
+	for name := range u.sources {
+		sourceNames = append(sourceNames, name)
+	}
+
+	minAge := time.Duration(u.refresh.ArticleMinAgeDays) * 24 * time.Hour
+	articles, err := u.repo.GetArticlesForMetricsRefresh(ctx, sourceNames, minAge, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get articles for metrics refresh: %w", err)
+	}
+
+	log.Printf("処理対象の記事数: %d（前回更新から%d日以上経過、APIの予算: %dリクエスト）",
+		len(articles), u.refresh.ArticleMinAgeDays, u.refresh.ArticleAPIBudget)
+
+	for i, article := range articles {
+		if deadlineReached(ctx, u.processing.DeadlineMargin) {
+			result.SkippedArticles = len(articles) - i
+			result.DeadlineReached = true
+			log.Printf("Warning: 期限に近づいたため残り%d件を次回に持ち越します", result.SkippedArticles)
+			break
+		}
+		if result.APICalls >= u.refresh.ArticleAPIBudget || ctx.Err() != nil {
+			result.SkippedArticles = len(articles) - i
+			log.Printf("APIの予算またはタイムアウトに達したため残り%d件を次回に持ち越します", result.SkippedArticles)
+			break
+		}
+
+		result.ProcessedArticles++
+		u.refreshArticle(ctx, article, result)
+	}
+
+	// 削除・非公開化された記事の分を除いて書籍のスコアを再計算
+	u.recomputeTombstonedBooks(ctx, result)
+
+	result.EndTime = time.Now()
+	log.Printf("記事情報再取得バッチ完了: 処理時間 %v\n", result.EndTime.Sub(result.StartTime))
+
+	return result, nil
+}
+
+// refreshArticle 1件分の記事を取得元から再取得して更新
+func (u *ArticleRefreshBatchUsecase) refreshArticle(ctx context.Context, stored *repository.ArticleForMetricsRefresh, result *ArticleRefreshBatchResult) {
+	source := u.sources[stored.Source]
+
+	result.APICalls++
+	fetched, err := source.GetArticle(ctx, stored.ID)
+	if err != nil {
+		u.handleFetchError(ctx, stored, err, result)
+		return
+	}
+
+	// 記事詳細にストック数が含まれない場合は個別に取得（予算に余裕がある場合のみ）
+	if fetched.Stocks == 0 && result.APICalls < u.refresh.ArticleAPIBudget {
+		if counter, ok := source.(repository.StocksCountSource); ok {
+			result.APICalls++
+			stocks, err := counter.GetArticleStocksCount(ctx, stored.ID)
+			if err != nil {
+				u.handleFetchError(ctx, stored, err, result)
+				return
+			}
+			fetched.Stocks = stocks
+		}
+	}
+
+	article := fetched.ToArticle()
+	if err := u.repo.UpdateArticleMetrics(ctx, article); err != nil {
+		log.Printf("Warning: 記事情報の更新エラー (ID: %s): %v\n", stored.ID, err)
+		result.Errors++
+		return
+	}
+
+	if article.Likes == stored.Likes && article.Stocks == stored.Stocks && article.Comments == stored.Comments {
+		result.UnchangedArticles++
+		return
+	}
+
+	result.UpdatedArticles++
+	log.Printf("  更新: [%s] %s いいね %d → %d, ストック %d → %d, コメント %d → %d",
+		stored.Source, stored.ID,
+		stored.Likes, article.Likes, stored.Stocks, article.Stocks, stored.Comments, article.Comments)
+}
+
+// handleFetchError 再取得エラーを処理（削除・非公開化の場合は削除日時、それ以外の失敗は失敗日時を記録）
+func (u *ArticleRefreshBatchUsecase) handleFetchError(ctx context.Context, stored *repository.ArticleForMetricsRefresh, err error, result *ArticleRefreshBatchResult) {
+	if !errors.Is(err, external.ErrArticleNotFound) {
+		log.Printf("Warning: 記事の再取得エラー (ID: %s): %v\n", stored.ID, err)
+		result.Errors++
+		// 取得元のエラー（5xx・タイムアウト・遮断中）では更新日時が変わらないため、失敗日時を記録して次回以降に回す
+		// （予算の超過・レート制限・実行自体のタイムアウトは記事の問題ではないため記録しない）
+		if ctx.Err() == nil && !errors.Is(err, external.ErrBudgetExceeded) && !errors.Is(err, external.ErrRateLimited) {
+			if markErr := u.repo.MarkArticleRefreshFailed(ctx, stored.ID); markErr != nil {
+				log.Printf("Warning: 再取得の失敗日時の記録エラー (ID: %s): %v\n", stored.ID, markErr)
+			}
+		}
+		return
+	}
+
+	// 紐付いている書籍（スコアの再計算対象）を先に控えておく
+	bookIDs, linkErr := u.repo.GetLinkedBookIDs(ctx, stored.ID)
+	if linkErr != nil {
+		log.Printf("Warning: 紐付け書籍の取得エラー (ID: %s): %v\n", stored.ID, linkErr)
+		result.Errors++
+		return
+	}
+
+	if markErr := u.repo.MarkArticleDeleted(ctx, stored.ID); markErr != nil {
+		log.Printf("Warning: 削除日時の記録エラー (ID: %s): %v\n", stored.ID, markErr)
+		result.Errors++
+		return
+	}
+
+	tombstone := ArticleTombstone{
+		ArticleID: stored.ID,
+		Source:    stored.Source,
+		Reason:    err.Error(),
+		BookIDs:   bookIDs,
+	}
+	result.Tombstones = append(result.Tombstones, tombstone)
+	log.Printf("  削除・非公開化を検出: [%s] %s（紐付け書籍 %d 冊）", stored.Source, stored.ID, len(bookIDs))
+
+	// バッチのレポートとしてエラーログにも記録
+	tombstoneLog := &repository.ErrorLog{
+		BatchProcess: "refresh_articles",
+		ErrorType:    "article_tombstone",
+		Level:        "INFO",
+		APIName:      stored.Source,
+		RelatedID:    stored.ID,
+		Message:      fmt.Sprintf("%s (linked books: %s)", err.Error(), strings.Join(bookIDs, ",")),
+	}
+	if saveErr := u.repo.SaveErrorLog(ctx, tombstoneLog); saveErr != nil {
+		log.Printf("Warning: 削除イベントの記録エラー: %v\n", saveErr)
+	}
+}
+
+// recomputeTombstonedBooks 削除・非公開化された記事に紐付いていた書籍のスコアを再計算
+func (u *ArticleRefreshBatchUsecase) recomputeTombstonedBooks(ctx context.Context, result *ArticleRefreshBatchResult) {
+	seen := make(map[string]bool)
+	for _, tombstone := range result.Tombstones {
+		for _, bookID := range tombstone.BookIDs {
+			if seen[bookID] {
+				continue
+			}
+			seen[bookID] = true
+
+			if err := u.recomputer.RecomputeBook(ctx, bookID); err != nil {
+				log.Printf("Warning: スコア再計算エラー (BookID: %s): %v\n", bookID, err)
+				result.Errors++
+				continue
+			}
+			result.RecomputedBooks++
+		}
+	}
+
+	if result.RecomputedBooks > 0 {
+		log.Printf("削除された記事の分を除いて%d冊のスコアを再計算しました", result.RecomputedBooks)
+	}
+}
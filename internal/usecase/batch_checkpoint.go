@@ -0,0 +1,218 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"teckbook-compass-backend/internal/domain/entity"
+	"teckbook-compass-backend/internal/infrastructure/external"
+)
+
+// batchRun 記事取得バッチ1回分の処理対象と集計途中の状態
+type batchRun struct {
+	plans      []sourcePlan
+	articles   []*entity.SourceArticle // 処理する記事
+	edited     map[string]bool         // 取得元で編集されていたため紐付けを作り直す記事
+	queued     map[string]*entity.RetryQueueItem
+	queryRuns  *searchQueryRuns
+	bookScores *BookScoreMap   // 処理済みの記事から集計したスコア
+	recompute  map[string]bool // スコアを再計算する書籍
+
+	checkpoint *entity.BatchCheckpoint // 進捗（保存しない設定・保存に失敗した場合は nil）
+	processed  []string                // 進捗に保存していない処理済みの記事
+	resumedAt  time.Time               // 再開した進捗を作成した時刻（再開していない場合はゼロ値）
+}
+
+// fetchedAt 記事を取得した時刻（再開した場合は中断したバッチが取得した時刻）
+func (r *batchRun) fetchedAt() time.Time {
+	if !r.resumedAt.IsZero() {
+		return r.resumedAt
+	}
+	return time.Now()
+}
+
+// createCheckpoint 取得した記事を進捗として保存する（BATCH_CHECKPOINT_INTERVAL が0以下の場合は保存しない）
+// 保存できた場合は、取得した記事を再開時に取得し直さないためクエリの取得状態を先に進める
+func (u *BatchUsecase) createCheckpoint(ctx context.Context, run *batchRun, fetchMode string) {
+	if u.processing.CheckpointInterval <= 0 || len(run.articles) == 0 {
+		return
+	}
+
+	checkpoint := &entity.BatchCheckpoint{
+		Status:    entity.BatchCheckpointProcessing,
+		FetchMode: fetchMode,
+	}
+	for _, plan := range run.plans {
+		checkpoint.Sources = append(checkpoint.Sources, entity.CheckpointSource{
+			Source:   plan.source.Name(),
+			StatusID: plan.statusID,
+			Mode:     plan.mode,
+		})
+	}
+	for _, article := range run.articles {
+		_, retried := run.queued[article.ID]
+		checkpoint.Articles = append(checkpoint.Articles, &entity.CheckpointArticle{
+			Source:    article.Source,
+			ArticleID: article.ID,
+			Edited:    run.edited[article.ID],
+			Retried:   retried,
+		})
+	}
+
+	if err := u.repo.CreateBatchCheckpoint(ctx, checkpoint); err != nil {
+		log.Printf("Warning: バッチの進捗の保存エラー（中断した場合は最初からやり直します）: %v\n", err)
+		return
+	}
+	run.checkpoint = checkpoint
+	log.Printf("バッチの進捗を保存しました (ID: %d, 記事: %d件)\n", checkpoint.ID, len(checkpoint.Articles))
+
+	for _, plan := range run.plans {
+		for _, state := range plan.states {
+			if err := u.repo.SaveCrawlState(ctx, state); err != nil {
+				log.Printf("Warning: クエリの取得状態の保存エラー (%s): %v\n", state, err)
+			}
+		}
+	}
+}
+
+// resumeRun 中断したバッチの進捗から処理対象と集計途中のスコアを復元する
+// 未処理の記事は取得元から取得し直し、取得できない記事は延期して次回以降に処理する
+func (u *BatchUsecase) resumeRun(ctx context.Context, checkpoint *entity.BatchCheckpoint, result *BatchResult) *batchRun {
+	log.Printf("中断したバッチを再開します (ID: %d, %s開始, 未処理の記事: %d/%d)\n",
+		checkpoint.ID, checkpoint.CreatedAt.Format("2006-01-02 15:04:05"), checkpoint.Remaining(), len(checkpoint.Articles))
+	result.FetchMode = checkpoint.FetchMode + "（中断したバッチを再開）"
+
+	// Slack通知: 開始メッセージ
+	if u.slackClient != nil {
+		if err := u.slackClient.SendStartMessage(result.FetchMode); err != nil {
+			log.Printf("Warning: Slack通知エラー: %v\n", err)
+		}
+	}
+
+	run := &batchRun{
+		edited:     make(map[string]bool),
+		queued:     make(map[string]*entity.RetryQueueItem),
+		queryRuns:  newSearchQueryRuns(result.StartTime),
+		bookScores: NewBookScoreMap(),
+		recompute:  make(map[string]bool),
+		checkpoint: checkpoint,
+		resumedAt:  checkpoint.CreatedAt,
+	}
+	for _, s := range checkpoint.Sources {
+		if source := u.sourceByName(s.Source); source != nil {
+			run.plans = append(run.plans, sourcePlan{source: source, statusID: s.StatusID, mode: s.Mode})
+		}
+	}
+	run.bookScores.Restore(checkpoint.Scores)
+	for _, bookID := range checkpoint.RecomputeBookIDs {
+		run.recompute[bookID] = true
+	}
+
+	if checkpoint.Status == entity.BatchCheckpointScoresSaved {
+		log.Println("スコアは保存済みのため、スコアの再計算から再開します")
+		return run
+	}
+
+	for _, item := range checkpoint.Articles {
+		if item.Processed {
+			continue
+		}
+
+		source := u.sourceByName(item.Source)
+		if source == nil {
+			run.processed = append(run.processed, item.ArticleID)
+			continue
+		}
+		article, err := source.GetArticle(ctx, item.ArticleID)
+		if errors.Is(err, external.ErrArticleNotFound) {
+			run.processed = append(run.processed, item.ArticleID)
+			continue
+		}
+		if err != nil {
+			// 取得し直せない記事は延期して次回以降のバッチで処理する
+			log.Printf("Warning: 再開する記事の取得エラー (ID: %s): %v\n", item.ArticleID, err)
+			u.deferArticle(ctx, &entity.SourceArticle{Source: item.Source, ID: item.ArticleID}, "resume")
+			run.processed = append(run.processed, item.ArticleID)
+			continue
+		}
+
+		run.articles = append(run.articles, article)
+		if item.Edited {
+			run.edited[article.ID] = true
+		}
+		if item.Retried {
+			run.queued[article.ID] = &entity.RetryQueueItem{Source: item.Source, ArticleID: item.ArticleID}
+		}
+	}
+	result.ProcessedArticles = len(run.articles)
+	return run
+}
+
+// markProcessed 処理が終わった記事を記録し、BATCH_CHECKPOINT_INTERVAL 件ごとに進捗を保存する
+func (u *BatchUsecase) markProcessed(ctx context.Context, run *batchRun, articleID string) {
+	if run.checkpoint == nil {
+		return
+	}
+	run.processed = append(run.processed, articleID)
+	if len(run.processed) >= u.processing.CheckpointInterval {
+		u.saveCheckpoint(ctx, run)
+	}
+}
+
+// saveCheckpoint 処理済みの記事と集計途中のスコアを進捗に保存する
+// 保存に失敗した場合は次の保存でまとめて保存する（中断した場合は前回保存した時点から再開する）
+func (u *BatchUsecase) saveCheckpoint(ctx context.Context, run *batchRun) {
+	if run.checkpoint == nil || len(run.processed) == 0 {
+		return
+	}
+	if err := u.flushCheckpoint(ctx, run); err != nil {
+		log.Printf("Warning: バッチの進捗の保存エラー: %v\n", err)
+	}
+}
+
+// flushCheckpoint 処理済みの記事・集計途中のスコア・再計算する書籍を1つのトランザクションで保存する
+func (u *BatchUsecase) flushCheckpoint(ctx context.Context, run *batchRun) error {
+	run.checkpoint.Scores = run.bookScores.List()
+	run.checkpoint.RecomputeBookIDs = make([]string, 0, len(run.recompute))
+	for bookID := range run.recompute {
+		run.checkpoint.RecomputeBookIDs = append(run.checkpoint.RecomputeBookIDs, bookID)
+	}
+	sort.Strings(run.checkpoint.RecomputeBookIDs)
+
+	if err := u.repo.SaveBatchCheckpointProgress(ctx, run.checkpoint, run.processed); err != nil {
+		return err
+	}
+	run.processed = nil
+	return nil
+}
+
+// saveCheckpointScores 集計したスコアを book_scores_daily に加算し、進捗をスコア保存済みにする
+// 加算と進捗の更新は1つのトランザクションで行うため、再開時に同じスコアを加算し直さない
+func (u *BatchUsecase) saveCheckpointScores(ctx context.Context, run *batchRun) (int, error) {
+	if run.checkpoint.Status == entity.BatchCheckpointScoresSaved {
+		log.Println("スコアは中断したバッチで保存済みです")
+		return len(run.checkpoint.Scores), nil
+	}
+
+	if err := u.flushCheckpoint(ctx, run); err != nil {
+		return 0, fmt.Errorf("failed to save batch checkpoint: %w", err)
+	}
+	if err := u.repo.SaveBatchCheckpointScores(ctx, run.checkpoint); err != nil {
+		return 0, fmt.Errorf("failed to save book scores (will resume from checkpoint %d): %w", run.checkpoint.ID, err)
+	}
+	return len(run.checkpoint.Scores), nil
+}
+
+// completeCheckpoint 完了したバッチの進捗を削除する
+func (u *BatchUsecase) completeCheckpoint(ctx context.Context, run *batchRun) {
+	if run.checkpoint == nil {
+		return
+	}
+	if err := u.repo.DeleteBatchCheckpoint(ctx, run.checkpoint.ID); err != nil {
+		log.Printf("Warning: バッチの進捗の削除エラー: %v\n", err)
+	}
+}
@@ -4,42 +4,80 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"sort"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"teckbook-compass-backend/internal/domain/entity"
 	"teckbook-compass-backend/internal/domain/repository"
+	"teckbook-compass-backend/internal/infrastructure/config"
 	"teckbook-compass-backend/internal/infrastructure/external"
 	"teckbook-compass-backend/internal/infrastructure/extractor"
+	"teckbook-compass-backend/internal/infrastructure/sentiment"
 )
 
 // BatchUsecase バッチ処理ユースケース
 type BatchUsecase struct {
-	repo          repository.BatchRepository
-	qiitaClient   *external.QiitaClient
-	rakutenClient *external.RakutenClient
-	slackClient   *external.SlackClient
-	bookExtractor *extractor.BookExtractor
+	repo           repository.BatchRepository
+	sources        []repository.ArticleSource
+	commentSources map[string]repository.CommentSource
+	metadata       *BookMetadataChain
+	hatenaClient   *external.HatenaClient
+	slackClient    *external.SlackClient
+	bookExtractor  *extractor.BookExtractor
+	classifier     *sentiment.Classifier
+	scoring        config.ScoringConfig
+	comments       config.CommentsConfig
+	crawl          config.CrawlConfig
+	processing     config.ProcessingConfig
+	usage          *external.UsageLedger
+
+	newBooks atomic.Int64 // 新しく保存した書籍数（ワーカーから並行して加算する）
 }
 
 // NewBatchUsecase BatchUsecaseを生成
+// sources: 記事の取得元（先頭から順に取得する）
+// metadata: 書籍メタデータの取得に使うプロバイダのチェーン
+// hatenaClient: nilまたは無効の場合ははてなブックマーク数を取得しない
+// usage: 外部APIクライアントと共有する使用量の台帳（nilの場合は使用量を報告しない）
 func NewBatchUsecase(
 	repo repository.BatchRepository,
-	qiitaClient *external.QiitaClient,
-	rakutenClient *external.RakutenClient,
+	sources []repository.ArticleSource,
+	metadata *BookMetadataChain,
+	hatenaClient *external.HatenaClient,
 	slackClient *external.SlackClient,
+	scoring config.ScoringConfig,
+	comments config.CommentsConfig,
+	crawl config.CrawlConfig,
+	processing config.ProcessingConfig,
+	usage *external.UsageLedger,
 ) *BatchUsecase {
+	// コメント取得に対応した取得元を索引
+	commentSources := make(map[string]repository.CommentSource)
+	for _, source := range sources {
+		if commentSource, ok := source.(repository.CommentSource); ok {
+			commentSources[source.Name()] = commentSource
+		}
+	}
+
 	return &BatchUsecase{
-		repo:          repo,
-		qiitaClient:   qiitaClient,
-		rakutenClient: rakutenClient,
-		slackClient:   slackClient,
-		bookExtractor: extractor.NewBookExtractor(),
+		repo:           repo,
+		sources:        sources,
+		commentSources: commentSources,
+		metadata:       metadata,
+		hatenaClient:   hatenaClient,
+		slackClient:    slackClient,
+		bookExtractor:  extractor.NewBookExtractor(),
+		classifier:     sentiment.NewClassifier(),
+		scoring:        scoring,
+		comments:       comments,
+		crawl:          crawl,
+		processing:     processing,
+		usage:          usage,
 	}
 }
 
-// BookScoreMap バッチ処理中のスコアを管理
-type BookScoreMap map[string]*entity.BookScore
-
 // BatchResult バッチ処理結果
 type BatchResult struct {
 	FetchMode         string
@@ -47,13 +85,45 @@ type BatchResult struct {
 	NewArticles       int
 	ProcessedBooks    int
 	NewBooks          int
+	UpdatedArticles   int           // 取得元で編集されていたため書籍の抽出をやり直した記事数
+	UnchangedArticles int           // 保存済みで編集されていないため処理を省いた記事数
+	RetriedArticles   int           // 外部APIの障害で延期していたため処理し直した記事数
+	DeferredArticles  int           // 外部APIの遮断・使用量の上限のため次回以降に延期した記事数
+	Workers           int           // 記事を並行処理したワーカー数
+	ProcessDuration   time.Duration // 記事の処理（書籍の抽出・紐付け）にかかった時間
+	Throughput        float64       // 記事の処理のスループット（件/秒）
 	Errors            int
-	NextPage          int
-	FetchStats        *external.FetchStats
+	CrawlStates       []*entity.CrawlState // 過去記事取得の進捗（過去記事取得モードで取得したクエリのみ）
+	FetchStats        *entity.FetchStats
+	Retries           map[string]int           // 外部APIクライアントごとのリトライ回数（リトライのなかったクライアントは含めない）
+	APIUsage          []external.ProviderUsage // 外部APIの当日の使用量と残り（他のバッチの分も含む）
+	DeadlineReached   bool                     // 期限に近づいたため途中で終了した（残りは次回の実行で処理する）
+	RemainingArticles int                      // 期限に近づいたため処理しなかった記事数
+	QueryRuns         []*entity.SearchQueryRun // 検索クエリごとの取得実績（中断したバッチを再開した場合は含まれない）
 	StartTime         time.Time
 	EndTime           time.Time
 }
 
+// Counters 実行履歴（batch_runs）に記録する件数
+func (r *BatchResult) Counters() map[string]int {
+	counters := map[string]int{
+		"fetched_articles":   r.ProcessedArticles,
+		"new_articles":       r.NewArticles,
+		"updated_articles":   r.UpdatedArticles,
+		"unchanged_articles": r.UnchangedArticles,
+		"retried_articles":   r.RetriedArticles,
+		"deferred_articles":  r.DeferredArticles,
+		"remaining_articles": r.RemainingArticles,
+		"processed_books":    r.ProcessedBooks,
+		"new_books":          r.NewBooks,
+		"errors":             r.Errors,
+	}
+	for client, count := range r.Retries {
+		counters["retries."+client] = count
+	}
+	return counters
+}
+
 // FetchModeOption 取得モードオプション（コマンドラインから指定）
 type FetchModeOption int
 
@@ -62,8 +132,18 @@ const (
 	FetchModeOptionHistorical                        // 過去記事取得モード
 )
 
+// sourcePlan 取得元ごとの取得計画
+type sourcePlan struct {
+	source   repository.ArticleSource
+	statusID string
+	status   *entity.BatchStatus
+	mode     entity.FetchMode
+	states   []*entity.CrawlState // 今回取得するクエリの取得状態（Step 1で決定）
+}
+
 // Run バッチ処理を実行
 // fetchModeOption: nilの場合は自動判定、指定された場合は強制的にそのモードで実行
+// 中断したバッチの進捗が残っている場合は、記事を取得し直さずに未処理の記事から再開する
 func (u *BatchUsecase) Run(ctx context.Context, fetchModeOption *FetchModeOption) (*BatchResult, error) {
 	result := &BatchResult{
 		StartTime: time.Now(),
@@ -71,109 +151,163 @@ func (u *BatchUsecase) Run(ctx context.Context, fetchModeOption *FetchModeOption
 
 	log.Println("バッチ処理を開始します...")
 
-	// バッチ状態を取得
-	batchStatus, err := u.repo.GetBatchStatus(ctx, entity.BatchStatusIDQiitaFetch)
+	checkpoint, err := u.repo.GetBatchCheckpoint(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get batch status: %w", err)
+		return nil, fmt.Errorf("failed to get batch checkpoint: %w", err)
 	}
 
-	// 取得モードを判定
-	var fetchMode entity.FetchMode
-	if fetchModeOption != nil {
-		// コマンドラインから指定された場合は強制
-		if *fetchModeOption == FetchModeOptionNew {
-			fetchMode = entity.FetchModeNew
-			log.Println("取得モード: 最新記事取得（強制指定）")
-		} else {
-			fetchMode = entity.FetchModeHistorical
-			log.Println("取得モード: 過去記事取得（強制指定）")
-		}
+	var run *batchRun
+	if checkpoint != nil {
+		run = u.resumeRun(ctx, checkpoint, result)
 	} else {
-		// 自動判定
-		fetchMode = batchStatus.GetFetchMode()
-		log.Printf("取得モード: %s（自動判定）\n", fetchMode.String())
-	}
-	result.FetchMode = fetchMode.String()
-
-	// Slack通知: 開始メッセージ
-	if u.slackClient != nil {
-		if err := u.slackClient.SendStartMessage(result.FetchMode); err != nil {
-			log.Printf("Warning: Slack通知エラー: %v\n", err)
+		run, err = u.startRun(ctx, fetchModeOption, result)
+		if err != nil {
+			return nil, err
 		}
 	}
+	articles := run.articles
 
-	var articles []*entity.QiitaAPIArticle
-	var fetchStats *external.FetchStats
+	// はてなブックマーク数を一括取得
+	if u.hatenaEnabled() {
+		fetched := u.attachHatenaBookmarks(ctx, articles)
+		log.Printf("はてなブックマーク数を取得しました (%d/%d件)\n", fetched, len(articles))
+	}
 
-	// 1. Qiita APIから記事を取得
-	log.Println("Step 1: Qiita APIから記事を取得中...")
-	u.slackLog("Step 1: Qiita APIから記事を取得中...")
+	// 2-4. 各記事を処理（ワーカーで並行処理）
+	result.Workers = u.workers()
+	log.Printf("Step 2-4: 各記事から技術書を抽出中...（ワーカー数: %d）\n", result.Workers)
+	u.slackLog("Step 2-4: 各記事から技術書を抽出中...")
 
-	if fetchMode == entity.FetchModeNew {
-		// 最新記事取得モード
-		articles, fetchStats, err = u.qiitaClient.FetchNewArticles(ctx, external.SearchQueries, batchStatus.LastFetchedAt, 8)
-		if err != nil {
-			return nil, fmt.Errorf("failed to fetch new articles: %w", err)
+	processStart := time.Now()
+	processed := 0
+	done := make(map[string]bool, len(articles))
+	// 遮断中・使用量の上限に達した外部APIのために延期した記事数（クライアントごと、まとめて1回だけ通知する）
+	deferredBy := make(map[string]int)
+	for outcome := range u.processArticles(ctx, articles, run.edited, result.Workers) {
+		if ctx.Err() != nil {
+			// 中断された記事は途中までしか処理していない可能性があるため、処理済みにせず
+			// 再開時（進捗を保存しない設定の場合は延期した記事として次回以降）に処理し直す
+			continue
 		}
-	} else {
-		// 過去記事取得モード
-		var nextPage int
-		articles, nextPage, fetchStats, err = u.qiitaClient.FetchHistoricalArticles(ctx, external.SearchQueries, batchStatus.NextPage, 1)
-		if err != nil {
-			return nil, fmt.Errorf("failed to fetch historical articles: %w", err)
+		processed++
+		done[outcome.article.ID] = true
+		if processed%50 == 0 {
+			log.Printf("進捗: %d/%d 記事を処理済み\n", processed, len(articles))
+			u.slackLogf("進捗: %d/%d 記事を処理済み", processed, len(articles))
 		}
-		result.NextPage = nextPage
-	}
-
-	log.Printf("取得した記事数: %d\n", len(articles))
-	u.slackLogf("取得した記事数: %d", len(articles))
-	result.ProcessedArticles = len(articles)
-	result.FetchStats = fetchStats
-
-	// 書籍スコアを管理するマップ
-	bookScores := make(BookScoreMap)
-
-	// 2-4. 各記事を処理
-	log.Println("Step 2-4: 各記事から技術書を抽出中...")
-	u.slackLog("Step 2-4: 各記事から技術書を抽出中...")
 
-	for i, article := range articles {
-		if i > 0 && i%50 == 0 {
-			log.Printf("進捗: %d/%d 記事を処理済み\n", i, len(articles))
-			u.slackLogf("進捗: %d/%d 記事を処理済み", i, len(articles))
+		reason, deferred := deferralReason(outcome.err)
+		// 処理が終わった記事のスコアのみを集計する（抽出に失敗した記事も紐付けた分は加算する）
+		// 延期した記事は再処理時に紐付けを作り直してスコアを再計算するため、途中まで紐付けた分は加算しない
+		if !deferred {
+			run.bookScores.Merge(outcome.scores)
+		}
+		// 編集された記事は紐付けを作り直したため、紐付いていた書籍のスコアを後で再計算する
+		// （抽出に失敗した場合も既存の紐付けは削除済みのため再計算する）
+		for _, bookID := range outcome.recompute {
+			run.recompute[bookID] = true
 		}
 
-		isNew, err := u.processArticle(ctx, article, bookScores)
-		if err != nil {
-			log.Printf("Warning: 記事処理エラー (ID: %s): %v\n", article.ID, err)
-			u.logError(ctx, "article_processing", err, article.ID)
+		if deferred {
+			// 遮断中・上限に達した外部APIで調べられなかった書籍があるため、記事ごと次回以降に処理し直す（エラーとしては記録しない）
+			u.deferArticle(ctx, outcome.article, reason)
+			deferredBy[reason]++
+			result.DeferredArticles++
+			outcome.err = nil
+		} else if item, ok := run.queued[outcome.article.ID]; ok {
+			u.deleteRetry(ctx, item)
+		}
+		u.markProcessed(ctx, run, outcome.article.ID)
+		if outcome.err != nil {
+			log.Printf("Warning: 記事処理エラー (ID: %s): %v\n", outcome.article.ID, outcome.err)
+			u.logError(ctx, "article_processing", outcome.err, outcome.article.ID)
 			result.Errors++
 			continue
 		}
+		run.queryRuns.recordArticle(outcome.article, outcome.booksLinked)
 
-		if isNew {
+		if _, ok := run.queued[outcome.article.ID]; ok {
+			if !deferred {
+				result.RetriedArticles++
+			}
+		} else if outcome.edited {
+			result.UpdatedArticles++
+		}
+		if outcome.isNew {
 			result.NewArticles++
 		}
+	}
+	if processed < len(articles) {
+		log.Printf("Warning: 中断されたため%d件の記事を処理していません\n", len(articles)-processed)
+	}
+	u.notifyDeferred(deferredBy)
+
+	if ctx.Err() != nil {
+		// 中断後の書き込みはキャンセルされたコンテキストでは失敗するため、キャンセルを引き継がないコンテキストで行う
+		saveCtx := context.WithoutCancel(ctx)
+		if run.checkpoint != nil {
+			// 処理済みの記事と集計途中のスコアを保存して終了し、次回のバッチで続きから再開する
+			u.saveCheckpoint(saveCtx, run)
+			return nil, fmt.Errorf("batch interrupted after %d/%d articles (will resume from checkpoint %d): %w",
+				processed, len(articles), run.checkpoint.ID, ctx.Err())
+		}
+
+		// 進捗を保存しない設定の場合は、未処理の記事を延期し、処理済みの記事のスコアを保存して終了する
+		for _, article := range articles {
+			if !done[article.ID] {
+				u.deferArticle(saveCtx, article, "interrupted")
+			}
+		}
+		u.saveBookScores(saveCtx, run, result)
+		u.recomputeBooks(saveCtx, run, result)
+		return nil, fmt.Errorf("batch interrupted after %d/%d articles (unprocessed articles deferred): %w",
+			processed, len(articles), ctx.Err())
+	}
+
+	if processed < len(articles) && deadlineReached(ctx, u.processing.DeadlineMargin) {
+		result.DeadlineReached = true
+		result.RemainingArticles = len(articles) - processed
+		log.Printf("Warning: 期限に近づいたため残り%d件の記事を次回に持ち越します\n", result.RemainingArticles)
+		u.slackLogf("期限に近づいたため残り%d件の記事を次回に持ち越します", result.RemainingArticles)
+
+		if run.checkpoint != nil {
+			// 処理済みの記事と集計途中のスコアを保存して終了し、次回の実行で続きから再開する
+			u.saveCheckpoint(ctx, run)
+			return u.finishPartialRun(result, processed), nil
+		}
+		// 進捗を保存しない設定の場合は、残りの記事を延期してスコアとバッチ状態を保存する
+		for _, article := range articles {
+			if !done[article.ID] {
+				u.deferArticle(ctx, article, "deadline")
+			}
+		}
+	}
 
-		// レートリミット対策
-		time.Sleep(500 * time.Millisecond)
+	result.ProcessDuration = time.Since(processStart)
+	if seconds := result.ProcessDuration.Seconds(); seconds > 0 {
+		result.Throughput = float64(processed) / seconds
 	}
+	log.Printf("記事処理: %d件 / %v（%.2f件/秒）\n", processed, result.ProcessDuration.Round(time.Second), result.Throughput)
+	u.slackLogf("記事処理: %d件 / %v（%.2f件/秒、ワーカー数: %d）", processed, result.ProcessDuration.Round(time.Second), result.Throughput, result.Workers)
 
 	// 5. スコアを保存
 	log.Println("Step 5: 書籍スコアを保存中...")
 	u.slackLog("Step 5: 書籍スコアを保存中...")
 
-	for bookID, score := range bookScores {
-		// 日付は紐づく記事の最新投稿日（日付のみ）
-		scoreDate := score.LatestArticleDate.Truncate(24 * time.Hour)
-		if err := u.repo.SaveBookScoreDaily(ctx, bookID, scoreDate, score.Score, score.ArticleCount); err != nil {
-			log.Printf("Warning: スコア保存エラー (BookID: %s): %v\n", bookID, err)
-			result.Errors++
-			continue
+	if run.checkpoint != nil {
+		// 進捗とスコアの加算を1つのトランザクションで保存し、再開時に加算し直さない
+		saved, err := u.saveCheckpointScores(ctx, run)
+		if err != nil {
+			return nil, err
 		}
-		result.ProcessedBooks++
+		result.ProcessedBooks = saved
+	} else {
+		u.saveBookScores(ctx, run, result)
 	}
 
+	// 編集された記事の紐付けを作り直した書籍は、保存済みの紐付けからスコアを再計算する
+	u.recomputeBooks(ctx, run, result)
+
 	// 6. Amazon API処理（後で追加するためスキップ）
 	log.Println("Step 6: Amazon API処理はスキップ（後で追加）")
 
@@ -184,20 +318,44 @@ func (u *BatchUsecase) Run(ctx context.Context, fetchModeOption *FetchModeOption
 	log.Println("Step 8: バッチ状態を更新中...")
 	u.slackLog("Step 8: バッチ状態を更新中...")
 
-	if fetchMode == entity.FetchModeNew {
-		// 最新記事取得モードの場合、last_fetched_atを更新
-		if err := u.repo.UpdateBatchStatusForNewFetch(ctx, entity.BatchStatusIDQiitaFetch, time.Now()); err != nil {
-			log.Printf("Warning: バッチ状態更新エラー: %v\n", err)
+	for _, plan := range run.plans {
+		for _, state := range plan.states {
+			if err := u.repo.SaveCrawlState(ctx, state); err != nil {
+				log.Printf("Warning: クエリの取得状態の保存エラー (%s): %v\n", state, err)
+			}
 		}
-		log.Println("最新記事取得完了 - 次回まで過去記事取得モードに移行")
-	} else {
-		// 過去記事取得モードの場合、next_pageを更新
-		if err := u.repo.UpdateBatchStatusForHistoricalFetch(ctx, entity.BatchStatusIDQiitaFetch, result.NextPage); err != nil {
-			log.Printf("Warning: バッチ状態更新エラー: %v\n", err)
+	}
+
+	for _, queryRun := range run.queryRuns.runs {
+		if err := u.repo.SaveSearchQueryRun(ctx, queryRun); err != nil {
+			log.Printf("Warning: 検索クエリの実行結果の保存エラー ([%s] %s): %v\n", queryRun.Source, queryRun.Query, err)
 		}
-		log.Printf("過去記事取得完了 - 次回開始ページ: %d\n", result.NextPage)
 	}
+	result.QueryRuns = run.queryRuns.runs
 
+	for _, plan := range run.plans {
+		name := plan.source.Name()
+		if plan.mode == entity.FetchModeNew {
+			// 最新記事取得モードの場合、last_fetched_atを更新（再開した場合は中断したバッチが取得した時刻）
+			if err := u.repo.UpdateBatchStatusForNewFetch(ctx, plan.statusID, run.fetchedAt()); err != nil {
+				log.Printf("Warning: バッチ状態更新エラー (%s): %v\n", name, err)
+			}
+			log.Printf("[%s] 最新記事取得完了 - 次回まで過去記事取得モードに移行\n", name)
+		} else {
+			// 過去記事取得モードの場合、実行日時を更新（進捗は crawl_states に保存済み）
+			if err := u.repo.UpdateBatchStatusForHistoricalFetch(ctx, plan.statusID); err != nil {
+				log.Printf("Warning: バッチ状態更新エラー (%s): %v\n", name, err)
+			}
+			log.Printf("[%s] 過去記事取得完了\n", name)
+		}
+	}
+
+	// 完了したため進捗を削除（次回は新しく取得する）
+	u.completeCheckpoint(ctx, run)
+
+	result.NewBooks = int(u.newBooks.Load())
+	result.Retries = u.retryCounts()
+	result.APIUsage = u.usage.Usages()
 	result.EndTime = time.Now()
 	log.Printf("バッチ処理完了: 処理時間 %v\n", result.EndTime.Sub(result.StartTime))
 
@@ -209,7 +367,8 @@ func (u *BatchUsecase) Run(ctx context.Context, fetchModeOption *FetchModeOption
 			result.NewArticles,
 			result.ProcessedBooks,
 			result.Errors,
-			result.NextPage,
+			DescribeCrawlProgress(result.CrawlStates),
+			external.DescribeUsages(result.APIUsage),
 			result.EndTime.Sub(result.StartTime),
 			result.FetchStats,
 		); err != nil {
@@ -220,6 +379,394 @@ func (u *BatchUsecase) Run(ctx context.Context, fetchModeOption *FetchModeOption
 	return result, nil
 }
 
+// saveBookScores 集計したスコアを book_scores_daily に加算（進捗を保存しない設定の場合）
+func (u *BatchUsecase) saveBookScores(ctx context.Context, run *batchRun, result *BatchResult) {
+	for bookID, score := range run.bookScores.Scores() {
+		// 日付は紐づく記事の最新投稿日（日付のみ）
+		scoreDate := score.LatestArticleDate.Truncate(24 * time.Hour)
+		if err := u.repo.SaveBookScoreDaily(ctx, bookID, scoreDate, score.Score, score.ArticleCount); err != nil {
+			log.Printf("Warning: スコア保存エラー (BookID: %s): %v\n", bookID, err)
+			result.Errors++
+			continue
+		}
+		result.ProcessedBooks++
+	}
+}
+
+// recomputeBooks 編集された記事の紐付けを作り直した書籍のスコアを、保存済みの紐付けから再計算する
+func (u *BatchUsecase) recomputeBooks(ctx context.Context, run *batchRun, result *BatchResult) {
+	if len(run.recompute) == 0 {
+		return
+	}
+
+	log.Printf("編集された記事に紐付く書籍のスコアを再計算中... (%d件)\n", len(run.recompute))
+	recomputer := NewScoreRecomputer(u.repo, u.scoring)
+	for bookID := range run.recompute {
+		if err := recomputer.RecomputeBook(ctx, bookID); err != nil {
+			log.Printf("Warning: スコア再計算エラー (BookID: %s): %v\n", bookID, err)
+			result.Errors++
+		}
+	}
+}
+
+// finishPartialRun 期限に近づいたため途中で終了したバッチの結果を確定する
+// スコアの保存・バッチ状態の更新は中断したバッチを再開した実行で行う
+func (u *BatchUsecase) finishPartialRun(result *BatchResult, processed int) *BatchResult {
+	result.NewBooks = int(u.newBooks.Load())
+	result.Retries = u.retryCounts()
+	result.APIUsage = u.usage.Usages()
+	result.EndTime = time.Now()
+	log.Printf("バッチ処理を中断しました: 処理時間 %v（残り%d件は次回の実行で再開します）\n", result.EndTime.Sub(result.StartTime), result.RemainingArticles)
+
+	if u.slackClient != nil {
+		message := fmt.Sprintf("期限に近づいたため記事%d件を処理して中断しました（残り%d件は次回の実行で再開します）",
+			processed, result.RemainingArticles)
+		if err := u.slackClient.SendError("記事取得バッチを途中で終了しました", message); err != nil {
+			log.Printf("Warning: Slack通知エラー: %v\n", err)
+		}
+	}
+	return result
+}
+
+// startRun 各取得元から記事を取得し、処理する記事を決める（Step 1）
+// 取得した記事は進捗に保存し、中断しても取得し直さずに再開できるようにする
+func (u *BatchUsecase) startRun(ctx context.Context, fetchModeOption *FetchModeOption, result *BatchResult) (*batchRun, error) {
+	// 取得元ごとに取得モードを判定
+	plans, err := u.planFetch(ctx, fetchModeOption)
+	if err != nil {
+		return nil, err
+	}
+	result.FetchMode = describeFetchModes(plans)
+
+	// Slack通知: 開始メッセージ
+	if u.slackClient != nil {
+		if err := u.slackClient.SendStartMessage(result.FetchMode); err != nil {
+			log.Printf("Warning: Slack通知エラー: %v\n", err)
+		}
+	}
+
+	var articles []*entity.SourceArticle
+	fetchStats := &entity.FetchStats{}
+	queryRuns := newSearchQueryRuns(result.StartTime)
+
+	// 1. 各取得元から記事を取得
+	log.Println("Step 1: 各取得元から記事を取得中...")
+	u.slackLog("Step 1: 各取得元から記事を取得中...")
+
+	for i := range plans {
+		plan := &plans[i]
+		name := plan.source.Name()
+
+		states, err := u.loadCrawlStates(ctx, plan.source)
+		if err != nil {
+			return nil, err
+		}
+		plan.states = scheduleCrawlStates(states, plan.mode, u.crawl.HistoricalQueriesPerRun)
+		log.Printf("[%s] 取得するクエリ: %d/%d\n", name, len(plan.states), len(states))
+
+		var fetched []*entity.SourceArticle
+		var stats *entity.FetchStats
+		if plan.mode == entity.FetchModeNew {
+			// 最新記事取得モード（未取得のクエリは取得元の前回取得日時から）
+			for _, state := range plan.states {
+				if state.LastNewFetchedAt == nil {
+					state.LastNewFetchedAt = plan.status.LastFetchedAt
+				}
+			}
+			fetched, stats, err = plan.source.FetchNewArticles(ctx, plan.states, u.crawl.NewFetchOverlap, 8)
+			if err != nil {
+				return nil, fmt.Errorf("failed to fetch new articles from %s: %w", name, err)
+			}
+		} else {
+			// 過去記事取得モード（クエリごとの進捗から続きを取得）
+			if len(plan.states) == 0 {
+				log.Printf("[%s] 全クエリの過去記事取得が完了しています - スキップ\n", name)
+				continue
+			}
+			fetched, stats, err = plan.source.FetchHistoricalArticles(ctx, plan.states, 1)
+			if err != nil {
+				return nil, fmt.Errorf("failed to fetch historical articles from %s: %w", name, err)
+			}
+			result.CrawlStates = append(result.CrawlStates, plan.states...)
+		}
+
+		recordCrawlYields(plan.states, stats)
+		queryRuns.addFetchStats(stats, plan.mode)
+		articles = append(articles, fetched...)
+		fetchStats.Merge(stats)
+	}
+
+	log.Printf("取得した記事数: %d\n", len(articles))
+	u.slackLogf("取得した記事数: %d", len(articles))
+	result.ProcessedArticles = len(articles)
+	result.FetchStats = fetchStats
+
+	// 前回と重ねて取得した範囲の保存済み記事は、取得元で編集されたもののみ処理する
+	articles, edited, err := u.selectChangedArticles(ctx, articles)
+	if err != nil {
+		return nil, err
+	}
+	result.UnchangedArticles = result.ProcessedArticles - len(articles)
+	log.Printf("処理対象の記事数: %d（編集された記事: %d, 未更新のため省略: %d）\n", len(articles), len(edited), result.UnchangedArticles)
+
+	// 外部APIの障害で延期した記事を処理し直す
+	articles, queued := u.loadRetryQueue(ctx, articles, edited)
+
+	run := &batchRun{
+		plans:      plans,
+		articles:   articles,
+		edited:     edited,
+		queued:     queued,
+		queryRuns:  queryRuns,
+		bookScores: NewBookScoreMap(),
+		recompute:  make(map[string]bool),
+	}
+	u.createCheckpoint(ctx, run, result.FetchMode)
+	return run, nil
+}
+
+// loadCrawlStates 取得元の有効な検索クエリの取得状態を読み込む
+// 検索クエリは search_queries から読み込み、取得元のクエリが1件も登録されていない場合は取得元の既定クエリを使う
+// 取得状態のないクエリは未実行の状態で追加する
+func (u *BatchUsecase) loadCrawlStates(ctx context.Context, source repository.ArticleSource) ([]*entity.CrawlState, error) {
+	queries, err := u.searchQueries(ctx, source)
+	if err != nil {
+		return nil, err
+	}
+
+	saved, err := u.repo.GetCrawlStates(ctx, source.Name())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get crawl states: %w", err)
+	}
+
+	byQuery := make(map[string]*entity.CrawlState, len(saved))
+	for _, state := range saved {
+		byQuery[state.Query] = state
+	}
+
+	states := make([]*entity.CrawlState, 0, len(queries))
+	for _, query := range queries {
+		state, ok := byQuery[query.Query]
+		if !ok {
+			state = entity.NewCrawlState(source.Name(), query.Query)
+		}
+		state.Priority = query.Priority
+		states = append(states, state)
+	}
+	return states, nil
+}
+
+// searchQueries 取得元の有効な検索クエリを取得（未登録の場合は既定クエリ）
+func (u *BatchUsecase) searchQueries(ctx context.Context, source repository.ArticleSource) ([]*entity.SearchQuery, error) {
+	registered, err := u.repo.GetSearchQueries(ctx, source.Name(), false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get search queries: %w", err)
+	}
+
+	if len(registered) == 0 {
+		log.Printf("[%s] 検索クエリが登録されていないため既定のクエリを使用します\n", source.Name())
+		defaults := make([]*entity.SearchQuery, 0, len(source.DefaultQueries()))
+		for _, query := range source.DefaultQueries() {
+			defaults = append(defaults, &entity.SearchQuery{Source: source.Name(), Query: query, Enabled: true})
+		}
+		return defaults, nil
+	}
+
+	enabled := make([]*entity.SearchQuery, 0, len(registered))
+	for _, query := range registered {
+		if query.Enabled {
+			enabled = append(enabled, query)
+		}
+	}
+	return enabled, nil
+}
+
+// scheduleCrawlStates 取得状態から今回取得するクエリと順序を決める
+// 検索クエリの優先度が高い順、同じ優先度では未実行のクエリ、1回あたりの新規件数が多いクエリの順に並べる
+// 過去記事取得モードでは取得し尽くしたクエリを除き、limit（0の場合は無制限）件までに絞る
+func scheduleCrawlStates(states []*entity.CrawlState, mode entity.FetchMode, limit int) []*entity.CrawlState {
+	scheduled := make([]*entity.CrawlState, 0, len(states))
+	for _, state := range states {
+		if mode == entity.FetchModeHistorical && state.IsCompleted() {
+			continue
+		}
+		scheduled = append(scheduled, state)
+	}
+
+	sort.SliceStable(scheduled, func(i, j int) bool {
+		if scheduled[i].Priority != scheduled[j].Priority {
+			return scheduled[i].Priority > scheduled[j].Priority
+		}
+		if (scheduled[i].Runs == 0) != (scheduled[j].Runs == 0) {
+			return scheduled[i].Runs == 0
+		}
+		return scheduled[i].Yield() > scheduled[j].Yield()
+	})
+
+	if mode == entity.FetchModeHistorical && limit > 0 && len(scheduled) > limit {
+		scheduled = scheduled[:limit]
+	}
+	return scheduled
+}
+
+// recordCrawlYields クエリごとの取得統計を取得状態の実績に加算する
+func recordCrawlYields(states []*entity.CrawlState, stats *entity.FetchStats) {
+	if stats == nil {
+		return
+	}
+
+	byQuery := make(map[string]*entity.CrawlState, len(states))
+	for _, state := range states {
+		byQuery[state.Query] = state
+	}
+	for _, queryStats := range stats.QueryStats {
+		if state, ok := byQuery[queryStats.Query]; ok {
+			state.RecordRun(queryStats.Fetched, queryStats.New)
+		}
+	}
+}
+
+// searchQueryRuns 検索クエリごとの今回の実行結果（取得件数と、見つけた記事から紐付けた書籍数）
+type searchQueryRuns struct {
+	runAt time.Time
+	runs  []*entity.SearchQueryRun
+	index map[string]*entity.SearchQueryRun // 取得元 + クエリ → 実行結果
+}
+
+// newSearchQueryRuns searchQueryRunsを生成
+func newSearchQueryRuns(runAt time.Time) *searchQueryRuns {
+	return &searchQueryRuns{
+		runAt: runAt,
+		index: make(map[string]*entity.SearchQueryRun),
+	}
+}
+
+// addFetchStats 取得元の取得統計からクエリごとの取得件数を記録
+func (q *searchQueryRuns) addFetchStats(stats *entity.FetchStats, mode entity.FetchMode) {
+	if stats == nil {
+		return
+	}
+	for _, queryStats := range stats.QueryStats {
+		run := q.get(queryStats.Source, queryStats.Query)
+		if run == nil {
+			run = &entity.SearchQueryRun{
+				Source:    queryStats.Source,
+				Query:     queryStats.Query,
+				FetchMode: fetchModeCode(mode),
+				RunAt:     q.runAt,
+			}
+			q.index[queryStats.Source+"\x00"+queryStats.Query] = run
+			q.runs = append(q.runs, run)
+		}
+		run.Fetched += queryStats.Fetched
+		run.New += queryStats.New
+		run.Duplicates += queryStats.Duplicates
+	}
+}
+
+// recordArticle 記事を見つけたクエリに、処理した記事と紐付けた書籍数を記録
+func (q *searchQueryRuns) recordArticle(article *entity.SourceArticle, booksLinked int) {
+	if run := q.get(article.Source, article.Query); run != nil {
+		run.RecordArticle(booksLinked)
+	}
+}
+
+// get 取得元とクエリの実行結果を取得（未記録の場合はnil）
+func (q *searchQueryRuns) get(source, query string) *entity.SearchQueryRun {
+	return q.index[source+"\x00"+query]
+}
+
+// fetchModeCode 取得モードの保存用の値（コマンドラインの指定と同じ new / historical）
+func fetchModeCode(mode entity.FetchMode) string {
+	if mode == entity.FetchModeNew {
+		return "new"
+	}
+	return "historical"
+}
+
+// DescribeCrawlProgress 過去記事取得の進捗の表示用文字列（完了したクエリ数と取得中の期間）
+func DescribeCrawlProgress(states []*entity.CrawlState) string {
+	if len(states) == 0 {
+		return ""
+	}
+
+	completed := 0
+	var lines []string
+	for _, state := range states {
+		if state.IsCompleted() {
+			completed++
+			continue
+		}
+		lines = append(lines, state.String())
+	}
+
+	summary := fmt.Sprintf("今回完了したクエリ: %d/%d", completed, len(states))
+	if len(lines) == 0 {
+		return summary
+	}
+	return summary + "\n" + strings.Join(lines, "\n")
+}
+
+// planFetch 取得元ごとのバッチ状態を読み込み、取得モードを決める
+// fetchModeOption: nilの場合は取得元ごとに自動判定、指定された場合は全取得元をそのモードで実行
+func (u *BatchUsecase) planFetch(ctx context.Context, fetchModeOption *FetchModeOption) ([]sourcePlan, error) {
+	plans := make([]sourcePlan, 0, len(u.sources))
+	for _, source := range u.sources {
+		statusID := entity.BatchStatusIDForSource(source.Name())
+		status, err := u.repo.GetBatchStatus(ctx, statusID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get batch status: %w", err)
+		}
+
+		var mode entity.FetchMode
+		if fetchModeOption != nil {
+			// コマンドラインから指定された場合は強制
+			if *fetchModeOption == FetchModeOptionNew {
+				mode = entity.FetchModeNew
+			} else {
+				mode = entity.FetchModeHistorical
+			}
+			log.Printf("[%s] 取得モード: %s（強制指定）\n", source.Name(), mode.String())
+		} else {
+			// 自動判定
+			mode = status.GetFetchMode()
+			log.Printf("[%s] 取得モード: %s（自動判定）\n", source.Name(), mode.String())
+		}
+
+		plans = append(plans, sourcePlan{
+			source:   source,
+			statusID: statusID,
+			status:   status,
+			mode:     mode,
+		})
+	}
+	return plans, nil
+}
+
+// describeFetchModes 取得モードの表示用文字列（全取得元で同じ場合はモード名のみ）
+func describeFetchModes(plans []sourcePlan) string {
+	if len(plans) == 0 {
+		return ""
+	}
+
+	same := true
+	for _, plan := range plans[1:] {
+		if plan.mode != plans[0].mode {
+			same = false
+			break
+		}
+	}
+	if same {
+		return plans[0].mode.String()
+	}
+
+	parts := make([]string, 0, len(plans))
+	for _, plan := range plans {
+		parts = append(parts, fmt.Sprintf("%s: %s", plan.source.Name(), plan.mode.String()))
+	}
+	return strings.Join(parts, ", ")
+}
+
 // slackLog Slackにログを送信
 func (u *BatchUsecase) slackLog(message string) {
 	if u.slackClient != nil {
@@ -234,73 +781,277 @@ func (u *BatchUsecase) slackLogf(format string, args ...interface{}) {
 	}
 }
 
+// selectChangedArticles 取得した記事のうち、未保存の記事と取得元で編集された記事を返す
+// 編集された記事（保存済みで取得元の更新日時が新しい、または未記録の記事）のIDも返す
+func (u *BatchUsecase) selectChangedArticles(ctx context.Context, articles []*entity.SourceArticle) ([]*entity.SourceArticle, map[string]bool, error) {
+	ids := make([]string, 0, len(articles))
+	for _, article := range articles {
+		ids = append(ids, article.ID)
+	}
+	stored, err := u.repo.GetArticleSourceUpdatedAts(ctx, ids)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get stored articles: %w", err)
+	}
+
+	changed := make([]*entity.SourceArticle, 0, len(articles))
+	edited := make(map[string]bool)
+	for _, article := range articles {
+		storedUpdatedAt, exists := stored[article.ID]
+		switch {
+		case !exists:
+			changed = append(changed, article)
+		case storedUpdatedAt == nil || article.UpdatedAt.After(*storedUpdatedAt):
+			changed = append(changed, article)
+			edited[article.ID] = true
+		}
+	}
+	return changed, edited, nil
+}
+
+// reprocessEditedArticle 取得元で編集された記事から書籍を抽出し直す
+// 既存の紐付けを削除してから抽出し、紐付けた書籍数と、編集前後に紐付いていた書籍のIDを返す
+// （今回のスコアは加算せず、紐付けを作り直した後に書籍ごとに再計算する）
+func (u *BatchUsecase) reprocessEditedArticle(ctx context.Context, article *entity.SourceArticle) (int, []string, error) {
+	previousBookIDs, err := u.repo.GetLinkedBookIDs(ctx, article.ID)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to get linked books: %w", err)
+	}
+	if err := u.repo.DeleteArticleBooks(ctx, article.ID); err != nil {
+		return 0, nil, fmt.Errorf("failed to delete article books: %w", err)
+	}
+
+	editedScores := NewBookScoreMap()
+	_, booksLinked, err := u.processArticle(ctx, article, editedScores)
+
+	bookIDs := previousBookIDs
+	for bookID := range editedScores.Scores() {
+		bookIDs = append(bookIDs, bookID)
+	}
+	return booksLinked, bookIDs, err
+}
+
 // processArticle 記事を処理
-func (u *BatchUsecase) processArticle(ctx context.Context, qiitaArticle *entity.QiitaAPIArticle, bookScores BookScoreMap) (bool, error) {
+// 新規の記事かどうかと、記事に紐付けた書籍数（本文・コメント）を返す
+func (u *BatchUsecase) processArticle(ctx context.Context, sourceArticle *entity.SourceArticle, bookScores *BookScoreMap) (bool, int, error) {
 	// 既に処理済みかチェック
-	exists, err := u.repo.ArticleExists(ctx, qiitaArticle.ID)
+	exists, err := u.repo.ArticleExists(ctx, sourceArticle.ID)
 	if err != nil {
-		return false, fmt.Errorf("failed to check article existence: %w", err)
+		return false, 0, fmt.Errorf("failed to check article existence: %w", err)
 	}
 
 	// 既存の記事でも更新する（スコア計算のため）
-	article := qiitaArticle.ToArticle()
+	article := sourceArticle.ToArticle()
 
 	// 記事を保存
 	if err := u.repo.SaveArticle(ctx, article); err != nil {
-		return false, fmt.Errorf("failed to save article: %w", err)
+		return false, 0, fmt.Errorf("failed to save article: %w", err)
+	}
+
+	// はてなブックマーク数を保存（取得できなかった記事は保存済みの件数を残す）
+	if u.hatenaEnabled() && sourceArticle.HatenaFetched {
+		if err := u.repo.SaveHatenaBookmarks(ctx, article.ID, article.HatenaBookmarks); err != nil {
+			log.Printf("Warning: はてなブックマーク数の保存エラー (ID: %s): %v\n", article.ID, err)
+		}
 	}
 
 	// タグを保存
-	if err := u.repo.SaveArticleTags(ctx, qiitaArticle.ID, qiitaArticle.GetTagNames()); err != nil {
-		return false, fmt.Errorf("failed to save article tags: %w", err)
+	if err := u.repo.SaveArticleTags(ctx, sourceArticle.ID, sourceArticle.Tags); err != nil {
+		return false, 0, fmt.Errorf("failed to save article tags: %w", err)
 	}
 
 	// 記事本文から書籍を抽出
-	extractedBooks := u.bookExtractor.ExtractFromText(qiitaArticle.Body)
+	extractedBooks := u.bookExtractor.ExtractFromText(sourceArticle.Body)
 	if len(extractedBooks) == 0 {
-		// HTMLからも試す
-		extractedBooks = u.bookExtractor.ExtractFromHTML(qiitaArticle.RenderedBody)
+		// HTMLからも試す（Markdownを返さない取得元はHTMLのみ）
+		extractedBooks = u.bookExtractor.ExtractFromHTML(sourceArticle.RenderedBody)
 	}
 
 	// 抽出した書籍を処理
+	linkedBookIDs := make(map[string]bool)
+	var deferErr error
 	for _, extracted := range extractedBooks {
 		bookID, err := u.processExtractedBook(ctx, extracted)
 		if err != nil {
-			// 書籍取得に失敗した場合はスキップ
+			// 書籍取得に失敗した場合はスキップ（遮断中・上限に達した外部APIで調べられなかった場合は記事ごと延期する）
+			if _, ok := deferralReason(err); ok && deferErr == nil {
+				deferErr = err
+			}
 			continue
 		}
 
 		if bookID != "" {
-			// 記事と書籍を紐付け
-			if err := u.repo.SaveArticleBook(ctx, article.ID, bookID); err != nil {
-				log.Printf("Warning: 記事-書籍紐付けエラー: %v\n", err)
-			}
+			u.linkMention(ctx, article, bookID, extracted, entity.MentionOriginBody, bookScores)
+			linkedBookIDs[bookID] = true
+		}
+	}
 
-			// スコアを加算
-			if score, ok := bookScores[bookID]; ok {
-				score.AddScore(article.Likes, article.Stocks, article.PublishedAt)
-			} else {
-				// 既存のスコアを取得
-				existingScore, _ := u.repo.GetExistingBookScore(ctx, bookID)
-				bookScores[bookID] = &entity.BookScore{
-					BookID:       bookID,
-					Score:        existingScore,
-					ArticleCount: 0,
-				}
-				bookScores[bookID].AddScore(article.Likes, article.Stocks, article.PublishedAt)
+	// コメントから書籍を抽出（有効かつ反応の多い記事のみ）
+	if u.shouldProcessComments(article) {
+		u.processComments(ctx, article, linkedBookIDs, bookScores)
+	}
+
+	if deferErr != nil {
+		return !exists, len(linkedBookIDs), fmt.Errorf("book lookup deferred: %w", deferErr)
+	}
+	return !exists, len(linkedBookIDs), nil
+}
+
+// sourceByName 名前で記事の取得元を探す（見つからない場合は nil）
+func (u *BatchUsecase) sourceByName(name string) repository.ArticleSource {
+	for _, source := range u.sources {
+		if source.Name() == name {
+			return source
+		}
+	}
+	return nil
+}
+
+// retryCounter 外部APIのリトライ回数を報告できるクライアント
+type retryCounter interface {
+	Retries() int
+}
+
+// retryCounts 外部APIクライアントごとのリトライ回数（リトライのなかったクライアントは含めない）
+func (u *BatchUsecase) retryCounts() map[string]int {
+	counts := make(map[string]int)
+	add := func(name string, client interface{}) {
+		if counter, ok := client.(retryCounter); ok && counter.Retries() > 0 {
+			counts[name] += counter.Retries()
+		}
+	}
+
+	for _, source := range u.sources {
+		add(source.Name(), source)
+	}
+	if u.metadata != nil {
+		for _, provider := range u.metadata.providers {
+			add(provider.Name(), provider)
+		}
+	}
+	if u.hatenaClient != nil {
+		add("hatena", u.hatenaClient)
+	}
+	if u.slackClient != nil {
+		add("slack", u.slackClient)
+	}
+	return counts
+}
+
+// workers 記事を並行処理するワーカー数（1未満の設定は1）
+func (u *BatchUsecase) workers() int {
+	if u.processing.Workers < 1 {
+		return 1
+	}
+	return u.processing.Workers
+}
+
+// hatenaEnabled はてなブックマーク数を取得するかどうか
+func (u *BatchUsecase) hatenaEnabled() bool {
+	return u.hatenaClient != nil && u.hatenaClient.IsEnabled()
+}
+
+// attachHatenaBookmarks 取得した記事にはてなブックマーク数を付与し、取得できた記事数を返す
+// 取得に失敗した記事は HatenaFetched を立てず（0件のまま）処理を続ける
+func (u *BatchUsecase) attachHatenaBookmarks(ctx context.Context, articles []*entity.SourceArticle) int {
+	urls := make([]string, 0, len(articles))
+	for _, article := range articles {
+		urls = append(urls, article.URL)
+	}
+
+	// エラーの場合もそれまでに取得できたURLの件数は使う
+	counts, err := u.hatenaClient.GetBookmarkCounts(ctx, urls)
+	if err != nil {
+		log.Printf("Warning: はてなブックマーク数の取得エラー（%d/%d件のみ取得）: %v\n", len(counts), len(urls), err)
+	}
+
+	fetched := 0
+	for _, article := range articles {
+		count, ok := counts[article.URL]
+		if !ok {
+			continue
+		}
+		article.HatenaBookmarks = count
+		article.HatenaFetched = true
+		fetched++
+	}
+	return fetched
+}
+
+// shouldProcessComments コメントを取得する対象の記事かどうかを判定
+func (u *BatchUsecase) shouldProcessComments(article *entity.Article) bool {
+	if !u.comments.Enabled || article.Comments == 0 {
+		return false
+	}
+	if _, ok := u.commentSources[article.Source]; !ok {
+		return false
+	}
+	return article.Likes >= u.comments.MinLikes
+}
+
+// processComments 記事のコメントから書籍を抽出して紐付け
+// linkedBookIDs: 本文で紐付け済みの書籍（同じ記事内で二重にスコア加算しない）
+func (u *BatchUsecase) processComments(ctx context.Context, article *entity.Article, linkedBookIDs map[string]bool, bookScores *BookScoreMap) {
+	comments, err := u.commentSources[article.Source].GetComments(ctx, article.ID)
+	if err != nil {
+		log.Printf("Warning: コメント取得エラー (ID: %s): %v\n", article.ID, err)
+		return
+	}
+
+	for _, comment := range comments {
+		for _, extracted := range u.bookExtractor.ExtractFromText(comment.Body) {
+			bookID, err := u.processExtractedBook(ctx, extracted)
+			if err != nil || bookID == "" || linkedBookIDs[bookID] {
+				continue
 			}
 
-			// カテゴリを振り分け
-			u.assignBookCategories(ctx, bookID, article.Tags)
+			u.linkMention(ctx, article, bookID, extracted, entity.MentionOriginComment, bookScores)
+			linkedBookIDs[bookID] = true
 		}
 	}
+}
+
+// linkMention 記事と書籍を紐付け、スコア加算とカテゴリ振り分けを行う
+func (u *BatchUsecase) linkMention(ctx context.Context, article *entity.Article, bookID string, extracted extractor.ExtractedBook, origin entity.MentionOrigin, bookScores *BookScoreMap) {
+	// 言及箇所の極性を判定（抜粋がなければ前後テキストで判定）
+	mention := extracted.Excerpt
+	if mention == "" {
+		mention = extracted.Snippet
+	}
+	polarity := u.classifier.Classify(mention)
+
+	// 記事と書籍を紐付け（抽出元の情報も保存）
+	articleBook := &entity.ArticleBook{
+		ArticleID:      article.ID,
+		BookID:         bookID,
+		Origin:         origin,
+		SourceType:     extracted.SourceType,
+		Confidence:     extracted.Confidence,
+		MatchedText:    extracted.MatchedText,
+		Snippet:        extracted.Snippet,
+		Excerpt:        extracted.Excerpt,
+		Sentiment:      polarity.Label,
+		SentimentScore: polarity.Score,
+	}
+	if err := u.repo.SaveArticleBook(ctx, articleBook); err != nil {
+		log.Printf("Warning: 記事-書籍紐付けエラー: %v\n", err)
+	}
 
-	return !exists, nil
+	// 確からしさ・取得元・言及箇所・極性からスコアの重みを決定
+	weight, ok := mentionWeight(u.scoring, article.Source, origin, extracted.Confidence, polarity.Label)
+	if !ok {
+		return
+	}
+
+	// スコアを加算（記事ごとに集計し、記事の処理が終わってからバッチ全体のスコアに加算する）
+	bookScores.Add(bookID, article.Popularity(u.scoring.HatenaWeight), article.PublishedAt, weight)
+
+	// カテゴリを振り分け
+	u.assignBookCategories(ctx, bookID, article.Tags)
 }
 
 // processExtractedBook 抽出した書籍情報を処理
 func (u *BatchUsecase) processExtractedBook(ctx context.Context, extracted extractor.ExtractedBook) (string, error) {
-	var rakutenBook *entity.RakutenBook
+	var book *entity.BookMetadata
 	var err error
 
 	// ISBNがある場合
@@ -311,35 +1062,42 @@ func (u *BatchUsecase) processExtractedBook(ctx context.Context, extracted extra
 			return "", fmt.Errorf("failed to check book existence: %w", err)
 		}
 		if existingBookID != "" {
-			// 既存の書籍が見つかった場合はそのIDを返す（楽天API呼び出し不要）
+			// 既存の書籍が見つかった場合はそのIDを返す（外部API呼び出し不要）
 			return existingBookID, nil
 		}
 
-		// 楽天APIで書籍情報を取得
-		rakutenBook, err = u.rakutenClient.SearchByISBN(ctx, extracted.ISBN)
+		// 検索結果のキャッシュを確認してから、メタデータプロバイダを順に問い合わせて書籍情報を取得
+		var cachedBookID string
+		cachedBookID, book, err = u.lookupBook(ctx, entity.BookLookupISBN, extracted.ISBN, u.metadata.LookupByISBN)
 		if err != nil {
 			// ISBNで見つからない場合はスキップ
 			return "", fmt.Errorf("failed to fetch book by ISBN: %w", err)
 		}
+		if cachedBookID != "" {
+			return cachedBookID, nil
+		}
 	} else if extracted.Title != "" {
-		// タイトルで検索
-		books, err := u.rakutenClient.SearchByTitle(ctx, extracted.Title)
-		if err != nil || len(books) == 0 {
+		// タイトルで検索（以前に見つかったタイトルは保存済みの書籍を返す）
+		var cachedBookID string
+		cachedBookID, book, err = u.lookupBook(ctx, entity.BookLookupTitle, extracted.Title, u.metadata.SearchByTitle)
+		if err != nil {
 			return "", fmt.Errorf("failed to fetch book by title: %w", err)
 		}
-		rakutenBook = books[0]
+		if cachedBookID != "" {
+			return cachedBookID, nil
+		}
 	} else if extracted.ASIN != "" {
-		// ASINの場合は楽天APIでは直接検索できないのでスキップ
+		// ASINの場合は書籍メタデータAPIでは直接検索できないのでスキップ
 		// 将来的にはAmazon APIで対応
 		return "", fmt.Errorf("ASIN lookup not supported yet")
 	}
 
-	if rakutenBook == nil || rakutenBook.ISBN == "" {
+	if book == nil || book.ISBN == "" {
 		return "", fmt.Errorf("no valid book data")
 	}
 
-	// 楽天APIで取得したISBN（正規化済み）で再度存在チェック
-	existingBookID, err := u.repo.GetBookIDByISBN(ctx, rakutenBook.ISBN)
+	// プロバイダから取得したISBN（正規化済み）で再度存在チェック
+	existingBookID, err := u.repo.GetBookIDByISBN(ctx, book.ISBN)
 	if err != nil {
 		return "", fmt.Errorf("failed to check book existence: %w", err)
 	}
@@ -350,15 +1108,13 @@ func (u *BatchUsecase) processExtractedBook(ctx context.Context, extracted extra
 	}
 
 	// 書籍を保存
-	if err = u.repo.SaveBook(ctx, rakutenBook); err != nil {
+	if err = u.repo.SaveBook(ctx, book); err != nil {
 		return "", fmt.Errorf("failed to save book: %w", err)
 	}
+	u.newBooks.Add(1)
 
-	// レートリミット対策
-	time.Sleep(300 * time.Millisecond)
-
-	// 楽天APIから取得したISBNを返す（保存したIDと一致させる）
-	return rakutenBook.ISBN, nil
+	// プロバイダから取得したISBNを返す（保存したIDと一致させる）
+	return book.ISBN, nil
 }
 
 // assignBookCategories 書籍にカテゴリを割り当て
@@ -393,7 +1149,11 @@ func (u *BatchUsecase) logError(ctx context.Context, errorType string, err error
 // GetStats バッチ処理の統計情報を取得（デバッグ用）
 func (u *BatchUsecase) GetStats(ctx context.Context) (map[string]interface{}, error) {
 	// 統計情報を返す（将来の拡張用）
+	searchQueries := make(map[string][]string, len(u.sources))
+	for _, source := range u.sources {
+		searchQueries[source.Name()] = source.DefaultQueries()
+	}
 	return map[string]interface{}{
-		"search_queries": external.SearchQueries,
+		"search_queries": searchQueries,
 	}, nil
 }
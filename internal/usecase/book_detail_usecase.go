@@ -40,6 +40,7 @@ func (uc *BookDetailUsecase) GetBookDetail(ctx context.Context, bookID string) (
 			Likes:    article.Likes,
 			Stocks:   article.Stocks,
 			Comments: article.Comments,
+			Excerpt:  article.Excerpt,
 		})
 	}
 
@@ -53,6 +54,12 @@ func (uc *BookDetailUsecase) GetBookDetail(ctx context.Context, bookID string) (
 		Tags:          bookDetail.Tags,
 		Overview:      bookDetail.Overview,
 		QiitaArticles: qiitaArticles,
+		Recommendation: dto.RecommendationDTO{
+			Positive: bookDetail.MentionSentiment.Positive,
+			Neutral:  bookDetail.MentionSentiment.Neutral,
+			Negative: bookDetail.MentionSentiment.Negative,
+			Ratio:    bookDetail.MentionSentiment.RecommendationRatio(),
+		},
 		RakutenReviewSummary: dto.RakutenReviewSummaryDTO{
 			AverageRating: bookDetail.RakutenReviewSummary.AverageRating,
 			TotalReviews:  bookDetail.RakutenReviewSummary.TotalReviews,
@@ -0,0 +1,66 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"teckbook-compass-backend/internal/domain/entity"
+)
+
+// errLookupCached 以前の問い合わせで見つからず、問い合わせ直す時刻になっていないため問い合わせなかった
+var errLookupCached = errors.New("book not found in previous lookup")
+
+// lookupBook 検索結果のキャッシュ（book_lookups）を確認してから fetch でメタデータプロバイダに問い合わせ、結果をキャッシュに保存する
+// 以前に見つかった書籍が保存済みの場合はその書籍IDを返す（プロバイダに問い合わせない）
+// 以前に見つからず、問い合わせ直す時刻になっていない場合は errLookupCached を返す
+// 障害・使用量の上限で応答がなかったプロバイダがある場合は、見つからなかったとは記録しない
+func (u *BatchUsecase) lookupBook(ctx context.Context, lookupType entity.BookLookupType, value string, fetch func(context.Context, string) (*entity.BookMetadata, error)) (string, *entity.BookMetadata, error) {
+	interval := u.processing.LookupRecheckInterval
+	if interval <= 0 {
+		book, err := fetch(ctx, value)
+		return "", book, err
+	}
+
+	key := entity.BookLookupKey(lookupType, value)
+	lookup, err := u.repo.GetBookLookup(ctx, lookupType, key)
+	if err != nil {
+		log.Printf("Warning: 検索結果のキャッシュの取得エラー (%s %s): %v\n", lookupType, key, err)
+		lookup = nil
+	}
+
+	now := time.Now()
+	switch {
+	case lookup == nil:
+		lookup = &entity.BookLookup{Type: lookupType, Key: key}
+	case lookup.Found():
+		bookID, err := u.repo.GetBookIDByISBN(ctx, lookup.ISBN)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to check book existence: %w", err)
+		}
+		if bookID != "" {
+			return bookID, nil, nil
+		}
+		// 書籍が保存されていない場合は問い合わせ直す
+	case !lookup.ShouldRecheck(now):
+		return "", nil, fmt.Errorf("%w: %s %q (misses: %d, next check: %s)",
+			errLookupCached, lookupType, value, lookup.Misses, lookup.NextCheckAt.Format(time.RFC3339))
+	}
+
+	book, err := fetch(ctx, value)
+	switch {
+	case err == nil && book != nil && book.ISBN != "":
+		lookup.RecordFound(book.ISBN, now)
+	case errors.Is(err, errBookNotFound):
+		lookup.RecordMiss(now, interval, max(u.processing.LookupRecheckMaxInterval, interval))
+	default:
+		return "", book, err
+	}
+
+	if saveErr := u.repo.SaveBookLookup(ctx, lookup); saveErr != nil {
+		log.Printf("Warning: 検索結果のキャッシュの保存エラー (%s %s): %v\n", lookupType, key, saveErr)
+	}
+	return "", book, err
+}
@@ -0,0 +1,99 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"teckbook-compass-backend/internal/domain/entity"
+	"teckbook-compass-backend/internal/domain/repository"
+	"teckbook-compass-backend/internal/infrastructure/external"
+)
+
+// errBookNotFound 問い合わせたすべてのプロバイダで書籍が見つからなかった（障害等で応答がなかったプロバイダがある場合は含めない）
+var errBookNotFound = errors.New("book not found by any provider")
+
+// BookMetadataChain 書籍メタデータプロバイダを設定順に問い合わせ、結果をフィールドごとにマージする
+// 先のプロバイダで見つからない書籍や欠けているフィールドを後のプロバイダで補完する
+type BookMetadataChain struct {
+	providers []repository.BookMetadataProvider
+}
+
+// NewBookMetadataChain BookMetadataChainを生成
+func NewBookMetadataChain(providers ...repository.BookMetadataProvider) *BookMetadataChain {
+	return &BookMetadataChain{
+		providers: providers,
+	}
+}
+
+// LookupByISBN ISBNで書籍メタデータを取得
+func (c *BookMetadataChain) LookupByISBN(ctx context.Context, isbn string) (*entity.BookMetadata, error) {
+	return c.enrich(ctx, &entity.BookMetadata{}, isbn, "")
+}
+
+// SearchByTitle タイトル検索に対応したプロバイダで検索し、先頭の結果を他のプロバイダで補完
+func (c *BookMetadataChain) SearchByTitle(ctx context.Context, title string) (*entity.BookMetadata, error) {
+	var errs []error
+	for _, provider := range c.providers {
+		searcher, ok := provider.(repository.BookTitleSearcher)
+		if !ok {
+			continue
+		}
+
+		results, err := searcher.SearchMetadataByTitle(ctx, title)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", provider.Name(), err))
+			continue
+		}
+		if len(results) == 0 || results[0].ISBN == "" {
+			continue
+		}
+
+		merged := &entity.BookMetadata{}
+		merged.Merge(results[0], provider.Name())
+		return c.enrich(ctx, merged, merged.ISBN, provider.Name())
+	}
+
+	return nil, fmt.Errorf("no book found for title %q: %w", title, lookupError(errs))
+}
+
+// enrich 主要なフィールドが埋まるまで各プロバイダにISBNで問い合わせてマージ
+// skip: 問い合わせ済みのプロバイダ名
+func (c *BookMetadataChain) enrich(ctx context.Context, merged *entity.BookMetadata, isbn string, skip string) (*entity.BookMetadata, error) {
+	var errs []error
+	for _, provider := range c.providers {
+		if merged.IsComplete() {
+			break
+		}
+		if provider.Name() == skip {
+			continue
+		}
+
+		found, err := provider.LookupByISBN(ctx, isbn)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", provider.Name(), err))
+			continue
+		}
+		merged.Merge(found, provider.Name())
+	}
+
+	if merged.Title == "" {
+		return nil, fmt.Errorf("no book metadata found for ISBN %s: %w", isbn, lookupError(errs))
+	}
+
+	if merged.ISBN == "" {
+		merged.ISBN = isbn
+	}
+	merged.ISBN = entity.ToISBN13(merged.ISBN)
+	return merged, nil
+}
+
+// lookupError プロバイダごとのエラーをまとめる（すべて見つからなかった場合は errBookNotFound を含める）
+func lookupError(errs []error) error {
+	for _, err := range errs {
+		if !errors.Is(err, external.ErrBookMetadataNotFound) {
+			return errors.Join(errs...)
+		}
+	}
+	return errors.Join(append([]error{errBookNotFound}, errs...)...)
+}
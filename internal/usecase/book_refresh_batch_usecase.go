@@ -0,0 +1,184 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"teckbook-compass-backend/internal/domain/entity"
+	"teckbook-compass-backend/internal/domain/repository"
+	"teckbook-compass-backend/internal/infrastructure/config"
+	"teckbook-compass-backend/internal/infrastructure/external"
+)
+
+// BookRefreshBatchUsecase 保存済み書籍の楽天情報（評価・価格・在庫・表紙）再取得バッチ処理ユースケース
+type BookRefreshBatchUsecase struct {
+	repo          repository.BatchRepository
+	rakutenClient *external.RakutenClient
+	refresh       config.RefreshConfig
+	processing    config.ProcessingConfig
+}
+
+// NewBookRefreshBatchUsecase BookRefreshBatchUsecaseを生成
+func NewBookRefreshBatchUsecase(
+	repo repository.BatchRepository,
+	rakutenClient *external.RakutenClient,
+	refresh config.RefreshConfig,
+	processing config.ProcessingConfig,
+) *BookRefreshBatchUsecase {
+	return &BookRefreshBatchUsecase{
+		repo:          repo,
+		rakutenClient: rakutenClient,
+		refresh:       refresh,
+		processing:    processing,
+	}
+}
+
+// BookRefreshChange 1冊分の変更内容
+type BookRefreshChange struct {
+	BookID  string
+	Title   string
+	Changes []entity.BookFieldChange
+}
+
+// BookRefreshBatchResult 書籍情報再取得バッチ結果
+type BookRefreshBatchResult struct {
+	ProcessedBooks  int
+	UpdatedBooks    int            // 1項目以上変更があった書籍数
+	UnchangedBooks  int            // 変更がなかった書籍数
+	NotFoundBooks   int            // 楽天で見つからなかった書籍数
+	FieldChanges    map[string]int // フィールドごとの変更件数
+	Changes         []BookRefreshChange
+	Errors          int
+	Retries         int                    // 楽天ブックスAPIへのリトライ回数
+	SkippedBooks    int                    // 1日の使用量の上限・期限に達したため次回に持ち越した書籍数
+	APIUsage        external.ProviderUsage // 楽天ブックスAPIの当日の使用量と残り（他のバッチの分も含む）
+	DeadlineReached bool                   // 期限に近づいたため途中で終了した
+	StartTime       time.Time
+	EndTime         time.Time
+}
+
+// Counters 実行履歴（batch_runs）に記録する件数（フィールドごとの変更件数を含む）
+func (r *BookRefreshBatchResult) Counters() map[string]int {
+	counters := map[string]int{
+		"processed_books": r.ProcessedBooks,
+		"updated_books":   r.UpdatedBooks,
+		"unchanged_books": r.UnchangedBooks,
+		"not_found_books": r.NotFoundBooks,
+		"skipped_books":   r.SkippedBooks,
+		"retries":         r.Retries,
+		"errors":          r.Errors,
+	}
+	for field, count := range r.FieldChanges {
+		counters["field_changes."+field] = count
+	}
+	return counters
+}
+
+// Run 書籍情報再取得バッチを実行
+// limit: 処理する書籍の最大数（前回取得からの経過日数×人気度の高い書籍から順に処理）
+func (u *BookRefreshBatchUsecase) Run(ctx context.Context, limit int) (*BookRefreshBatchResult, error) {
+	result := &BookRefreshBatchResult{
+		FieldChanges: make(map[string]int),
+		StartTime:    time.Now(),
+	}
+
+	log.Println("書籍情報再取得バッチを開始します...")
+
+	// 1冊につき1リクエストのため、楽天ブックスAPIの当日の残りを超える分は次回に回す
+	limit = limitByBudget(limit, u.rakutenClient.Usage())
+	if limit == 0 {
+		log.Println("楽天ブックスAPIの本日の使用量が上限に達しているため処理しません")
+		result.APIUsage = u.rakutenClient.Usage()
+		result.EndTime = time.Now()
+		return result, nil
+	}
+
+	minAge := time.Duration(u.refresh.BookMinAgeDays) * 24 * time.Hour
+	books, err := u.repo.GetBooksForMetadataRefresh(ctx, minAge, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get books for metadata refresh: %w", err)
+	}
+
+	log.Printf("処理対象の書籍数: %d（前回取得から%d日以上経過）", len(books), u.refresh.BookMinAgeDays)
+
+	for i, book := range books {
+		if deadlineReached(ctx, u.processing.DeadlineMargin) {
+			log.Printf("Warning: 期限に近づいたため残り%d件を次回に持ち越します", len(books)-i)
+			result.SkippedBooks = len(books) - i
+			result.DeadlineReached = true
+			break
+		}
+		if ctx.Err() != nil {
+			log.Printf("Warning: タイムアウトのため残り%d件を次回に持ち越します", len(books)-i)
+			break
+		}
+		if remaining, ok := u.rakutenClient.Usage().Remaining(); ok && remaining == 0 {
+			// 他のバッチと上限を共有しているため、途中で使い切ることがある
+			log.Printf("Warning: 楽天ブックスAPIの本日の使用量が上限に達したため残り%d件を次回に持ち越します", len(books)-i)
+			result.SkippedBooks = len(books) - i
+			break
+		}
+
+		result.ProcessedBooks++
+		u.refreshBook(ctx, book, result)
+	}
+
+	result.Retries = u.rakutenClient.Retries()
+	result.APIUsage = u.rakutenClient.Usage()
+	result.EndTime = time.Now()
+	log.Printf("書籍情報再取得バッチ完了: 処理時間 %v\n", result.EndTime.Sub(result.StartTime))
+
+	return result, nil
+}
+
+// refreshBook 1冊分の楽天情報を再取得して変更があれば更新
+func (u *BookRefreshBatchUsecase) refreshBook(ctx context.Context, book *repository.BookForMetadataRefresh, result *BookRefreshBatchResult) {
+	rakutenBook, err := u.rakutenClient.SearchByISBN(ctx, book.ID)
+	if err != nil {
+		if errors.Is(err, external.ErrBookMetadataNotFound) {
+			result.NotFoundBooks++
+			if err := u.repo.MarkBookMetadataRefreshed(ctx, book.ID); err != nil {
+				log.Printf("Warning: 再取得日時の更新エラー (ID: %s): %v\n", book.ID, err)
+				result.Errors++
+			}
+			return
+		}
+		log.Printf("Warning: 楽天APIエラー (ID: %s): %v\n", book.ID, err)
+		result.Errors++
+		return
+	}
+
+	next := entity.SnapshotFromRakuten(rakutenBook)
+	changes := book.Current.Diff(next)
+
+	if err := u.repo.UpdateBookRakutenSnapshot(ctx, book.ID, next); err != nil {
+		log.Printf("Warning: 書籍情報の更新エラー (ID: %s): %v\n", book.ID, err)
+		result.Errors++
+		return
+	}
+
+	if len(changes) == 0 {
+		result.UnchangedBooks++
+		return
+	}
+
+	result.UpdatedBooks++
+	for _, change := range changes {
+		result.FieldChanges[change.Field]++
+	}
+	result.Changes = append(result.Changes, BookRefreshChange{
+		BookID:  book.ID,
+		Title:   book.Title,
+		Changes: changes,
+	})
+
+	descriptions := make([]string, 0, len(changes))
+	for _, change := range changes {
+		descriptions = append(descriptions, change.String())
+	}
+	log.Printf("  更新: %s (%s) %s", book.Title, book.ID, strings.Join(descriptions, ", "))
+}
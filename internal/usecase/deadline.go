@@ -0,0 +1,23 @@
+package usecase
+
+import (
+	"context"
+	"time"
+)
+
+// deadlineReached 呼び出し元の期限（Lambdaの実行時間の上限等）までの残りが余裕以下になったかどうか
+// 期限がないコンテキストでは常に false
+func deadlineReached(ctx context.Context, margin time.Duration) bool {
+	deadline, ok := ctx.Deadline()
+	return ok && time.Until(deadline) <= margin
+}
+
+// intakeContext 新しい処理を始めてよい間だけ有効なコンテキスト（期限から余裕を引いた時刻に終了する）
+// 処理中の記事は呼び出し元のコンテキストで続けるため、終了後も途中までの結果を保存できる
+func intakeContext(ctx context.Context, margin time.Duration) (context.Context, context.CancelFunc) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return context.WithCancel(ctx)
+	}
+	return context.WithDeadline(ctx, deadline.Add(-margin))
+}
@@ -12,6 +12,7 @@ type BookDetailResponse struct {
 	Tags                 []string                `json:"tags"`
 	Overview             string                  `json:"overview"`
 	QiitaArticles        []QiitaArticleDTO       `json:"qiitaArticles"`
+	Recommendation       RecommendationDTO       `json:"recommendation"`
 	RakutenReviewSummary RakutenReviewSummaryDTO `json:"rakutenReviewSummary"`
 	PurchaseLinks        PurchaseLinksDTO        `json:"purchaseLinks"`
 }
@@ -23,6 +24,15 @@ type QiitaArticleDTO struct {
 	Likes    int    `json:"likes"`
 	Stocks   int    `json:"stocks"`
 	Comments int    `json:"comments"`
+	Excerpt  string `json:"excerpt"`
+}
+
+// RecommendationDTO 記事での言及の極性集計
+type RecommendationDTO struct {
+	Positive int      `json:"positive"`
+	Neutral  int      `json:"neutral"`
+	Negative int      `json:"negative"`
+	Ratio    *float64 `json:"ratio"`
 }
 
 // RakutenReviewSummaryDTO 楽天レビューサマリー
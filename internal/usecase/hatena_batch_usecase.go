@@ -0,0 +1,177 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"teckbook-compass-backend/internal/domain/repository"
+	"teckbook-compass-backend/internal/infrastructure/config"
+	"teckbook-compass-backend/internal/infrastructure/external"
+)
+
+// HatenaBatchUsecase 保存済み記事のはてなブックマーク数更新バッチ処理ユースケース
+type HatenaBatchUsecase struct {
+	repo         repository.BatchRepository
+	hatenaClient *external.HatenaClient
+	recomputer   *ScoreRecomputer
+	scoring      config.ScoringConfig
+	processing   config.ProcessingConfig
+}
+
+// NewHatenaBatchUsecase HatenaBatchUsecaseを生成
+func NewHatenaBatchUsecase(
+	repo repository.BatchRepository,
+	hatenaClient *external.HatenaClient,
+	scoring config.ScoringConfig,
+	processing config.ProcessingConfig,
+) *HatenaBatchUsecase {
+	return &HatenaBatchUsecase{
+		repo:         repo,
+		hatenaClient: hatenaClient,
+		recomputer:   NewScoreRecomputer(repo, scoring),
+		scoring:      scoring,
+		processing:   processing,
+	}
+}
+
+// HatenaBatchResult はてなブックマーク数更新バッチ結果
+type HatenaBatchResult struct {
+	ProcessedArticles  int
+	BookmarkedArticles int  // 1件以上ブックマークされていた記事数
+	RecomputedBooks    int  // ブックマーク数が変わった記事に紐付くためスコアを再計算した書籍数
+	SkippedArticles    int  // 期限に達した・件数を取得できなかったため次回に持ち越した記事数
+	DeadlineReached    bool // 期限に近づいたため途中で終了した
+	Errors             int
+	StartTime          time.Time
+	EndTime            time.Time
+}
+
+// Counters 実行履歴（batch_runs）に記録する件数
+func (r *HatenaBatchResult) Counters() map[string]int {
+	return map[string]int{
+		"processed_articles":  r.ProcessedArticles,
+		"bookmarked_articles": r.BookmarkedArticles,
+		"recomputed_books":    r.RecomputedBooks,
+		"skipped_articles":    r.SkippedArticles,
+		"errors":              r.Errors,
+	}
+}
+
+// Run はてなブックマーク数更新バッチを実行
+// limit: 処理する記事の最大数（未取得・取得日時が古い記事から順に処理）
+func (u *HatenaBatchUsecase) Run(ctx context.Context, limit int) (*HatenaBatchResult, error) {
+	result := &HatenaBatchResult{
+		StartTime: time.Now(),
+	}
+
+	log.Println("はてなブックマーク数更新バッチを開始します...")
+
+	if !u.hatenaClient.IsEnabled() {
+		return nil, fmt.Errorf("hatena bookmark api is disabled")
+	}
+
+	articles, err := u.repo.GetArticlesForHatenaUpdate(ctx, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get articles for hatena update: %w", err)
+	}
+
+	log.Printf("処理対象の記事数: %d", len(articles))
+
+	if len(articles) == 0 {
+		result.EndTime = time.Now()
+		return result, nil
+	}
+
+	urls := make([]string, 0, len(articles))
+	for _, article := range articles {
+		urls = append(urls, article.URL)
+	}
+
+	if deadlineReached(ctx, u.processing.DeadlineMargin) {
+		// 未取得・取得日時が古い記事から処理するため、次回の実行で同じ記事から取得する
+		log.Printf("Warning: 期限に近づいたため%d件を次回に持ち越します", len(articles))
+		result.SkippedArticles = len(articles)
+		result.DeadlineReached = true
+		result.EndTime = time.Now()
+		return result, nil
+	}
+
+	// 途中のリクエストが失敗した場合も、取得できた記事の件数は保存する
+	// （取得できなかった記事は取得日時が古いまま残るため、次回の実行で先に取得する）
+	counts, fetchErr := u.hatenaClient.GetBookmarkCounts(ctx, urls)
+	if fetchErr != nil {
+		log.Printf("Warning: はてなブックマーク数の取得エラー（取得できた%d/%d件のみ保存します）: %v\n", len(counts), len(articles), fetchErr)
+		result.Errors++
+	}
+
+	// ブックマーク数が変わった記事に紐付く書籍（スコアの再計算対象）
+	recompute := make(map[string]bool)
+	for _, article := range articles {
+		count, ok := counts[article.URL]
+		if !ok {
+			result.SkippedArticles++
+			continue
+		}
+
+		result.ProcessedArticles++
+		if err := u.repo.SaveHatenaBookmarks(ctx, article.ID, count); err != nil {
+			log.Printf("Warning: はてなブックマーク数の保存エラー (ID: %s): %v\n", article.ID, err)
+			result.Errors++
+			continue
+		}
+		if count > 0 {
+			result.BookmarkedArticles++
+		}
+		if count != article.HatenaBookmarks {
+			u.collectLinkedBooks(ctx, article.ID, recompute, result)
+		}
+	}
+
+	// 記事の人気度にはブックマーク数が含まれるため、変わった記事に紐付く書籍のスコアを再計算する
+	u.recomputeBooks(ctx, recompute, result)
+
+	result.EndTime = time.Now()
+	log.Printf("はてなブックマーク数更新バッチ完了: 処理時間 %v\n", result.EndTime.Sub(result.StartTime))
+
+	if fetchErr != nil && result.ProcessedArticles == 0 {
+		return result, fmt.Errorf("failed to get hatena bookmark counts: %w", fetchErr)
+	}
+	return result, nil
+}
+
+// collectLinkedBooks 記事に紐付いている書籍をスコアの再計算対象に加える
+// はてなブックマーク数の重みが0の場合はスコアが変わらないため加えない
+func (u *HatenaBatchUsecase) collectLinkedBooks(ctx context.Context, articleID string, recompute map[string]bool, result *HatenaBatchResult) {
+	if u.scoring.HatenaWeight == 0 {
+		return
+	}
+
+	bookIDs, err := u.repo.GetLinkedBookIDs(ctx, articleID)
+	if err != nil {
+		log.Printf("Warning: 紐付け書籍の取得エラー (ID: %s): %v\n", articleID, err)
+		result.Errors++
+		return
+	}
+	for _, bookID := range bookIDs {
+		recompute[bookID] = true
+	}
+}
+
+// recomputeBooks ブックマーク数が変わった記事に紐付く書籍のスコアを再計算
+func (u *HatenaBatchUsecase) recomputeBooks(ctx context.Context, recompute map[string]bool, result *HatenaBatchResult) {
+	if len(recompute) == 0 {
+		return
+	}
+
+	log.Printf("ブックマーク数が変わった記事に紐付く書籍のスコアを再計算中... (%d件)\n", len(recompute))
+	for bookID := range recompute {
+		if err := u.recomputer.RecomputeBook(ctx, bookID); err != nil {
+			log.Printf("Warning: スコア再計算エラー (BookID: %s): %v\n", bookID, err)
+			result.Errors++
+			continue
+		}
+		result.RecomputedBooks++
+	}
+}
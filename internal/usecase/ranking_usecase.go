@@ -19,9 +19,9 @@ func NewRankingUsecase(bookRepo repository.BookRepository) *RankingUsecase {
 }
 
 // GetRankings 総合ランキングを取得
-func (uc *RankingUsecase) GetRankings(ctx context.Context, rangeType string, limit int, offset int, categoryID string) (*dto.RankingResponse, error) {
+func (uc *RankingUsecase) GetRankings(ctx context.Context, rangeType string, limit int, offset int, categoryID string, source string) (*dto.RankingResponse, error) {
 	// リポジトリから書籍ランキングを取得
-	books, err := uc.bookRepo.GetRankings(ctx, rangeType, limit, offset, categoryID)
+	books, err := uc.bookRepo.GetRankings(ctx, rangeType, limit, offset, categoryID, source)
 	if err != nil {
 		return nil, err
 	}
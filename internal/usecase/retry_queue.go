@@ -0,0 +1,139 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"teckbook-compass-backend/internal/domain/entity"
+	"teckbook-compass-backend/internal/infrastructure/external"
+)
+
+// maxRetryQueueAttempts 延期を繰り返した記事をあきらめるまでの回数
+const maxRetryQueueAttempts = 5
+
+// loadRetryQueue 外部APIの障害・使用量の上限で処理を延期した記事を取得元から取得し直し、処理対象に加える
+// 保存済みの記事の紐付けを作り直すため、編集された記事として edited に追加する
+// 戻り値の2つ目は再処理する記事ID → 延期した記事（処理後にキューから削除する）
+func (u *BatchUsecase) loadRetryQueue(ctx context.Context, articles []*entity.SourceArticle, edited map[string]bool) ([]*entity.SourceArticle, map[string]*entity.RetryQueueItem) {
+	queued := make(map[string]*entity.RetryQueueItem)
+	if u.processing.RetryQueueLimit <= 0 {
+		return articles, queued
+	}
+
+	items, err := u.repo.GetRetryQueue(ctx, u.processing.RetryQueueLimit)
+	if err != nil {
+		log.Printf("Warning: 延期した記事の取得エラー: %v\n", err)
+		return articles, queued
+	}
+
+	included := make(map[string]bool, len(articles))
+	for _, article := range articles {
+		included[article.ID] = true
+	}
+
+	for _, item := range items {
+		if item.Attempts > maxRetryQueueAttempts {
+			u.logError(ctx, "retry_queue_exhausted", fmt.Errorf("deferred %d times: %s", item.Attempts, item.Reason), item.ArticleID)
+			u.deleteRetry(ctx, item)
+			continue
+		}
+
+		source := u.sourceByName(item.Source)
+		if source == nil {
+			u.deleteRetry(ctx, item)
+			continue
+		}
+
+		if !included[item.ArticleID] {
+			article, err := source.GetArticle(ctx, item.ArticleID)
+			if errors.Is(err, external.ErrArticleNotFound) {
+				u.deleteRetry(ctx, item)
+				continue
+			}
+			if _, ok := deferralReason(err); ok {
+				// 取得元が遮断中・上限に達したため、残りは次回に持ち越す
+				log.Printf("Warning: [%s] 遮断中または使用量の上限のため延期した記事の取得を中断します\n", item.Source)
+				break
+			}
+			if err != nil {
+				log.Printf("Warning: 延期した記事の取得エラー (ID: %s): %v\n", item.ArticleID, err)
+				continue
+			}
+			articles = append(articles, article)
+			included[article.ID] = true
+		}
+
+		edited[item.ArticleID] = true
+		queued[item.ArticleID] = item
+	}
+
+	if len(queued) > 0 {
+		log.Printf("延期した記事を再処理します: %d件\n", len(queued))
+	}
+	return articles, queued
+}
+
+// deferralReason 記事ごと延期すべきエラーかどうかと、その原因のクライアント名
+// 遮断中（ErrCircuitOpen）・1日の使用量の上限（ErrBudgetExceeded）・レート制限（ErrRateLimited）は時間をおけば回復するため延期する
+func deferralReason(err error) (string, bool) {
+	var circuitErr *external.CircuitOpenError
+	if errors.As(err, &circuitErr) {
+		return circuitErr.Name, true
+	}
+	var budgetErr *external.BudgetExceededError
+	if errors.As(err, &budgetErr) {
+		return budgetErr.Provider, true
+	}
+	var limitErr *external.RateLimitedError
+	if errors.As(err, &limitErr) {
+		return limitErr.Provider, true
+	}
+	return "", false
+}
+
+// deferArticle 遮断中・上限に達した外部APIのために処理しきれなかった記事を延期する
+func (u *BatchUsecase) deferArticle(ctx context.Context, article *entity.SourceArticle, reason string) {
+	item := &entity.RetryQueueItem{Source: article.Source, ArticleID: article.ID, Reason: reason}
+	if err := u.repo.EnqueueRetry(ctx, item); err != nil {
+		log.Printf("Warning: 記事の延期エラー (ID: %s): %v\n", article.ID, err)
+	}
+}
+
+// deleteRetry 延期した記事をキューから削除
+func (u *BatchUsecase) deleteRetry(ctx context.Context, item *entity.RetryQueueItem) {
+	if err := u.repo.DeleteRetry(ctx, item.Source, item.ArticleID); err != nil {
+		log.Printf("Warning: 延期した記事の削除エラー (ID: %s): %v\n", item.ArticleID, err)
+	}
+}
+
+// notifyDeferred 遮断・使用量の上限により延期した記事数をクライアントごとにまとめて1回だけ通知する
+func (u *BatchUsecase) notifyDeferred(deferred map[string]int) {
+	if len(deferred) == 0 {
+		return
+	}
+
+	names := make([]string, 0, len(deferred))
+	total := 0
+	for name, count := range deferred {
+		names = append(names, name)
+		total += count
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "外部APIの障害または1日の使用量の上限により%d件の記事の処理を延期しました（次回以降のバッチで再処理します）", total)
+	for _, name := range names {
+		fmt.Fprintf(&b, "\n• %s: %d件", name, deferred[name])
+	}
+
+	log.Printf("Warning: %s\n", b.String())
+	if u.slackClient != nil {
+		if err := u.slackClient.SendError("外部APIが利用できないため記事の処理を延期しました", b.String()); err != nil {
+			log.Printf("Warning: Slack通知エラー: %v\n", err)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"teckbook-compass-backend/internal/domain/entity"
+	"teckbook-compass-backend/internal/domain/repository"
+	"teckbook-compass-backend/internal/infrastructure/config"
+)
+
+// ScoreRecomputer 保存済みの記事-書籍の紐付けから書籍の日次スコアを作り直す
+// 記事の削除などで過去に加算したスコアを取り消す必要がある場合に使用する
+type ScoreRecomputer struct {
+	repo    repository.BatchRepository
+	scoring config.ScoringConfig
+}
+
+// NewScoreRecomputer ScoreRecomputerを生成
+func NewScoreRecomputer(repo repository.BatchRepository, scoring config.ScoringConfig) *ScoreRecomputer {
+	return &ScoreRecomputer{
+		repo:    repo,
+		scoring: scoring,
+	}
+}
+
+// RecomputeBook 書籍の日次スコアを、削除されていない記事からの言及のみで再計算して置き換える
+// 記事の投稿日ごとに集計し、現在の記事の人気度とスコア設定で計算する
+func (r *ScoreRecomputer) RecomputeBook(ctx context.Context, bookID string) error {
+	mentions, err := r.repo.GetBookMentionsForScoring(ctx, bookID)
+	if err != nil {
+		return fmt.Errorf("failed to get book mentions: %w", err)
+	}
+
+	daily := make(map[time.Time]*entity.BookScore)
+	for _, mention := range mentions {
+		weight, ok := mentionWeight(r.scoring, mention.Article.Source, mention.Origin, mention.Confidence, mention.Sentiment)
+		if !ok {
+			continue
+		}
+
+		date := mention.Article.PublishedAt.Truncate(24 * time.Hour)
+		score, exists := daily[date]
+		if !exists {
+			score = &entity.BookScore{BookID: bookID}
+			daily[date] = score
+		}
+		score.AddScore(mention.Article.Popularity(r.scoring.HatenaWeight), mention.Article.PublishedAt, weight)
+	}
+
+	scores := make([]*entity.BookScore, 0, len(daily))
+	for _, score := range daily {
+		scores = append(scores, score)
+	}
+	sort.Slice(scores, func(i, j int) bool {
+		return scores[i].LatestArticleDate.Before(scores[j].LatestArticleDate)
+	})
+
+	if err := r.repo.ReplaceBookScoresDaily(ctx, bookID, scores); err != nil {
+		return fmt.Errorf("failed to replace book scores: %w", err)
+	}
+	return nil
+}
+
+// RecomputeAll 日次スコアまたは紐付けがあるすべての書籍のスコアを再計算して置き換える（戻り値は再計算した書籍数）
+// 保存済みのスコアを加算の重複などから作り直す場合に使用する（書籍ごとのエラーはログに出力して続行する）
+func (r *ScoreRecomputer) RecomputeAll(ctx context.Context) (int, error) {
+	bookIDs, err := r.repo.GetBookIDsForScoring(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get book IDs for scoring: %w", err)
+	}
+
+	recomputed := 0
+	for _, bookID := range bookIDs {
+		if err := ctx.Err(); err != nil {
+			return recomputed, err
+		}
+		if err := r.RecomputeBook(ctx, bookID); err != nil {
+			log.Printf("Warning: スコア再計算エラー (BookID: %s): %v\n", bookID, err)
+			continue
+		}
+		recomputed++
+	}
+	return recomputed, nil
+}
+
+// mentionWeight 記事-書籍の紐付け1件に掛けるスコアの重みを返す
+// 確からしさが下限未満でスコアに加算しない場合は false を返す
+func mentionWeight(scoring config.ScoringConfig, source string, origin entity.MentionOrigin, confidence float64, sentiment entity.Sentiment) (float64, bool) {
+	// 確からしさが下限未満の紐付けはスコアに加算しない
+	if confidence < scoring.MinConfidence {
+		return 0, false
+	}
+
+	// 取得元ごとの重み
+	weight := scoring.SourceWeight(source)
+
+	// コメントでの言及は本文より低い重みで加算
+	if origin == entity.MentionOriginComment {
+		weight *= scoring.CommentWeight
+	}
+
+	// ネガティブな言及は設定に応じて減点
+	if sentiment == entity.SentimentNegative {
+		weight *= scoring.NegativeWeight
+	}
+
+	return weight, true
+}
@@ -0,0 +1,9 @@
+-- インデックスを削除
+DROP INDEX IF EXISTS idx_article_books_confidence;
+DROP INDEX IF EXISTS idx_article_books_source_type;
+
+-- プロヴェナンスカラムを削除
+ALTER TABLE article_books DROP COLUMN IF EXISTS snippet;
+ALTER TABLE article_books DROP COLUMN IF EXISTS matched_text;
+ALTER TABLE article_books DROP COLUMN IF EXISTS confidence;
+ALTER TABLE article_books DROP COLUMN IF EXISTS source_type;
@@ -0,0 +1,9 @@
+-- article_booksに抽出元の情報（プロヴェナンス）を追加
+ALTER TABLE article_books ADD COLUMN IF NOT EXISTS source_type VARCHAR(20);
+ALTER TABLE article_books ADD COLUMN IF NOT EXISTS confidence REAL;
+ALTER TABLE article_books ADD COLUMN IF NOT EXISTS matched_text TEXT;
+ALTER TABLE article_books ADD COLUMN IF NOT EXISTS snippet TEXT;
+
+-- 管理用の絞り込みに使うインデックス
+CREATE INDEX IF NOT EXISTS idx_article_books_source_type ON article_books(source_type);
+CREATE INDEX IF NOT EXISTS idx_article_books_confidence ON article_books(confidence);
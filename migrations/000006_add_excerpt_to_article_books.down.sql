@@ -0,0 +1,2 @@
+-- 抜粋カラムを削除
+ALTER TABLE article_books DROP COLUMN IF EXISTS excerpt;
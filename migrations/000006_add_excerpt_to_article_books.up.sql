@@ -0,0 +1,2 @@
+-- article_booksに書籍への言及箇所の抜粋（書籍詳細で表示）を追加
+ALTER TABLE article_books ADD COLUMN IF NOT EXISTS excerpt TEXT;
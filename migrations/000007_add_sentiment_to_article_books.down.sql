@@ -0,0 +1,6 @@
+-- インデックスを削除
+DROP INDEX IF EXISTS idx_article_books_book_id_sentiment;
+
+-- 極性カラムを削除
+ALTER TABLE article_books DROP COLUMN IF EXISTS sentiment_score;
+ALTER TABLE article_books DROP COLUMN IF EXISTS sentiment;
@@ -0,0 +1,6 @@
+-- article_booksに言及の極性（positive / neutral / negative）を追加
+ALTER TABLE article_books ADD COLUMN IF NOT EXISTS sentiment VARCHAR(10);
+ALTER TABLE article_books ADD COLUMN IF NOT EXISTS sentiment_score REAL;
+
+-- 書籍ごとの極性集計用インデックス
+CREATE INDEX IF NOT EXISTS idx_article_books_book_id_sentiment ON article_books(book_id, sentiment);
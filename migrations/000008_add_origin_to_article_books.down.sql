@@ -0,0 +1,10 @@
+-- コメント由来の紐付けを削除（本文由来のみ残す）
+DELETE FROM article_books WHERE origin <> 'body';
+
+DROP INDEX IF EXISTS idx_article_books_origin;
+
+-- 一意制約を元に戻す
+ALTER TABLE article_books DROP CONSTRAINT IF EXISTS article_books_article_id_book_id_origin_key;
+ALTER TABLE article_books ADD CONSTRAINT article_books_article_id_book_id_key UNIQUE (article_id, book_id);
+
+ALTER TABLE article_books DROP COLUMN IF EXISTS origin;
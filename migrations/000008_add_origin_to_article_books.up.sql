@@ -0,0 +1,8 @@
+-- article_booksに言及箇所（body: 記事本文 / comment: 記事へのコメント）を追加
+ALTER TABLE article_books ADD COLUMN IF NOT EXISTS origin VARCHAR(20) NOT NULL DEFAULT 'body';
+
+-- 同じ記事・書籍でも本文とコメントの言及を別々に保持する
+ALTER TABLE article_books DROP CONSTRAINT IF EXISTS article_books_article_id_book_id_key;
+ALTER TABLE article_books ADD CONSTRAINT article_books_article_id_book_id_origin_key UNIQUE (article_id, book_id, origin);
+
+CREATE INDEX IF NOT EXISTS idx_article_books_origin ON article_books(origin);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_articles_source;
+
+ALTER TABLE articles DROP COLUMN IF EXISTS source;
@@ -0,0 +1,4 @@
+-- articlesに取得元（qiita / zenn）を追加（既存の記事はすべてQiita由来）
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS source VARCHAR(20) NOT NULL DEFAULT 'qiita';
+
+CREATE INDEX IF NOT EXISTS idx_articles_source ON articles(source);
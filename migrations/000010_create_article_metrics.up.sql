@@ -0,0 +1,12 @@
+-- article_metrics（記事の外部指標: はてなブックマーク数など）
+CREATE TABLE IF NOT EXISTS article_metrics (
+    article_id VARCHAR(50) PRIMARY KEY,
+    hatena_bookmarks INT NOT NULL DEFAULT 0,
+    hatena_fetched_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (article_id) REFERENCES articles(id) ON DELETE CASCADE
+);
+
+-- 未取得・取得日時が古い記事から更新するためのインデックス
+CREATE INDEX IF NOT EXISTS idx_article_metrics_hatena_fetched_at ON article_metrics(hatena_fetched_at);
@@ -0,0 +1,2 @@
+-- booksに書籍メタデータのフィールドごとの取得元（{"title": "rakuten", "overview": "openbd", ...}）を追加
+ALTER TABLE books ADD COLUMN IF NOT EXISTS metadata_sources JSONB;
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_books_metadata_refreshed_at;
+
+ALTER TABLE books DROP COLUMN IF EXISTS metadata_refreshed_at;
+ALTER TABLE books DROP COLUMN IF EXISTS rakuten_availability;
@@ -0,0 +1,5 @@
+-- 楽天の書籍情報（評価・価格・在庫・表紙）の定期再取得用カラムを追加
+ALTER TABLE books ADD COLUMN IF NOT EXISTS rakuten_availability VARCHAR(2);
+ALTER TABLE books ADD COLUMN IF NOT EXISTS metadata_refreshed_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_books_metadata_refreshed_at ON books(metadata_refreshed_at);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_articles_updated_at;
+
+ALTER TABLE articles DROP COLUMN IF EXISTS deleted_at;
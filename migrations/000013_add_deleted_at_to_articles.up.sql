@@ -0,0 +1,5 @@
+-- 取得元で削除・非公開化された記事の検出日時
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+
+-- 記事情報の再取得で更新日時の古い記事から処理するためのインデックス
+CREATE INDEX IF NOT EXISTS idx_articles_updated_at ON articles(updated_at);
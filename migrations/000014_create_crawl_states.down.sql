@@ -0,0 +1,5 @@
+-- トリガーを削除
+DROP TRIGGER IF EXISTS update_crawl_states_updated_at ON crawl_states;
+
+-- テーブルを削除
+DROP TABLE IF EXISTS crawl_states;
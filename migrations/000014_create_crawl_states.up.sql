@@ -0,0 +1,19 @@
+-- crawl_states（過去記事取得の取得元・クエリごとの進捗）
+-- batch_statuses.next_page（全クエリ共通のページ番号）に代わり、クエリごとに取得期間とページを管理する
+CREATE TABLE IF NOT EXISTS crawl_states (
+    source VARCHAR(20) NOT NULL,
+    query TEXT NOT NULL,
+    window_start DATE,
+    window_end DATE,
+    window_days INT NOT NULL DEFAULT 0,
+    page INT NOT NULL DEFAULT 1,
+    completed_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (source, query)
+);
+
+CREATE TRIGGER update_crawl_states_updated_at
+    BEFORE UPDATE ON crawl_states
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
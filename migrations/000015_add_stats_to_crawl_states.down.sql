@@ -0,0 +1,6 @@
+ALTER TABLE crawl_states
+    DROP COLUMN IF EXISTS last_new_count,
+    DROP COLUMN IF EXISTS new_count,
+    DROP COLUMN IF EXISTS fetched_count,
+    DROP COLUMN IF EXISTS runs,
+    DROP COLUMN IF EXISTS last_new_fetched_at;
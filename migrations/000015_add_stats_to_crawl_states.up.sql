@@ -0,0 +1,8 @@
+-- crawl_states に最新記事の取得日時と取得件数の実績を追加
+-- クエリごとに最新記事の取得基準日時を持ち、実績の多いクエリから優先して取得する
+ALTER TABLE crawl_states
+    ADD COLUMN IF NOT EXISTS last_new_fetched_at TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS runs INT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS fetched_count INT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS new_count INT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS last_new_count INT NOT NULL DEFAULT 0;
@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_search_query_runs_source_query;
+DROP TABLE IF EXISTS search_query_runs;
+
+-- トリガーを削除
+DROP TRIGGER IF EXISTS update_search_queries_updated_at ON search_queries;
+
+-- テーブルを削除
+DROP TABLE IF EXISTS search_queries;
@@ -0,0 +1,53 @@
+-- search_queries（取得元ごとの検索クエリ）
+-- コードに固定していた検索クエリを移し、再デプロイなしで追加・無効化・優先度の変更をできるようにする
+CREATE TABLE IF NOT EXISTS search_queries (
+    id SERIAL PRIMARY KEY,
+    source VARCHAR(20) NOT NULL,
+    query TEXT NOT NULL,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    priority INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (source, query)
+);
+
+CREATE TRIGGER update_search_queries_updated_at
+    BEFORE UPDATE ON search_queries
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- これまでの固定クエリを登録
+INSERT INTO search_queries (source, query) VALUES
+    ('qiita', '技術書'),
+    ('qiita', '書籍'),
+    ('qiita', '本の紹介'),
+    ('qiita', 'おすすめ本'),
+    ('qiita', '読んだ本'),
+    ('qiita', '入門書'),
+    ('qiita', '書評'),
+    ('qiita', 'レビュー'),
+    ('qiita', '読書'),
+    ('zenn', 'book'),
+    ('zenn', '技術書'),
+    ('zenn', '読書'),
+    ('zenn', '書評'),
+    ('zenn', '読書メモ')
+ON CONFLICT (source, query) DO NOTHING;
+
+-- search_query_runs（検索クエリごとの実行結果）
+-- どのクエリが実際に書籍の紹介記事を見つけているかを確認するために記録する
+CREATE TABLE IF NOT EXISTS search_query_runs (
+    id BIGSERIAL PRIMARY KEY,
+    source VARCHAR(20) NOT NULL,
+    query TEXT NOT NULL,
+    fetch_mode VARCHAR(20) NOT NULL,
+    fetched INT NOT NULL DEFAULT 0,
+    new_count INT NOT NULL DEFAULT 0,
+    duplicates INT NOT NULL DEFAULT 0,
+    articles_processed INT NOT NULL DEFAULT 0,
+    articles_with_books INT NOT NULL DEFAULT 0,
+    books_linked INT NOT NULL DEFAULT 0,
+    run_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_search_query_runs_source_query ON search_query_runs(source, query, run_at DESC);
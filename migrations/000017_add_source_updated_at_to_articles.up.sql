@@ -0,0 +1,3 @@
+-- 取得元での記事の更新日時（articles.updated_at はDBの更新日時のため別に持つ）
+-- 前回の取得以降に編集された記事を判定し、書籍の抽出をやり直すために使用する
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS source_updated_at TIMESTAMP;
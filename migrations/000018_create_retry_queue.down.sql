@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS update_retry_queue_updated_at ON retry_queue;
+DROP INDEX IF EXISTS idx_retry_queue_updated_at;
+DROP TABLE IF EXISTS retry_queue;
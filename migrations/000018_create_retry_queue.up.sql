@@ -0,0 +1,18 @@
+-- retry_queue（外部APIの障害で処理を延期した記事）
+-- サーキットブレーカーが遮断中のプロバイダで書籍を調べられなかった記事を登録し、次回以降のバッチで処理し直す
+CREATE TABLE IF NOT EXISTS retry_queue (
+    source VARCHAR(20) NOT NULL,
+    article_id VARCHAR(255) NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    attempts INT NOT NULL DEFAULT 1,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (source, article_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_retry_queue_updated_at ON retry_queue(updated_at);
+
+CREATE TRIGGER update_retry_queue_updated_at
+    BEFORE UPDATE ON retry_queue
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
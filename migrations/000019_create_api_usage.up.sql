@@ -0,0 +1,10 @@
+-- api_usage（外部APIの日ごとの呼び出し回数）
+-- プロバイダごとの1日の使用量の上限（API_DAILY_BUDGETS）の判定と、使用量の確認に使う
+CREATE TABLE IF NOT EXISTS api_usage (
+    usage_date DATE NOT NULL,
+    provider VARCHAR(50) NOT NULL,
+    endpoint VARCHAR(100) NOT NULL,
+    calls INT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (usage_date, provider, endpoint)
+);
@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS update_book_lookups_updated_at ON book_lookups;
+DROP TABLE IF EXISTS book_lookups;
@@ -0,0 +1,19 @@
+-- book_lookups（書籍メタデータの検索結果のキャッシュ）
+-- 記事から抽出したISBN・タイトル（正規化済み）ごとに、見つかった書籍のISBNか見つからなかったことを記録する
+-- 見つからなかった検索は next_check_at まで外部APIに問い合わせない（見つからないたびに間隔を倍にする）
+CREATE TABLE IF NOT EXISTS book_lookups (
+    lookup_type VARCHAR(10) NOT NULL,
+    lookup_key TEXT NOT NULL,
+    isbn VARCHAR(13),
+    misses INT NOT NULL DEFAULT 0,
+    checked_at TIMESTAMP NOT NULL,
+    next_check_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (lookup_type, lookup_key)
+);
+
+CREATE TRIGGER update_book_lookups_updated_at
+    BEFORE UPDATE ON book_lookups
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS batch_checkpoint_articles;
+DROP TRIGGER IF EXISTS update_batch_checkpoints_updated_at ON batch_checkpoints;
+DROP TABLE IF EXISTS batch_checkpoints;
@@ -0,0 +1,30 @@
+-- batch_checkpoints（記事取得バッチの進捗）
+-- 中断したバッチを取得し直さずに再開するため、取得した記事・集計途中のスコアを保存する（完了したら削除）
+-- status: processing（記事を処理中）/ scores_saved（スコアを book_scores_daily に加算済み）
+CREATE TABLE IF NOT EXISTS batch_checkpoints (
+    id BIGSERIAL PRIMARY KEY,
+    status VARCHAR(20) NOT NULL DEFAULT 'processing',
+    fetch_mode VARCHAR(100) NOT NULL DEFAULT '',
+    sources JSONB NOT NULL DEFAULT '[]',
+    scores JSONB NOT NULL DEFAULT '[]',
+    recompute_book_ids JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TRIGGER update_batch_checkpoints_updated_at
+    BEFORE UPDATE ON batch_checkpoints
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- batch_checkpoint_articles（進捗に含まれる記事と処理済みかどうか）
+CREATE TABLE IF NOT EXISTS batch_checkpoint_articles (
+    checkpoint_id BIGINT NOT NULL REFERENCES batch_checkpoints(id) ON DELETE CASCADE,
+    article_id VARCHAR(255) NOT NULL,
+    source VARCHAR(20) NOT NULL,
+    position INT NOT NULL,
+    edited BOOLEAN NOT NULL DEFAULT FALSE,
+    retried BOOLEAN NOT NULL DEFAULT FALSE,
+    processed BOOLEAN NOT NULL DEFAULT FALSE,
+    PRIMARY KEY (checkpoint_id, article_id)
+);
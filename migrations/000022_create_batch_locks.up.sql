@@ -0,0 +1,12 @@
+-- batch_locks（バッチの排他制御のロック保持者）
+-- 排他制御はバッチの種類ごとのアドバイザリロック（pg_try_advisory_lock）で行い、
+-- このテーブルには保持者の情報とリース期限（ハートビートで延長）を記録する
+CREATE TABLE IF NOT EXISTS batch_locks (
+    batch_type VARCHAR(50) PRIMARY KEY,
+    holder VARCHAR(255) NOT NULL,
+    pid INT NOT NULL,
+    backend_pid INT NOT NULL,
+    acquired_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    heartbeat_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP NOT NULL
+);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_batch_runs_type_started_at;
+DROP TABLE IF EXISTS batch_runs;
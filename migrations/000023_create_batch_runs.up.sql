@@ -0,0 +1,19 @@
+-- batch_runs（バッチの実行履歴）
+-- 実行ごとの種類・パラメータ・件数・検索クエリごとの取得実績・結果を記録し、月ごとの件数や失敗の傾向の確認に使う
+-- outcome: running（実行中、終了を記録できずに停止した場合も残る）/ succeeded / partial（期限で途中終了）/ failed
+-- counters: バッチの種類ごとの件数（{"processed_articles": 120, "new_books": 8, ...}）
+CREATE TABLE IF NOT EXISTS batch_runs (
+    id BIGSERIAL PRIMARY KEY,
+    batch_type VARCHAR(50) NOT NULL,
+    mode VARCHAR(100) NOT NULL DEFAULT '',
+    params JSONB NOT NULL DEFAULT '{}',
+    counters JSONB NOT NULL DEFAULT '{}',
+    query_stats JSONB NOT NULL DEFAULT '[]',
+    outcome VARCHAR(20) NOT NULL DEFAULT 'running',
+    error_summary TEXT NOT NULL DEFAULT '',
+    started_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    finished_at TIMESTAMP,
+    duration_ms BIGINT NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS idx_batch_runs_type_started_at ON batch_runs(batch_type, started_at DESC);
@@ -0,0 +1,18 @@
+UPDATE batch_checkpoint_articles SET article_id = substring(article_id FROM 6) WHERE source = 'zenn' AND article_id LIKE 'zenn:%';
+UPDATE retry_queue SET article_id = substring(article_id FROM 6) WHERE source = 'zenn' AND article_id LIKE 'zenn:%';
+UPDATE articles SET id = substring(id FROM 6) WHERE source = 'zenn' AND id LIKE 'zenn:%';
+
+ALTER TABLE article_metrics DROP CONSTRAINT IF EXISTS article_metrics_article_id_fkey;
+ALTER TABLE article_metrics ADD CONSTRAINT article_metrics_article_id_fkey
+    FOREIGN KEY (article_id) REFERENCES articles(id) ON DELETE CASCADE;
+ALTER TABLE article_tags DROP CONSTRAINT IF EXISTS article_tags_article_id_fkey;
+ALTER TABLE article_tags ADD CONSTRAINT article_tags_article_id_fkey
+    FOREIGN KEY (article_id) REFERENCES articles(id) ON DELETE CASCADE;
+ALTER TABLE article_books DROP CONSTRAINT IF EXISTS article_books_article_id_fkey;
+ALTER TABLE article_books ADD CONSTRAINT article_books_article_id_fkey
+    FOREIGN KEY (article_id) REFERENCES articles(id) ON DELETE CASCADE;
+
+ALTER TABLE article_metrics ALTER COLUMN article_id TYPE VARCHAR(50);
+ALTER TABLE article_tags ALTER COLUMN article_id TYPE VARCHAR(50);
+ALTER TABLE article_books ALTER COLUMN article_id TYPE VARCHAR(50);
+ALTER TABLE articles ALTER COLUMN id TYPE VARCHAR(50);
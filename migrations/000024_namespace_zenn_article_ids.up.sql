@@ -0,0 +1,24 @@
+-- Zennの記事IDに取得元の接頭辞を付ける（"zenn:<slug>"）
+-- articles.id は取得元をまたいで一意である必要があるが、Zennのslugは QiitaのIDや他の記事と重なりうるため
+-- （既に重なって上書きされた記事は元に戻せないため、次回以降の取得で保存し直される）
+
+-- 接頭辞の分だけ長くなるため記事IDの列を広げる
+ALTER TABLE articles ALTER COLUMN id TYPE VARCHAR(255);
+ALTER TABLE article_books ALTER COLUMN article_id TYPE VARCHAR(255);
+ALTER TABLE article_tags ALTER COLUMN article_id TYPE VARCHAR(255);
+ALTER TABLE article_metrics ALTER COLUMN article_id TYPE VARCHAR(255);
+
+-- 記事IDの変更を紐付け・タグ・指標に反映する
+ALTER TABLE article_books DROP CONSTRAINT IF EXISTS article_books_article_id_fkey;
+ALTER TABLE article_books ADD CONSTRAINT article_books_article_id_fkey
+    FOREIGN KEY (article_id) REFERENCES articles(id) ON DELETE CASCADE ON UPDATE CASCADE;
+ALTER TABLE article_tags DROP CONSTRAINT IF EXISTS article_tags_article_id_fkey;
+ALTER TABLE article_tags ADD CONSTRAINT article_tags_article_id_fkey
+    FOREIGN KEY (article_id) REFERENCES articles(id) ON DELETE CASCADE ON UPDATE CASCADE;
+ALTER TABLE article_metrics DROP CONSTRAINT IF EXISTS article_metrics_article_id_fkey;
+ALTER TABLE article_metrics ADD CONSTRAINT article_metrics_article_id_fkey
+    FOREIGN KEY (article_id) REFERENCES articles(id) ON DELETE CASCADE ON UPDATE CASCADE;
+
+UPDATE articles SET id = 'zenn:' || id WHERE source = 'zenn' AND id NOT LIKE 'zenn:%';
+UPDATE retry_queue SET article_id = 'zenn:' || article_id WHERE source = 'zenn' AND article_id NOT LIKE 'zenn:%';
+UPDATE batch_checkpoint_articles SET article_id = 'zenn:' || article_id WHERE source = 'zenn' AND article_id NOT LIKE 'zenn:%';
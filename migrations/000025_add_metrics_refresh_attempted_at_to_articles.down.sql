@@ -0,0 +1,2 @@
+-- 記事情報の再取得に失敗した日時のカラムを削除
+ALTER TABLE articles DROP COLUMN IF EXISTS metrics_refresh_attempted_at;
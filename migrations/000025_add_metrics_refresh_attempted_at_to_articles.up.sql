@@ -0,0 +1,4 @@
+-- articlesテーブルに記事情報の再取得に失敗した日時を追加
+-- 取得元のエラー（5xx・タイムアウト・サーキットブレーカーの遮断）では updated_at が変わらないため、
+-- 失敗した日時を記録して次の再取得を遅らせ、失敗し続ける記事が毎回先頭でAPIの予算を使い切らないようにする
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS metrics_refresh_attempted_at TIMESTAMP;